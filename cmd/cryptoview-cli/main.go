@@ -0,0 +1,14 @@
+// Command cryptoview-cli runs the quote, watch and serve commands without
+// linking the Fyne UI, so it builds with CGO_ENABLED=0 and starts on hosts
+// without OpenGL or X11 libraries.
+package main
+
+import (
+	"os"
+
+	"cryptoview/internal/cli"
+)
+
+func main() {
+	os.Exit(cli.Run(os.Args[1:], os.Stdout, os.Stderr))
+}
@@ -1,12 +1,24 @@
 package main
 
 import (
+	"fmt"
+	"os"
+
+	"cryptoview/internal/cli"
 	"cryptoview/internal/ui"
 	"fyne.io/fyne/v2/app"
 )
 
 func main() {
-	a := app.New()
-	w := ui.BuildMainWindow(a, nil)
+	if len(os.Args) > 1 && cli.IsCommand(os.Args[1]) {
+		os.Exit(cli.Run(os.Args[1:], os.Stdout, os.Stderr))
+	}
+	source, err := cli.ParseSourceFlags(os.Args[1:])
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "cryptoview: %v\n\nRun 'cryptoview help' for usage.\n", err)
+		os.Exit(cli.ExitUsage)
+	}
+	a := app.NewWithID("io.github.cryptoview")
+	w := ui.BuildMainWindowWithSource(a, nil, source)
 	w.ShowAndRun()
 }
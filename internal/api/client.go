@@ -1,19 +1,95 @@
 package api
 
 import (
+	"context"
 	"net/http"
+	"strings"
+	"sync"
 	"time"
 )
 
-const defaultTimeout = 10 * time.Second
+const (
+	defaultTimeout   = 10 * time.Second
+	proAPIKeyHeader  = "x-cg-pro-api-key"
+	demoAPIKeyHeader = "x-cg-demo-api-key"
+)
 
 type Client struct {
 	httpClient *http.Client
 	baseURL    string
+	proBaseURL string
+
+	mu     sync.RWMutex
+	apiKey string
+	demo   bool
 }
 
 func NewClient(timeout time.Duration) *Client {
-	return newClient("https://api.coingecko.com/api/v3", timeout)
+	c := newClient("https://api.coingecko.com/api/v3", timeout)
+	c.proBaseURL = "https://pro-api.coingecko.com/api/v3"
+	return c
+}
+
+// SetAPIKey switches the client to the paid API; an empty key goes back to
+// the public one. Free Demo keys are told apart on first use, see get.
+func (c *Client) SetAPIKey(key string) {
+	c.mu.Lock()
+	c.apiKey = strings.TrimSpace(key)
+	c.demo = false
+	c.mu.Unlock()
+}
+
+// get sends a GET for path. A keyed request goes to the pro API first; when
+// pro-api turns the key away it is retried as a Demo key on the public API,
+// and the client remembers the plan that worked. A key neither API accepts
+// comes back as a StatusError matching ErrAPIKeyRejected.
+func (c *Client) get(ctx context.Context, path string) (*http.Response, error) {
+	c.mu.RLock()
+	key, demo := c.apiKey, c.demo
+	c.mu.RUnlock()
+	if key == "" || c.proBaseURL == "" {
+		return c.do(ctx, c.baseURL+path, "", "")
+	}
+	if !demo {
+		resp, err := c.do(ctx, c.proBaseURL+path, proAPIKeyHeader, key)
+		if err != nil || !keyRefused(resp.StatusCode) {
+			return resp, err
+		}
+		resp.Body.Close()
+	}
+	resp, err := c.do(ctx, c.baseURL+path, demoAPIKeyHeader, key)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode == http.StatusUnauthorized || resp.StatusCode == http.StatusForbidden {
+		resp.Body.Close()
+		return nil, &StatusError{StatusCode: resp.StatusCode, KeyRejected: true}
+	}
+	if !demo && resp.StatusCode == http.StatusOK {
+		c.mu.Lock()
+		if c.apiKey == key {
+			c.demo = true
+		}
+		c.mu.Unlock()
+	}
+	return resp, nil
+}
+
+func (c *Client) do(ctx context.Context, url, header, key string) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+	if key != "" {
+		req.Header.Set(header, key)
+	}
+	return c.httpClient.Do(req)
+}
+
+// keyRefused matches how pro-api answers a key it does not serve; Demo keys
+// get a 400 with error code 10011, unknown keys a 401.
+func keyRefused(status int) bool {
+	return status == http.StatusBadRequest || status == http.StatusUnauthorized || status == http.StatusForbidden
 }
 
 func newClient(baseURL string, timeout time.Duration) *Client {
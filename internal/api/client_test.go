@@ -45,7 +45,7 @@ func TestClient_TimeoutRespected(t *testing.T) {
 
 	client := newClient(srv.URL, 10*time.Millisecond)
 	ctx := context.Background()
-	_, err := client.GetMarkets(ctx, "usd")
+	_, err := client.GetMarkets(ctx, "usd", nil)
 	if err == nil {
 		t.Fatal("expected timeout error")
 	}
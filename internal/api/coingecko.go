@@ -3,6 +3,7 @@ package api
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"net/http"
 	"net/url"
@@ -15,7 +16,7 @@ import (
 
 const trackedCoinIDs = "bitcoin,ethereum,the-open-network,solana,dogecoin,ripple,litecoin"
 
-func (c *Client) GetMarkets(ctx context.Context, fiat string) ([]model.CoinGeckoMarket, error) {
+func (c *Client) GetMarkets(ctx context.Context, fiat string, ids []string) ([]model.CoinGeckoMarket, error) {
 	normalized, err := normalizeFiatCurrency(fiat)
 	if err != nil {
 		return nil, err
@@ -23,50 +24,148 @@ func (c *Client) GetMarkets(ctx context.Context, fiat string) ([]model.CoinGecko
 
 	params := url.Values{}
 	params.Set("vs_currency", normalized)
-	params.Set("ids", trackedCoinIDs)
+	params.Set("ids", joinCoinIDs(ids))
 	params.Set("order", "market_cap_desc")
 	params.Set("sparkline", "false")
 	params.Set("price_change_percentage", "24h")
 
-	endpoint := fmt.Sprintf("%s/coins/markets?%s", c.baseURL, params.Encode())
-	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
+	resp, err := c.get(ctx, "/coins/markets?"+params.Encode())
 	if err != nil {
 		return nil, err
 	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, statusErrorFromResponse(resp)
+	}
 
-	resp, err := c.httpClient.Do(req)
+	var markets []model.CoinGeckoMarket
+	if err := json.NewDecoder(resp.Body).Decode(&markets); err != nil {
+		return nil, err
+	}
+
+	return markets, nil
+}
+
+func (c *Client) GetMarketChart(ctx context.Context, id, fiat string, days int) ([]model.PricePoint, error) {
+	normalized, err := normalizeFiatCurrency(fiat)
+	if err != nil {
+		return nil, err
+	}
+	id = strings.ToLower(strings.TrimSpace(id))
+	if id == "" {
+		return nil, fmt.Errorf("coin id is required")
+	}
+	if days <= 0 {
+		days = 1
+	}
+
+	params := url.Values{}
+	params.Set("vs_currency", normalized)
+	params.Set("days", strconv.Itoa(days))
+
+	resp, err := c.get(ctx, fmt.Sprintf("/coins/%s/market_chart?%s", url.PathEscape(id), params.Encode()))
 	if err != nil {
 		return nil, err
 	}
 	defer resp.Body.Close()
 
 	if resp.StatusCode != http.StatusOK {
-		statusErr := &StatusError{StatusCode: resp.StatusCode}
-		if retryAfter := strings.TrimSpace(resp.Header.Get("Retry-After")); retryAfter != "" {
-			if secs, err := strconv.Atoi(retryAfter); err == nil && secs > 0 {
-				statusErr.RetryAfter = time.Duration(secs) * time.Second
-			} else if when, err := http.ParseTime(retryAfter); err == nil {
-				if d := time.Until(when); d > 0 {
-					statusErr.RetryAfter = d
-				}
-			}
+		return nil, statusErrorFromResponse(resp)
+	}
+
+	var chart model.CoinGeckoMarketChart
+	if err := json.NewDecoder(resp.Body).Decode(&chart); err != nil {
+		return nil, err
+	}
+
+	return chart.PricePoints(), nil
+}
+
+// GetSimplePrices quotes ids in several fiats with one request. Each coin maps
+// to the raw CoinGecko fields, e.g. "usd", "eur", "usd_24h_change" and
+// "last_updated_at".
+func (c *Client) GetSimplePrices(ctx context.Context, ids []string, fiats []string) (map[string]map[string]float64, error) {
+	vs := make([]string, 0, len(fiats))
+	for _, fiat := range fiats {
+		normalized, err := normalizeFiatCurrency(fiat)
+		if err != nil {
+			return nil, err
 		}
-		return nil, statusErr
+		vs = append(vs, normalized)
+	}
+	if len(vs) == 0 {
+		return nil, fmt.Errorf("at least one fiat currency is required")
 	}
 
-	var markets []model.CoinGeckoMarket
-	if err := json.NewDecoder(resp.Body).Decode(&markets); err != nil {
+	params := url.Values{}
+	params.Set("ids", joinCoinIDs(ids))
+	params.Set("vs_currencies", strings.Join(vs, ","))
+	params.Set("include_24hr_change", "true")
+	params.Set("include_24hr_vol", "true")
+	params.Set("include_last_updated_at", "true")
+
+	resp, err := c.get(ctx, "/simple/price?"+params.Encode())
+	if err != nil {
 		return nil, err
 	}
+	defer resp.Body.Close()
 
-	return markets, nil
+	if resp.StatusCode != http.StatusOK {
+		return nil, statusErrorFromResponse(resp)
+	}
+
+	var prices map[string]map[string]float64
+	if err := json.NewDecoder(resp.Body).Decode(&prices); err != nil {
+		return nil, err
+	}
+	return prices, nil
+}
+
+func statusErrorFromResponse(resp *http.Response) *StatusError {
+	statusErr := &StatusError{StatusCode: resp.StatusCode}
+	if retryAfter := strings.TrimSpace(resp.Header.Get("Retry-After")); retryAfter != "" {
+		if secs, err := strconv.Atoi(retryAfter); err == nil && secs > 0 {
+			statusErr.RetryAfter = time.Duration(secs) * time.Second
+		} else if when, err := http.ParseTime(retryAfter); err == nil {
+			if d := time.Until(when); d > 0 {
+				statusErr.RetryAfter = d
+			}
+		}
+	}
+	return statusErr
+}
+
+func joinCoinIDs(ids []string) string {
+	cleaned := make([]string, 0, len(ids))
+	for _, id := range ids {
+		id = strings.ToLower(strings.TrimSpace(id))
+		if id != "" {
+			cleaned = append(cleaned, id)
+		}
+	}
+	if len(cleaned) == 0 {
+		return trackedCoinIDs
+	}
+	return strings.Join(cleaned, ",")
+}
+
+var ErrUnsupportedFiat = errors.New("unsupported fiat currency")
+
+var supportedVsCurrencies = map[string]bool{
+	"usd": true, "eur": true, "rub": true, "gbp": true, "jpy": true, "chf": true,
+	"cny": true, "try": true, "uah": true, "pln": true, "cad": true, "aud": true,
+	"inr": true, "brl": true, "krw": true, "btc": true, "eth": true, "sats": true,
+}
+
+func SupportsFiat(fiat string) bool {
+	return supportedVsCurrencies[strings.ToLower(strings.TrimSpace(fiat))]
 }
 
 func normalizeFiatCurrency(fiat string) (string, error) {
-	switch strings.ToLower(strings.TrimSpace(fiat)) {
-	case "usd", "eur", "rub":
-		return strings.ToLower(strings.TrimSpace(fiat)), nil
-	default:
-		return "", fmt.Errorf("unsupported fiat currency: %s", fiat)
+	normalized := strings.ToLower(strings.TrimSpace(fiat))
+	if !supportedVsCurrencies[normalized] {
+		return "", fmt.Errorf("%w: %s", ErrUnsupportedFiat, fiat)
 	}
+	return normalized, nil
 }
@@ -2,6 +2,7 @@ package api
 
 import (
 	"context"
+	"errors"
 	"net/http"
 	"net/http/httptest"
 	"strings"
@@ -29,7 +30,7 @@ func TestGetMarketsBuildsQueryAndDecodes(t *testing.T) {
 	defer srv.Close()
 
 	client := newClient(srv.URL, time.Second)
-	markets, err := client.GetMarkets(context.Background(), "USD")
+	markets, err := client.GetMarkets(context.Background(), "USD", nil)
 	if err != nil {
 		t.Fatalf("unexpected error: %v", err)
 	}
@@ -41,6 +42,22 @@ func TestGetMarketsBuildsQueryAndDecodes(t *testing.T) {
 	}
 }
 
+func TestGetMarketsUsesRequestedIDs(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if got := r.URL.Query().Get("ids"); got != "cardano,avalanche-2" {
+			t.Fatalf("unexpected ids: %s", got)
+		}
+		w.WriteHeader(http.StatusOK)
+		_, _ = w.Write([]byte(`[]`))
+	}))
+	defer srv.Close()
+
+	client := newClient(srv.URL, time.Second)
+	if _, err := client.GetMarkets(context.Background(), "usd", []string{" Cardano ", "", "avalanche-2"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
 func TestGetMarketsStatusError(t *testing.T) {
 	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		w.WriteHeader(http.StatusTooManyRequests)
@@ -48,12 +65,40 @@ func TestGetMarketsStatusError(t *testing.T) {
 	defer srv.Close()
 
 	client := newClient(srv.URL, time.Second)
-	_, err := client.GetMarkets(context.Background(), "usd")
+	_, err := client.GetMarkets(context.Background(), "usd", nil)
 	if err == nil {
 		t.Fatal("expected error for non-200 status")
 	}
 }
 
+func TestGetSimplePricesQuotesSeveralFiats(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/simple/price" {
+			t.Fatalf("unexpected path: %s", r.URL.Path)
+		}
+		if got := r.URL.Query().Get("vs_currencies"); got != "usd,eur" {
+			t.Fatalf("unexpected vs_currencies: %s", got)
+		}
+		if got := r.URL.Query().Get("include_last_updated_at"); got != "true" {
+			t.Fatalf("unexpected include_last_updated_at: %s", got)
+		}
+		_, _ = w.Write([]byte(`{"bitcoin":{"usd":100,"eur":92,"usd_24h_change":1.5,"last_updated_at":1700000000}}`))
+	}))
+	defer srv.Close()
+
+	client := newClient(srv.URL, time.Second)
+	prices, err := client.GetSimplePrices(context.Background(), []string{"bitcoin"}, []string{"USD", "EUR"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got := prices["bitcoin"]; got["usd"] != 100 || got["eur"] != 92 || got["usd_24h_change"] != 1.5 {
+		t.Fatalf("unexpected prices %+v", prices)
+	}
+	if _, err := client.GetSimplePrices(context.Background(), []string{"bitcoin"}, []string{"XYZ"}); !errors.Is(err, ErrUnsupportedFiat) {
+		t.Fatalf("expected ErrUnsupportedFiat, got %v", err)
+	}
+}
+
 func TestGetMarketsUnsupportedCurrency(t *testing.T) {
 	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		w.WriteHeader(http.StatusOK)
@@ -62,9 +107,9 @@ func TestGetMarketsUnsupportedCurrency(t *testing.T) {
 	defer srv.Close()
 
 	client := newClient(srv.URL, time.Second)
-	_, err := client.GetMarkets(context.Background(), "GBP")
-	if err == nil {
-		t.Fatal("expected error for unsupported currency GBP")
+	_, err := client.GetMarkets(context.Background(), "XYZ", nil)
+	if !errors.Is(err, ErrUnsupportedFiat) {
+		t.Fatalf("expected ErrUnsupportedFiat for XYZ, got %v", err)
 	}
 	if !strings.Contains(err.Error(), "unsupported") {
 		t.Fatalf("expected unsupported currency message, got %v", err)
@@ -82,8 +127,122 @@ func TestGetMarketsContextCancel(t *testing.T) {
 	client := newClient(srv.URL, time.Second)
 	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
 	defer cancel()
-	_, err := client.GetMarkets(ctx, "usd")
+	_, err := client.GetMarkets(ctx, "usd", nil)
 	if err == nil {
 		t.Fatal("expected timeout/cancel error")
 	}
 }
+
+func TestGetMarketChartBuildsQueryAndDecodes(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/coins/the-open-network/market_chart" {
+			t.Fatalf("unexpected path: %s", r.URL.Path)
+		}
+		if got := r.URL.Query().Get("vs_currency"); got != "eur" {
+			t.Fatalf("unexpected vs_currency: %s", got)
+		}
+		if got := r.URL.Query().Get("days"); got != "7" {
+			t.Fatalf("unexpected days: %s", got)
+		}
+		w.WriteHeader(http.StatusOK)
+		_, _ = w.Write([]byte(`{"prices":[[1700000000000,2.5],[1700003600000,2.6]],"total_volumes":[]}`))
+	}))
+	defer srv.Close()
+
+	client := newClient(srv.URL, time.Second)
+	points, err := client.GetMarketChart(context.Background(), "the-open-network", "EUR", 7)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(points) != 2 || points[1].Price != 2.6 {
+		t.Fatalf("unexpected points: %+v", points)
+	}
+}
+
+func TestGetMarketChartStatusError(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Retry-After", "30")
+		w.WriteHeader(http.StatusTooManyRequests)
+	}))
+	defer srv.Close()
+
+	client := newClient(srv.URL, time.Second)
+	_, err := client.GetMarketChart(context.Background(), "bitcoin", "usd", 1)
+	statusErr, ok := err.(*StatusError)
+	if !ok || statusErr.StatusCode != http.StatusTooManyRequests || statusErr.RetryAfter != 30*time.Second {
+		t.Fatalf("expected 429 status error with retry-after, got %v", err)
+	}
+}
+
+func TestAPIKeySwitchesToProBaseURL(t *testing.T) {
+	var gotKey string
+	pro := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotKey = r.Header.Get("x-cg-pro-api-key")
+		_, _ = w.Write([]byte(`[]`))
+	}))
+	defer pro.Close()
+	public := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		t.Fatalf("expected keyed request to go to the pro API, got %s", r.URL.Path)
+	}))
+	defer public.Close()
+
+	client := newClient(public.URL, time.Second)
+	client.proBaseURL = pro.URL
+	client.SetAPIKey(" CG-key ")
+	if _, err := client.GetMarkets(context.Background(), "usd", nil); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if gotKey != "CG-key" {
+		t.Fatalf("expected pro API key header, got %q", gotKey)
+	}
+}
+
+func TestDemoKeyFallsBackToPublicAPI(t *testing.T) {
+	proCalls := 0
+	pro := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		proCalls++
+		w.WriteHeader(http.StatusBadRequest)
+		_, _ = w.Write([]byte(`{"status":{"error_code":10011}}`))
+	}))
+	defer pro.Close()
+	var gotKeys []string
+	public := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotKeys = append(gotKeys, r.Header.Get("x-cg-demo-api-key"))
+		if r.Header.Get("x-cg-pro-api-key") != "" {
+			t.Errorf("expected no pro header on the public API")
+		}
+		_, _ = w.Write([]byte(`[]`))
+	}))
+	defer public.Close()
+
+	client := newClient(public.URL, time.Second)
+	client.proBaseURL = pro.URL
+	client.SetAPIKey("CG-demo")
+	for i := 0; i < 2; i++ {
+		if _, err := client.GetMarkets(context.Background(), "usd", nil); err != nil {
+			t.Fatalf("request %d: %v", i, err)
+		}
+	}
+	if proCalls != 1 || len(gotKeys) != 2 || gotKeys[0] != "CG-demo" {
+		t.Fatalf("expected one pro attempt then demo requests, pro=%d public=%v", proCalls, gotKeys)
+	}
+}
+
+func TestRejectedKeyIsReported(t *testing.T) {
+	refuse := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusUnauthorized)
+	})
+	pro := httptest.NewServer(refuse)
+	defer pro.Close()
+	public := httptest.NewServer(refuse)
+	defer public.Close()
+
+	client := newClient(public.URL, time.Second)
+	client.proBaseURL = pro.URL
+	client.SetAPIKey("wrong")
+	_, err := client.GetSimplePrices(context.Background(), []string{"bitcoin"}, []string{"usd"})
+	var statusErr *StatusError
+	if !errors.Is(err, ErrAPIKeyRejected) || !errors.As(err, &statusErr) || statusErr.StatusCode != http.StatusUnauthorized {
+		t.Fatalf("expected a rejected key error, got %v", err)
+	}
+}
@@ -1,18 +1,34 @@
 package api
 
 import (
+	"errors"
 	"fmt"
 	"time"
 )
 
+// ErrAPIKeyRejected matches a StatusError for a key that neither the pro
+// nor the public API accepts.
+var ErrAPIKeyRejected = errors.New("coingecko: api key rejected")
+
 type StatusError struct {
-	StatusCode int
-	RetryAfter time.Duration
+	StatusCode  int
+	RetryAfter  time.Duration
+	KeyRejected bool
 }
 
 func (e *StatusError) Error() string {
 	if e == nil {
 		return "http status error"
 	}
+	if e.KeyRejected {
+		return fmt.Sprintf("coingecko: api key rejected (HTTP %d)", e.StatusCode)
+	}
 	return fmt.Sprintf("coingecko status: %d", e.StatusCode)
 }
+
+func (e *StatusError) Unwrap() error {
+	if e != nil && e.KeyRejected {
+		return ErrAPIKeyRejected
+	}
+	return nil
+}
@@ -0,0 +1,460 @@
+package cli
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"os/signal"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+
+	"cryptoview/internal/model"
+	"cryptoview/internal/server"
+	"cryptoview/internal/service/marketfeed"
+	"cryptoview/internal/ui/i18n"
+)
+
+const (
+	ExitOK      = 0
+	ExitError   = 1
+	ExitUsage   = 2
+	ExitWarning = 3
+)
+
+const (
+	defaultQuoteTimeout  = 20 * time.Second
+	defaultWatchInterval = 5 * time.Second
+	quotePollInterval    = 2 * time.Second
+)
+
+const usage = `Usage:
+  cryptoview [source flags]       start the desktop application
+  cryptoview quote [flags]        print current quotes once and exit
+  cryptoview watch [flags]        print quotes every interval until interrupted
+  cryptoview serve [flags]        run the local HTTP/SSE API without a window
+
+Flags:
+  --fiat CODE                     quote currency: USD, EUR, RUB, GBP, JPY, CHF, CNY, TRY,
+                                  KZT, UAH, PLN, CAD, AUD, INR, BRL, KRW, or BTC, ETH, SATS
+                                  to price in crypto (default USD)
+  --format table|json|csv         output format (default table)
+  --lang EN|RU                    number formatting for table output (default EN)
+  --timeout 20s                   quote: how long to wait for fresh data
+  --interval 5s                   watch: refresh interval
+  --count N                       watch: stop after N prints (default 0, unlimited)
+  --addr 127.0.0.1:8787           serve: listen address (default $CRYPTOVIEW_API_ADDR or 127.0.0.1:8787)
+  --verbose                       log feed activity to stderr
+
+Source flags (desktop and all commands):
+  --record FILE                   save every market and FX snapshot to a JSONL file
+  --replay FILE                   play a recorded file back instead of live providers
+  --replay-speed 1                replay speed multiplier, e.g. 10 for ten times faster
+
+Exit codes: 0 ok, 1 error, 2 usage, 3 warning (stale, cached or fallback data)
+
+cryptoview-cli takes the same commands and does not need OpenGL or X11.
+`
+
+type marketFeed interface {
+	Start()
+	Stop()
+	SetFiat(currency i18n.FiatCurrency)
+	server.Source
+}
+
+type feedFactory func(callbacks marketfeed.Callbacks, interval time.Duration, source marketfeed.SourceOptions) (marketFeed, error)
+
+type options struct {
+	fiat     i18n.FiatCurrency
+	format   string
+	lang     i18n.AppLanguage
+	timeout  time.Duration
+	interval time.Duration
+	count    int
+	addr     string
+	verbose  bool
+	source   marketfeed.SourceOptions
+}
+
+func IsCommand(name string) bool {
+	switch name {
+	case "quote", "watch", "serve", "help", "-h", "-help", "--help":
+		return true
+	default:
+		return false
+	}
+}
+
+func Run(args []string, stdout, stderr io.Writer) int {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	return run(ctx, args, stdout, stderr, newDefaultFeed)
+}
+
+func newDefaultFeed(callbacks marketfeed.Callbacks, interval time.Duration, source marketfeed.SourceOptions) (marketFeed, error) {
+	feed, err := marketfeed.NewDefaultWithSource(callbacks, source)
+	if err != nil {
+		return nil, err
+	}
+	feed.SetMarketPollInterval(interval)
+	return feed, nil
+}
+
+// ParseSourceFlags parses the flags accepted when starting the desktop
+// application, which only choose the market data source.
+func ParseSourceFlags(args []string) (marketfeed.SourceOptions, error) {
+	var source marketfeed.SourceOptions
+	fs := flag.NewFlagSet("cryptoview", flag.ContinueOnError)
+	fs.SetOutput(io.Discard)
+	addSourceFlags(fs, &source)
+	if err := fs.Parse(args); err != nil {
+		return source, err
+	}
+	if fs.NArg() > 0 {
+		return source, fmt.Errorf("unexpected argument %q", fs.Arg(0))
+	}
+	return source, validateSource(source)
+}
+
+func addSourceFlags(fs *flag.FlagSet, source *marketfeed.SourceOptions) {
+	fs.StringVar(&source.RecordPath, "record", "", "")
+	fs.StringVar(&source.ReplayPath, "replay", "", "")
+	fs.Float64Var(&source.ReplaySpeed, "replay-speed", 1, "")
+}
+
+func validateSource(source marketfeed.SourceOptions) error {
+	if source.RecordPath != "" && source.ReplayPath != "" {
+		return fmt.Errorf("--record and --replay cannot be combined")
+	}
+	if source.ReplaySpeed <= 0 {
+		return fmt.Errorf("replay-speed must be positive")
+	}
+	return nil
+}
+
+func run(ctx context.Context, args []string, stdout, stderr io.Writer, makeFeed feedFactory) int {
+	if len(args) == 0 {
+		fmt.Fprint(stderr, usage)
+		return ExitUsage
+	}
+	command := args[0]
+	switch command {
+	case "help", "-h", "-help", "--help":
+		fmt.Fprint(stdout, usage)
+		return ExitOK
+	case "quote", "watch", "serve":
+	default:
+		fmt.Fprintf(stderr, "cryptoview: unknown command %q\n\n%s", command, usage)
+		return ExitUsage
+	}
+
+	opts, err := parseOptions(command, args[1:])
+	if errors.Is(err, flag.ErrHelp) {
+		fmt.Fprint(stdout, usage)
+		return ExitOK
+	}
+	if err != nil {
+		fmt.Fprintf(stderr, "cryptoview: %v\n", err)
+		return ExitUsage
+	}
+	out, err := newWriter(opts.format, command == "watch", opts.fiat, opts.lang, stdout)
+	if err != nil {
+		fmt.Fprintf(stderr, "cryptoview: %v\n", err)
+		return ExitUsage
+	}
+
+	if opts.verbose {
+		log.SetOutput(stderr)
+	} else {
+		log.SetOutput(io.Discard)
+	}
+	defer log.SetOutput(os.Stderr)
+
+	tracker := newTracker()
+	if command == "serve" {
+		return serve(ctx, tracker, makeFeed, opts, stderr)
+	}
+	interval := quotePollInterval
+	if command == "watch" {
+		interval = opts.interval
+	}
+	feed, err := makeFeed(tracker.callbacks(), interval, opts.source)
+	if err != nil {
+		fmt.Fprintf(stderr, "cryptoview: %v\n", err)
+		return ExitError
+	}
+	feed.SetFiat(opts.fiat)
+	feed.Start()
+	defer feed.Stop()
+
+	if command == "watch" {
+		return watch(ctx, tracker, out, opts, stderr)
+	}
+	return quote(ctx, tracker, out, opts, stderr)
+}
+
+func serve(ctx context.Context, tracker *tracker, makeFeed feedFactory, opts options, stderr io.Writer) int {
+	api := server.New()
+	feed, err := makeFeed(api.Callbacks(tracker.callbacks()), quotePollInterval, opts.source)
+	if err != nil {
+		fmt.Fprintf(stderr, "cryptoview: %v\n", err)
+		return ExitError
+	}
+	api.SetSource(feed)
+	if err := api.Start(opts.addr); err != nil {
+		fmt.Fprintf(stderr, "cryptoview: %v\n", err)
+		return ExitError
+	}
+	feed.Start()
+	fmt.Fprintf(stderr, "cryptoview: serving on http://%s\n", api.Addr())
+
+	<-ctx.Done()
+	feed.Stop()
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := api.Shutdown(shutdownCtx); err != nil {
+		fmt.Fprintf(stderr, "cryptoview: shutdown: %v\n", err)
+		return ExitError
+	}
+	return ExitOK
+}
+
+func parseOptions(command string, args []string) (options, error) {
+	opts := options{
+		fiat:     i18n.FiatUSD,
+		lang:     i18n.LangEN,
+		timeout:  defaultQuoteTimeout,
+		interval: defaultWatchInterval,
+	}
+	var fiat, lang string
+	fs := flag.NewFlagSet(command, flag.ContinueOnError)
+	fs.SetOutput(io.Discard)
+	fs.StringVar(&fiat, "fiat", string(i18n.FiatUSD), "")
+	fs.StringVar(&opts.format, "format", formatTable, "")
+	fs.StringVar(&lang, "lang", string(i18n.LangEN), "")
+	fs.DurationVar(&opts.timeout, "timeout", defaultQuoteTimeout, "")
+	fs.DurationVar(&opts.interval, "interval", defaultWatchInterval, "")
+	fs.IntVar(&opts.count, "count", 0, "")
+	fs.StringVar(&opts.addr, "addr", defaultServeAddr(), "")
+	fs.BoolVar(&opts.verbose, "verbose", false, "")
+	addSourceFlags(fs, &opts.source)
+	if err := fs.Parse(args); err != nil {
+		return opts, err
+	}
+	if fs.NArg() > 0 {
+		return opts, fmt.Errorf("unexpected argument %q", fs.Arg(0))
+	}
+
+	currency, ok := i18n.ParseFiatCurrency(fiat)
+	if !ok {
+		return opts, fmt.Errorf("unsupported fiat %q", fiat)
+	}
+	opts.fiat = currency
+	language, ok := i18n.ParseAppLanguage(lang)
+	if !ok {
+		return opts, fmt.Errorf("unsupported language %q", lang)
+	}
+	opts.lang = language
+	opts.format = strings.ToLower(strings.TrimSpace(opts.format))
+	if opts.timeout <= 0 {
+		return opts, fmt.Errorf("timeout must be positive")
+	}
+	if opts.interval < time.Second {
+		return opts, fmt.Errorf("interval must be at least 1s")
+	}
+	if opts.count < 0 {
+		return opts, fmt.Errorf("count must not be negative")
+	}
+	if err := validateSource(opts.source); err != nil {
+		return opts, err
+	}
+	return opts, nil
+}
+
+func defaultServeAddr() string {
+	if addr := strings.TrimSpace(os.Getenv(server.AddrEnv)); addr != "" {
+		return addr
+	}
+	return server.DefaultAddr
+}
+
+func quote(ctx context.Context, tracker *tracker, out writer, opts options, stderr io.Writer) int {
+	_, _, primed := tracker.snapshot()
+	timer := time.NewTimer(opts.timeout)
+	defer timer.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return ExitError
+		case <-timer.C:
+			coins, status, _ := tracker.snapshot()
+			if len(coins) == 0 {
+				fmt.Fprintln(stderr, "cryptoview: timed out waiting for market data")
+				return ExitError
+			}
+			fmt.Fprintln(stderr, "cryptoview: timed out waiting for fresh data, showing last known quotes")
+			if err := out.write(time.Now(), status, coins); err != nil {
+				fmt.Fprintf(stderr, "cryptoview: %v\n", err)
+				return ExitError
+			}
+			return ExitWarning
+		case <-tracker.notify:
+			coins, status, seq := tracker.snapshot()
+			if seq == primed || status.Kind == marketfeed.StatusKindLoading || status.Kind == "" {
+				continue
+			}
+			if status.Kind == marketfeed.StatusKindError {
+				reportStatus(stderr, status)
+				return ExitError
+			}
+			if len(coins) == 0 {
+				continue
+			}
+			reportStatus(stderr, status)
+			if err := out.write(time.Now(), status, coins); err != nil {
+				fmt.Fprintf(stderr, "cryptoview: %v\n", err)
+				return ExitError
+			}
+			return exitCode(status.Kind)
+		}
+	}
+}
+
+func watch(ctx context.Context, tracker *tracker, out writer, opts options, stderr io.Writer) int {
+	ticker := time.NewTicker(opts.interval)
+	defer ticker.Stop()
+
+	printed := 0
+	var reported marketfeed.StatusEvent
+	emit := func() (int, bool) {
+		coins, status, _ := tracker.snapshot()
+		if status.Kind != reported.Kind || status.Code != reported.Code {
+			reportStatus(stderr, status)
+			reported = status
+		}
+		if len(coins) == 0 {
+			return 0, false
+		}
+		if err := out.write(time.Now(), status, coins); err != nil {
+			fmt.Fprintf(stderr, "cryptoview: %v\n", err)
+			return ExitError, true
+		}
+		printed++
+		if opts.count > 0 && printed >= opts.count {
+			return exitCode(status.Kind), true
+		}
+		return 0, false
+	}
+
+	for {
+		select {
+		case <-ctx.Done():
+			_, status, _ := tracker.snapshot()
+			if printed == 0 {
+				return ExitError
+			}
+			return exitCode(status.Kind)
+		case <-tracker.notify:
+			if printed > 0 {
+				continue
+			}
+			if code, done := emit(); done {
+				return code
+			}
+		case <-ticker.C:
+			if code, done := emit(); done {
+				return code
+			}
+		}
+	}
+}
+
+func exitCode(kind marketfeed.StatusKind) int {
+	switch kind {
+	case marketfeed.StatusKindOK:
+		return ExitOK
+	case marketfeed.StatusKindWarning, marketfeed.StatusKindLoading:
+		return ExitWarning
+	default:
+		return ExitError
+	}
+}
+
+func reportStatus(w io.Writer, event marketfeed.StatusEvent) {
+	switch event.Kind {
+	case marketfeed.StatusKindWarning:
+		parts := []string{"warning:", string(event.Code)}
+		if event.Provider != "" {
+			parts = append(parts, "provider="+event.Provider)
+		}
+		if event.Fiat != "" {
+			parts = append(parts, "fiat="+string(event.Fiat))
+		}
+		if event.DataAge > 0 {
+			parts = append(parts, "age="+event.DataAge.Round(time.Second).String())
+		}
+		if len(event.Discarded) > 0 {
+			parts = append(parts, "discarded="+strings.Join(event.Discarded, ","))
+		}
+		fmt.Fprintf(w, "cryptoview: %s\n", strings.Join(parts, " "))
+	case marketfeed.StatusKindError:
+		if event.Err != nil {
+			fmt.Fprintf(w, "cryptoview: error: %s: %v\n", event.Code, event.Err)
+		} else {
+			fmt.Fprintf(w, "cryptoview: error: %s\n", event.Code)
+		}
+	}
+}
+
+type tracker struct {
+	mu     sync.Mutex
+	coins  []model.Coin
+	status marketfeed.StatusEvent
+	seq    int
+	notify chan struct{}
+}
+
+func newTracker() *tracker {
+	return &tracker{notify: make(chan struct{}, 1)}
+}
+
+func (t *tracker) callbacks() marketfeed.Callbacks {
+	return marketfeed.Callbacks{
+		OnMarketUpdate: func(coins []model.Coin) {
+			t.mu.Lock()
+			t.coins = coins
+			t.mu.Unlock()
+			t.signal()
+		},
+		OnStatus: func(event marketfeed.StatusEvent) {
+			if event.Kind == marketfeed.StatusKindCircuit {
+				return
+			}
+			t.mu.Lock()
+			t.status = event
+			t.seq++
+			t.mu.Unlock()
+			t.signal()
+		},
+	}
+}
+
+func (t *tracker) signal() {
+	select {
+	case t.notify <- struct{}{}:
+	default:
+	}
+}
+
+func (t *tracker) snapshot() ([]model.Coin, marketfeed.StatusEvent, int) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.coins, t.status, t.seq
+}
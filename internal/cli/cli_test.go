@@ -0,0 +1,288 @@
+package cli
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"go/build"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"cryptoview/internal/model"
+	"cryptoview/internal/service/marketfeed"
+	"cryptoview/internal/ui/i18n"
+)
+
+type fakeFeed struct {
+	callbacks marketfeed.Callbacks
+	fiat      i18n.FiatCurrency
+	interval  time.Duration
+	source    marketfeed.SourceOptions
+	onStart   func(f *fakeFeed)
+	stopped   bool
+}
+
+func (f *fakeFeed) Start() {
+	if f.onStart != nil {
+		f.onStart(f)
+	}
+}
+
+func (f *fakeFeed) Stop() {
+	f.stopped = true
+}
+
+func (f *fakeFeed) SetFiat(currency i18n.FiatCurrency) {
+	f.fiat = currency
+}
+
+func (f *fakeFeed) Quotes(i18n.FiatCurrency) ([]model.Coin, bool) {
+	return sampleCoins(), true
+}
+
+func (f *fakeFeed) ProviderStates() []marketfeed.ProviderState {
+	return nil
+}
+
+func (f *fakeFeed) emit(coins []model.Coin, event marketfeed.StatusEvent) {
+	if coins != nil {
+		f.callbacks.OnMarketUpdate(coins)
+	}
+	f.callbacks.OnStatus(event)
+}
+
+type lockedBuffer struct {
+	mu  sync.Mutex
+	buf bytes.Buffer
+}
+
+func (b *lockedBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.Write(p)
+}
+
+func (b *lockedBuffer) String() string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.String()
+}
+
+func factoryFor(feed *fakeFeed) feedFactory {
+	return func(callbacks marketfeed.Callbacks, interval time.Duration, source marketfeed.SourceOptions) (marketFeed, error) {
+		feed.callbacks = callbacks
+		feed.interval = interval
+		feed.source = source
+		return feed, nil
+	}
+}
+
+func sampleCoins() []model.Coin {
+	return []model.Coin{
+		{ID: "bitcoin", Name: "Bitcoin", Ticker: "BTC", Price: 45000.5, PriceUSD: 50000, Change24h: 1.25, LastUpdateTime: "12:00:00"},
+		{ID: "ethereum", Name: "Ethereum", Ticker: "ETH", Price: 1800, PriceUSD: 2000, Change24h: -0.5, LastUpdateTime: "12:00:01"},
+	}
+}
+
+func TestQuotePrintsJSONAndExitsOK(t *testing.T) {
+	feed := &fakeFeed{onStart: func(f *fakeFeed) {
+		go f.emit(sampleCoins(), marketfeed.StatusEvent{Kind: marketfeed.StatusKindOK, Provider: "coingecko"})
+	}}
+	var stdout, stderr bytes.Buffer
+
+	code := run(context.Background(), []string{"quote", "--fiat", "eur", "--format", "json"}, &stdout, &stderr, factoryFor(feed))
+
+	if code != ExitOK {
+		t.Fatalf("expected exit %d, got %d (stderr=%q)", ExitOK, code, stderr.String())
+	}
+	if feed.fiat != i18n.FiatEUR || !feed.stopped {
+		t.Fatalf("expected feed configured for EUR and stopped, got fiat=%s stopped=%v", feed.fiat, feed.stopped)
+	}
+	var payload jsonPayload
+	if err := json.Unmarshal(stdout.Bytes(), &payload); err != nil {
+		t.Fatalf("invalid json output: %v\n%s", err, stdout.String())
+	}
+	if payload.Fiat != "EUR" || payload.Status != "ok" || len(payload.Quotes) != 2 {
+		t.Fatalf("unexpected payload %+v", payload)
+	}
+	if q := payload.Quotes[0]; q.Ticker != "BTC" || q.Price != 45000.5 || q.PriceFormatted != "€45,000.50" {
+		t.Fatalf("unexpected quote %+v", q)
+	}
+}
+
+func TestQuoteSkipsRestoredCacheAndReportsWarning(t *testing.T) {
+	feed := &fakeFeed{onStart: func(f *fakeFeed) {
+		f.emit(sampleCoins(), marketfeed.StatusEvent{Kind: marketfeed.StatusKindWarning, Code: marketfeed.StatusCodeOffline, DataAge: time.Hour})
+		go f.emit(sampleCoins()[:1], marketfeed.StatusEvent{Kind: marketfeed.StatusKindWarning, Code: marketfeed.StatusCodeFallback, Provider: "coinlore"})
+	}}
+	var stdout, stderr bytes.Buffer
+
+	code := run(context.Background(), []string{"quote", "--format", "csv"}, &stdout, &stderr, factoryFor(feed))
+
+	if code != ExitWarning {
+		t.Fatalf("expected exit %d, got %d", ExitWarning, code)
+	}
+	lines := strings.Split(strings.TrimSpace(stdout.String()), "\n")
+	if len(lines) != 2 || !strings.HasPrefix(lines[0], "time,id,ticker") || !strings.Contains(lines[1], ",bitcoin,BTC,Bitcoin,45000.5,50000,1.25,USD,12:00:00,warning") {
+		t.Fatalf("expected fresh fallback data only, got %q", stdout.String())
+	}
+	if !strings.Contains(stderr.String(), "fallback_active provider=coinlore") {
+		t.Fatalf("expected warning on stderr, got %q", stderr.String())
+	}
+}
+
+func TestQuoteExitsWithErrorWhenNoData(t *testing.T) {
+	feed := &fakeFeed{onStart: func(f *fakeFeed) {
+		go f.emit(nil, marketfeed.StatusEvent{Kind: marketfeed.StatusKindError, Code: marketfeed.StatusCodeNoData, Err: errors.New("boom")})
+	}}
+	var stdout, stderr bytes.Buffer
+
+	code := run(context.Background(), []string{"quote"}, &stdout, &stderr, factoryFor(feed))
+
+	if code != ExitError || stdout.Len() != 0 {
+		t.Fatalf("expected error exit without output, got %d %q", code, stdout.String())
+	}
+	if !strings.Contains(stderr.String(), "error: no_data: boom") {
+		t.Fatalf("unexpected stderr %q", stderr.String())
+	}
+}
+
+func TestWatchPrintsTableUntilCount(t *testing.T) {
+	feed := &fakeFeed{onStart: func(f *fakeFeed) {
+		go f.emit(sampleCoins(), marketfeed.StatusEvent{Kind: marketfeed.StatusKindOK})
+	}}
+	var stdout, stderr bytes.Buffer
+
+	code := run(context.Background(), []string{"watch", "--interval", "1s", "--count", "2", "--lang", "ru"}, &stdout, &stderr, factoryFor(feed))
+
+	if code != ExitOK {
+		t.Fatalf("expected exit %d, got %d (stderr=%q)", ExitOK, code, stderr.String())
+	}
+	if feed.interval != time.Second {
+		t.Fatalf("expected poll interval to follow --interval, got %s", feed.interval)
+	}
+	out := stdout.String()
+	if strings.Count(out, "TICKER") != 2 || !strings.Contains(out, "45 000,50 $") || !strings.Contains(out, "+1.25%") {
+		t.Fatalf("unexpected table output:\n%s", out)
+	}
+}
+
+func TestRunRejectsInvalidUsage(t *testing.T) {
+	cases := [][]string{
+		{},
+		{"bogus"},
+		{"quote", "--fiat", "XYZ"},
+		{"quote", "--format", "xml"},
+		{"watch", "--interval", "10ms"},
+		{"quote", "--record", "a.jsonl", "--replay", "b.jsonl"},
+		{"quote", "--replay", "b.jsonl", "--replay-speed", "0"},
+	}
+	for _, args := range cases {
+		var stdout, stderr bytes.Buffer
+		if code := run(context.Background(), args, &stdout, &stderr, factoryFor(&fakeFeed{})); code != ExitUsage {
+			t.Fatalf("args %v: expected usage exit, got %d", args, code)
+		}
+	}
+
+	var stdout, stderr bytes.Buffer
+	if code := run(context.Background(), []string{"help"}, &stdout, &stderr, nil); code != ExitOK || !strings.Contains(stdout.String(), "cryptoview quote") {
+		t.Fatalf("expected help output, got %d %q", code, stdout.String())
+	}
+}
+
+func TestSourceFlagsReachFeedFactory(t *testing.T) {
+	feed := &fakeFeed{onStart: func(f *fakeFeed) {
+		go f.emit(sampleCoins(), marketfeed.StatusEvent{Kind: marketfeed.StatusKindOK})
+	}}
+	var stdout, stderr bytes.Buffer
+
+	code := run(context.Background(), []string{"quote", "--replay", "demo.jsonl", "--replay-speed", "10"}, &stdout, &stderr, factoryFor(feed))
+
+	if code != ExitOK {
+		t.Fatalf("expected exit %d, got %d (stderr=%q)", ExitOK, code, stderr.String())
+	}
+	want := marketfeed.SourceOptions{ReplayPath: "demo.jsonl", ReplaySpeed: 10}
+	if feed.source != want {
+		t.Fatalf("expected source %+v, got %+v", want, feed.source)
+	}
+
+	source, err := ParseSourceFlags([]string{"--record", "out.jsonl"})
+	if err != nil || source.RecordPath != "out.jsonl" || source.ReplaySpeed != 1 {
+		t.Fatalf("unexpected desktop source %+v err=%v", source, err)
+	}
+	if _, err := ParseSourceFlags([]string{"--fiat", "EUR"}); err == nil {
+		t.Fatalf("expected desktop mode to reject command flags")
+	}
+}
+
+func TestServeExposesQuotesUntilCancelled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	feed := &fakeFeed{}
+	stderr := &lockedBuffer{}
+	done := make(chan int, 1)
+	go func() {
+		done <- run(ctx, []string{"serve", "--addr", "127.0.0.1:0"}, io.Discard, stderr, factoryFor(feed))
+	}()
+
+	deadline := time.Now().Add(2 * time.Second)
+	var addr string
+	for addr == "" && time.Now().Before(deadline) {
+		time.Sleep(10 * time.Millisecond)
+		if _, rest, ok := strings.Cut(stderr.String(), "serving on "); ok {
+			addr = strings.TrimSpace(rest)
+		}
+	}
+	if addr == "" {
+		t.Fatal("server did not report its address")
+	}
+	resp, err := http.Get(addr + "/v1/quotes")
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("expected 200, got %d", resp.StatusCode)
+	}
+
+	cancel()
+	select {
+	case code := <-done:
+		if code != ExitOK || !feed.stopped {
+			t.Fatalf("expected clean shutdown, got %d stopped=%v", code, feed.stopped)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("serve did not stop after cancellation")
+	}
+}
+
+// The headless binary must start on hosts without GL/X11, so nothing this
+// package imports may pull in Fyne.
+func TestCLIDoesNotImportUI(t *testing.T) {
+	seen := make(map[string]bool)
+	var walk func(path, parent string)
+	walk = func(path, parent string) {
+		if seen[path] {
+			return
+		}
+		seen[path] = true
+		if strings.HasPrefix(path, "fyne.io/") {
+			t.Fatalf("%s imports %s", parent, path)
+		}
+		if !strings.HasPrefix(path, "cryptoview/") {
+			return
+		}
+		pkg, err := build.Import(path, ".", 0)
+		if err != nil {
+			t.Fatalf("import %s: %v", path, err)
+		}
+		for _, dep := range pkg.Imports {
+			walk(dep, path)
+		}
+	}
+	walk("cryptoview/internal/cli", "")
+}
@@ -0,0 +1,160 @@
+package cli
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"text/tabwriter"
+	"time"
+
+	"cryptoview/internal/model"
+	"cryptoview/internal/service/marketfeed"
+	"cryptoview/internal/ui/i18n"
+)
+
+const (
+	formatTable = "table"
+	formatJSON  = "json"
+	formatCSV   = "csv"
+)
+
+type writer interface {
+	write(at time.Time, status marketfeed.StatusEvent, coins []model.Coin) error
+}
+
+func newWriter(format string, stream bool, fiat i18n.FiatCurrency, lang i18n.AppLanguage, out io.Writer) (writer, error) {
+	switch format {
+	case formatTable:
+		return &tableWriter{out: out, fiat: fiat, lang: lang, stamp: stream}, nil
+	case formatJSON:
+		return &jsonWriter{out: out, fiat: fiat, lang: lang, compact: stream}, nil
+	case formatCSV:
+		return &csvWriter{out: csv.NewWriter(out), fiat: fiat}, nil
+	default:
+		return nil, fmt.Errorf("unsupported format %q", format)
+	}
+}
+
+type tableWriter struct {
+	out   io.Writer
+	fiat  i18n.FiatCurrency
+	lang  i18n.AppLanguage
+	stamp bool
+	count int
+}
+
+func (w *tableWriter) write(at time.Time, _ marketfeed.StatusEvent, coins []model.Coin) error {
+	if w.stamp {
+		if w.count > 0 {
+			fmt.Fprintln(w.out)
+		}
+		fmt.Fprintf(w.out, "# %s\n", i18n.FormatDateTime(at, w.lang))
+	}
+	w.count++
+	tw := tabwriter.NewWriter(w.out, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "TICKER\tNAME\tPRICE\t24H\tUPDATED\t")
+	for _, coin := range coins {
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%+.2f%%\t%s\t\n",
+			coin.Ticker,
+			coin.Name,
+			i18n.FormatQuote(coin.Price, w.fiat, w.lang, coin.Converted),
+			coin.Change24h,
+			i18n.FormatTime(coin.LastUpdateTime, w.lang),
+		)
+	}
+	return tw.Flush()
+}
+
+type jsonQuote struct {
+	ID             string  `json:"id"`
+	Name           string  `json:"name"`
+	Ticker         string  `json:"ticker"`
+	Price          float64 `json:"price"`
+	PriceUSD       float64 `json:"price_usd"`
+	PriceFormatted string  `json:"price_formatted"`
+	Converted      bool    `json:"converted,omitempty"`
+	Change24h      float64 `json:"change_24h"`
+	Updated        string  `json:"updated"`
+}
+
+type jsonPayload struct {
+	Time     time.Time   `json:"time"`
+	Fiat     string      `json:"fiat"`
+	Status   string      `json:"status"`
+	Code     string      `json:"code,omitempty"`
+	Provider string      `json:"provider,omitempty"`
+	Quotes   []jsonQuote `json:"quotes"`
+}
+
+type jsonWriter struct {
+	out     io.Writer
+	fiat    i18n.FiatCurrency
+	lang    i18n.AppLanguage
+	compact bool
+}
+
+func (w *jsonWriter) write(at time.Time, status marketfeed.StatusEvent, coins []model.Coin) error {
+	payload := jsonPayload{
+		Time:     at.UTC(),
+		Fiat:     string(w.fiat),
+		Status:   string(status.Kind),
+		Code:     string(status.Code),
+		Provider: status.Provider,
+		Quotes:   make([]jsonQuote, 0, len(coins)),
+	}
+	for _, coin := range coins {
+		payload.Quotes = append(payload.Quotes, jsonQuote{
+			ID:             coin.ID,
+			Name:           coin.Name,
+			Ticker:         coin.Ticker,
+			Price:          coin.Price,
+			PriceUSD:       coin.PriceUSD,
+			PriceFormatted: i18n.FormatQuote(coin.Price, w.fiat, w.lang, coin.Converted),
+			Converted:      coin.Converted,
+			Change24h:      coin.Change24h,
+			Updated:        coin.LastUpdateTime,
+		})
+	}
+	encoder := json.NewEncoder(w.out)
+	if !w.compact {
+		encoder.SetIndent("", "  ")
+	}
+	return encoder.Encode(payload)
+}
+
+type csvWriter struct {
+	out    *csv.Writer
+	fiat   i18n.FiatCurrency
+	header bool
+}
+
+func (w *csvWriter) write(at time.Time, status marketfeed.StatusEvent, coins []model.Coin) error {
+	if !w.header {
+		w.header = true
+		if err := w.out.Write([]string{"time", "id", "ticker", "name", "price", "price_usd", "change_24h", "fiat", "updated", "status"}); err != nil {
+			return err
+		}
+	}
+	stamp := at.UTC().Format(time.RFC3339)
+	for _, coin := range coins {
+		record := []string{
+			stamp,
+			coin.ID,
+			coin.Ticker,
+			coin.Name,
+			strconv.FormatFloat(coin.Price, 'f', -1, 64),
+			strconv.FormatFloat(coin.PriceUSD, 'f', -1, 64),
+			strconv.FormatFloat(coin.Change24h, 'f', 2, 64),
+			string(w.fiat),
+			coin.LastUpdateTime,
+			string(status.Kind),
+		}
+		if err := w.out.Write(record); err != nil {
+			return err
+		}
+	}
+	w.out.Flush()
+	return w.out.Error()
+}
@@ -0,0 +1,315 @@
+package metrics
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+var Default = NewRegistry()
+
+var DefaultDurationBuckets = []float64{0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10}
+
+type metricType string
+
+const (
+	typeCounter   metricType = "counter"
+	typeGauge     metricType = "gauge"
+	typeHistogram metricType = "histogram"
+)
+
+type collector interface {
+	desc() *descriptor
+	write(w io.Writer)
+}
+
+type descriptor struct {
+	name   string
+	help   string
+	kind   metricType
+	labels []string
+}
+
+type Registry struct {
+	mu         sync.Mutex
+	collectors map[string]collector
+}
+
+func NewRegistry() *Registry {
+	return &Registry{collectors: make(map[string]collector)}
+}
+
+func (r *Registry) Counter(name, help string, labels ...string) *CounterVec {
+	c := r.register(&CounterVec{vec: newVec(descriptor{name: name, help: help, kind: typeCounter, labels: labels})})
+	return c.(*CounterVec)
+}
+
+func (r *Registry) Gauge(name, help string, labels ...string) *GaugeVec {
+	c := r.register(&GaugeVec{vec: newVec(descriptor{name: name, help: help, kind: typeGauge, labels: labels})})
+	return c.(*GaugeVec)
+}
+
+func (r *Registry) Histogram(name, help string, buckets []float64, labels ...string) *HistogramVec {
+	sorted := append([]float64(nil), buckets...)
+	sort.Float64s(sorted)
+	h := &HistogramVec{
+		d:       descriptor{name: name, help: help, kind: typeHistogram, labels: labels},
+		buckets: sorted,
+		series:  make(map[string]*histogramSeries),
+	}
+	return r.register(h).(*HistogramVec)
+}
+
+func (r *Registry) GaugeFunc(name, help string, labels []string, collect func(emit func(value float64, labelValues ...string))) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	d := descriptor{name: name, help: help, kind: typeGauge, labels: labels}
+	if existing, ok := r.collectors[name]; ok {
+		if _, ok := existing.(*gaugeFunc); !ok {
+			panic(fmt.Sprintf("metrics: %s already registered with a different type", name))
+		}
+	}
+	r.collectors[name] = &gaugeFunc{d: d, collect: collect}
+}
+
+func (r *Registry) register(c collector) collector {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	d := c.desc()
+	if existing, ok := r.collectors[d.name]; ok {
+		if fmt.Sprintf("%T", existing) != fmt.Sprintf("%T", c) {
+			panic(fmt.Sprintf("metrics: %s already registered with a different type", d.name))
+		}
+		return existing
+	}
+	r.collectors[d.name] = c
+	return c
+}
+
+func (r *Registry) WriteText(w io.Writer) error {
+	r.mu.Lock()
+	names := make([]string, 0, len(r.collectors))
+	for name := range r.collectors {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	collectors := make([]collector, 0, len(names))
+	for _, name := range names {
+		collectors = append(collectors, r.collectors[name])
+	}
+	r.mu.Unlock()
+
+	bw := bufio.NewWriter(w)
+	for _, c := range collectors {
+		d := c.desc()
+		fmt.Fprintf(bw, "# HELP %s %s\n", d.name, escapeHelp(d.help))
+		fmt.Fprintf(bw, "# TYPE %s %s\n", d.name, d.kind)
+		c.write(bw)
+	}
+	return bw.Flush()
+}
+
+func (r *Registry) ServeHTTP(w http.ResponseWriter, _ *http.Request) {
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+	_ = r.WriteText(w)
+}
+
+type vec struct {
+	mu     sync.Mutex
+	d      descriptor
+	values map[string]float64
+	labels map[string][]string
+}
+
+func newVec(d descriptor) vec {
+	return vec{d: d, values: make(map[string]float64), labels: make(map[string][]string)}
+}
+
+func (v *vec) update(labelValues []string, fn func(float64) float64) {
+	if len(labelValues) != len(v.d.labels) {
+		panic(fmt.Sprintf("metrics: %s expects %d labels, got %d", v.d.name, len(v.d.labels), len(labelValues)))
+	}
+	key := strings.Join(labelValues, "\xff")
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	if _, ok := v.labels[key]; !ok {
+		v.labels[key] = append([]string(nil), labelValues...)
+	}
+	v.values[key] = fn(v.values[key])
+}
+
+func (v *vec) Value(labelValues ...string) float64 {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	return v.values[strings.Join(labelValues, "\xff")]
+}
+
+func (v *vec) desc() *descriptor {
+	return &v.d
+}
+
+func (v *vec) write(w io.Writer) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	for _, key := range sortedKeys(v.values) {
+		fmt.Fprintf(w, "%s%s %s\n", v.d.name, formatLabels(v.d.labels, v.labels[key]), formatValue(v.values[key]))
+	}
+}
+
+type CounterVec struct {
+	vec
+}
+
+func (c *CounterVec) Inc(labelValues ...string) {
+	c.Add(1, labelValues...)
+}
+
+func (c *CounterVec) Add(delta float64, labelValues ...string) {
+	if delta < 0 {
+		return
+	}
+	c.update(labelValues, func(current float64) float64 { return current + delta })
+}
+
+type GaugeVec struct {
+	vec
+}
+
+func (g *GaugeVec) Set(value float64, labelValues ...string) {
+	g.update(labelValues, func(float64) float64 { return value })
+}
+
+type gaugeFunc struct {
+	d       descriptor
+	collect func(emit func(value float64, labelValues ...string))
+}
+
+func (g *gaugeFunc) desc() *descriptor {
+	return &g.d
+}
+
+func (g *gaugeFunc) write(w io.Writer) {
+	g.collect(func(value float64, labelValues ...string) {
+		if len(labelValues) != len(g.d.labels) {
+			return
+		}
+		fmt.Fprintf(w, "%s%s %s\n", g.d.name, formatLabels(g.d.labels, labelValues), formatValue(value))
+	})
+}
+
+type HistogramVec struct {
+	mu      sync.Mutex
+	d       descriptor
+	buckets []float64
+	series  map[string]*histogramSeries
+}
+
+type histogramSeries struct {
+	labels []string
+	counts []uint64
+	sum    float64
+	count  uint64
+}
+
+func (h *HistogramVec) Observe(value float64, labelValues ...string) {
+	if len(labelValues) != len(h.d.labels) {
+		panic(fmt.Sprintf("metrics: %s expects %d labels, got %d", h.d.name, len(h.d.labels), len(labelValues)))
+	}
+	key := strings.Join(labelValues, "\xff")
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	s := h.series[key]
+	if s == nil {
+		s = &histogramSeries{labels: append([]string(nil), labelValues...), counts: make([]uint64, len(h.buckets))}
+		h.series[key] = s
+	}
+	for i, bound := range h.buckets {
+		if value <= bound {
+			s.counts[i]++
+		}
+	}
+	s.sum += value
+	s.count++
+}
+
+func (h *HistogramVec) Count(labelValues ...string) uint64 {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if s := h.series[strings.Join(labelValues, "\xff")]; s != nil {
+		return s.count
+	}
+	return 0
+}
+
+func (h *HistogramVec) desc() *descriptor {
+	return &h.d
+}
+
+func (h *HistogramVec) write(w io.Writer) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	keys := make([]string, 0, len(h.series))
+	for key := range h.series {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	bucketLabels := append(append([]string(nil), h.d.labels...), "le")
+	for _, key := range keys {
+		s := h.series[key]
+		for i, bound := range h.buckets {
+			values := append(append([]string(nil), s.labels...), formatValue(bound))
+			fmt.Fprintf(w, "%s_bucket%s %d\n", h.d.name, formatLabels(bucketLabels, values), s.counts[i])
+		}
+		values := append(append([]string(nil), s.labels...), "+Inf")
+		fmt.Fprintf(w, "%s_bucket%s %d\n", h.d.name, formatLabels(bucketLabels, values), s.count)
+		fmt.Fprintf(w, "%s_sum%s %s\n", h.d.name, formatLabels(h.d.labels, s.labels), formatValue(s.sum))
+		fmt.Fprintf(w, "%s_count%s %d\n", h.d.name, formatLabels(h.d.labels, s.labels), s.count)
+	}
+}
+
+func sortedKeys(values map[string]float64) []string {
+	keys := make([]string, 0, len(values))
+	for key := range values {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func formatLabels(names, values []string) string {
+	if len(names) == 0 {
+		return ""
+	}
+	parts := make([]string, len(names))
+	for i, name := range names {
+		parts[i] = fmt.Sprintf("%s=\"%s\"", name, escapeLabel(values[i]))
+	}
+	return "{" + strings.Join(parts, ",") + "}"
+}
+
+func formatValue(value float64) string {
+	switch {
+	case math.IsInf(value, 1):
+		return "+Inf"
+	case math.IsInf(value, -1):
+		return "-Inf"
+	case math.IsNaN(value):
+		return "NaN"
+	default:
+		return strconv.FormatFloat(value, 'g', -1, 64)
+	}
+}
+
+func escapeLabel(value string) string {
+	return strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`).Replace(value)
+}
+
+func escapeHelp(value string) string {
+	return strings.NewReplacer(`\`, `\\`, "\n", `\n`).Replace(value)
+}
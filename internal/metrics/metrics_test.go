@@ -0,0 +1,73 @@
+package metrics
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestRegistryWritesPrometheusText(t *testing.T) {
+	r := NewRegistry()
+	attempts := r.Counter("fetch_attempts_total", "Fetch attempts.", "provider")
+	attempts.Inc("coingecko")
+	attempts.Add(2, "coinlore")
+	r.Gauge("queue_depth", "Queue depth.").Set(3)
+	latency := r.Histogram("fetch_seconds", "Fetch latency.", []float64{1, 0.5}, "provider")
+	latency.Observe(0.2, "coingecko")
+	latency.Observe(0.7, "coingecko")
+	latency.Observe(3, "coingecko")
+	r.GaugeFunc("snapshot_age_seconds", "Snapshot age.", nil, func(emit func(float64, ...string)) {
+		emit(12.5)
+	})
+
+	var b strings.Builder
+	if err := r.WriteText(&b); err != nil {
+		t.Fatal(err)
+	}
+	want := `# HELP fetch_attempts_total Fetch attempts.
+# TYPE fetch_attempts_total counter
+fetch_attempts_total{provider="coingecko"} 1
+fetch_attempts_total{provider="coinlore"} 2
+# HELP fetch_seconds Fetch latency.
+# TYPE fetch_seconds histogram
+fetch_seconds_bucket{provider="coingecko",le="0.5"} 1
+fetch_seconds_bucket{provider="coingecko",le="1"} 2
+fetch_seconds_bucket{provider="coingecko",le="+Inf"} 3
+fetch_seconds_sum{provider="coingecko"} 3.9
+fetch_seconds_count{provider="coingecko"} 3
+# HELP queue_depth Queue depth.
+# TYPE queue_depth gauge
+queue_depth 3
+# HELP snapshot_age_seconds Snapshot age.
+# TYPE snapshot_age_seconds gauge
+snapshot_age_seconds 12.5
+`
+	if b.String() != want {
+		t.Fatalf("unexpected output:\n%s\nwant:\n%s", b.String(), want)
+	}
+}
+
+func TestRegistryReturnsExistingCollectorAndEscapesLabels(t *testing.T) {
+	r := NewRegistry()
+	first := r.Counter("errors_total", "Errors.", "reason")
+	second := r.Counter("errors_total", "Errors.", "reason")
+	if first != second {
+		t.Fatal("expected re-registration to return the existing counter")
+	}
+	first.Inc("say \"hi\"\n")
+	first.Add(-1, "ignored")
+
+	var b strings.Builder
+	if err := r.WriteText(&b); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(b.String(), `errors_total{reason="say \"hi\"\n"} 1`) || strings.Contains(b.String(), "ignored") {
+		t.Fatalf("unexpected output:\n%s", b.String())
+	}
+
+	defer func() {
+		if recover() == nil {
+			t.Fatal("expected type conflict to panic")
+		}
+	}()
+	r.Gauge("errors_total", "Errors.")
+}
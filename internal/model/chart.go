@@ -0,0 +1,61 @@
+package model
+
+import (
+	"sort"
+	"time"
+)
+
+type PricePoint struct {
+	Time  time.Time
+	Price float64
+}
+
+type Candle struct {
+	Time  time.Time
+	Open  float64
+	High  float64
+	Low   float64
+	Close float64
+}
+
+type CoinGeckoMarketChart struct {
+	Prices [][]float64 `json:"prices"`
+}
+
+func (c CoinGeckoMarketChart) PricePoints() []PricePoint {
+	points := make([]PricePoint, 0, len(c.Prices))
+	for _, pair := range c.Prices {
+		if len(pair) < 2 || pair[1] <= 0 {
+			continue
+		}
+		points = append(points, PricePoint{
+			Time:  time.UnixMilli(int64(pair[0])),
+			Price: pair[1],
+		})
+	}
+	sort.Slice(points, func(i, j int) bool { return points[i].Time.Before(points[j].Time) })
+	return points
+}
+
+func BuildCandles(points []PricePoint, interval time.Duration) []Candle {
+	if len(points) == 0 || interval <= 0 {
+		return nil
+	}
+	candles := make([]Candle, 0, len(points))
+	for _, p := range points {
+		bucket := p.Time.Truncate(interval)
+		if n := len(candles); n > 0 && candles[n-1].Time.Equal(bucket) {
+			last := &candles[n-1]
+			if p.Price > last.High {
+				last.High = p.Price
+			}
+			if p.Price < last.Low {
+				last.Low = p.Price
+			}
+			last.Close = p.Price
+			continue
+		}
+		candles = append(candles, Candle{Time: bucket, Open: p.Price, High: p.Price, Low: p.Price, Close: p.Price})
+	}
+	return candles
+}
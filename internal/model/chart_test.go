@@ -0,0 +1,47 @@
+package model
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+)
+
+func TestMarketChartPricePointsSortsAndSkipsInvalid(t *testing.T) {
+	var chart CoinGeckoMarketChart
+	raw := `{"prices":[[1700000060000,101.5],[1700000000000,100],[1700000120000,0],[1700000180000]]}`
+	if err := json.Unmarshal([]byte(raw), &chart); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	points := chart.PricePoints()
+	if len(points) != 2 {
+		t.Fatalf("expected 2 valid points, got %d", len(points))
+	}
+	if points[0].Price != 100 || points[1].Price != 101.5 {
+		t.Fatalf("expected points sorted by time, got %+v", points)
+	}
+	if !points[0].Time.Equal(time.UnixMilli(1700000000000)) {
+		t.Fatalf("unexpected timestamp %v", points[0].Time)
+	}
+}
+
+func TestBuildCandlesBucketsPoints(t *testing.T) {
+	base := time.Unix(1700000000, 0).Truncate(time.Hour)
+	points := []PricePoint{
+		{Time: base, Price: 10},
+		{Time: base.Add(10 * time.Minute), Price: 14},
+		{Time: base.Add(20 * time.Minute), Price: 8},
+		{Time: base.Add(30 * time.Minute), Price: 12},
+		{Time: base.Add(time.Hour), Price: 13},
+	}
+	candles := BuildCandles(points, time.Hour)
+	if len(candles) != 2 {
+		t.Fatalf("expected 2 candles, got %d", len(candles))
+	}
+	first := candles[0]
+	if first.Open != 10 || first.High != 14 || first.Low != 8 || first.Close != 12 {
+		t.Fatalf("unexpected first candle %+v", first)
+	}
+	if candles[1].Open != 13 || candles[1].Close != 13 {
+		t.Fatalf("unexpected second candle %+v", candles[1])
+	}
+}
@@ -10,9 +10,13 @@ type Coin struct {
 	Name           string
 	Ticker         string
 	Price          float64
+	PriceUSD       float64
 	Change24h      float64
 	LastUpdateTime string
 	IconPath       string
+	// Converted is set when Price was derived from PriceUSD with an FX rate
+	// rather than quoted natively in the display fiat.
+	Converted bool
 }
 
 var coinIconPathByID = map[string]string{
@@ -32,6 +36,7 @@ type CoinGeckoMarket struct {
 	Name                     string  `json:"name"`
 	CurrentPrice             float64 `json:"current_price"`
 	PriceChangePercentage24h float64 `json:"price_change_percentage_24h"`
+	TotalVolume              float64 `json:"total_volume"`
 	LastUpdated              string  `json:"last_updated"`
 }
 
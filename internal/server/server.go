@@ -0,0 +1,424 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net"
+	"net/http"
+	"sync"
+	"time"
+
+	"cryptoview/internal/metrics"
+	"cryptoview/internal/model"
+	"cryptoview/internal/service/marketfeed"
+	"cryptoview/internal/ui/i18n"
+)
+
+const (
+	AddrEnv     = "CRYPTOVIEW_API_ADDR"
+	DefaultAddr = "127.0.0.1:8787"
+
+	defaultKeepAlive = 15 * time.Second
+)
+
+type Source interface {
+	Quotes(fiat i18n.FiatCurrency) ([]model.Coin, bool)
+	ProviderStates() []marketfeed.ProviderState
+}
+
+type Server struct {
+	mu          sync.RWMutex
+	source      Source
+	status      marketfeed.StatusEvent
+	statusAt    time.Time
+	subscribers map[*subscriber]struct{}
+	keepAlive   time.Duration
+	metrics     *metrics.Registry
+
+	httpServer *http.Server
+	listener   net.Listener
+	done       chan struct{}
+	closeOnce  sync.Once
+}
+
+type subscriber struct {
+	quotes chan struct{}
+	status chan struct{}
+}
+
+type quoteJSON struct {
+	ID        string  `json:"id"`
+	Name      string  `json:"name"`
+	Ticker    string  `json:"ticker"`
+	Price     float64 `json:"price"`
+	PriceUSD  float64 `json:"price_usd"`
+	Converted bool    `json:"converted,omitempty"`
+	Change24h float64 `json:"change_24h"`
+	Updated   string  `json:"updated"`
+}
+
+type quotesJSON struct {
+	Fiat   string      `json:"fiat"`
+	Time   time.Time   `json:"time"`
+	Quotes []quoteJSON `json:"quotes"`
+}
+
+type providerJSON struct {
+	Name                string     `json:"name"`
+	FX                  bool       `json:"fx,omitempty"`
+	Circuit             string     `json:"circuit"`
+	ConsecutiveFailures int        `json:"consecutive_failures"`
+	CoolingDown         bool       `json:"cooling_down"`
+	CooldownUntil       *time.Time `json:"cooldown_until,omitempty"`
+	CooldownRemaining   float64    `json:"cooldown_remaining_seconds"`
+	LastSuccess         *time.Time `json:"last_success,omitempty"`
+	LastFailure         *time.Time `json:"last_failure,omitempty"`
+	LastError           string     `json:"last_error,omitempty"`
+	LastErrorKind       string     `json:"last_error_kind,omitempty"`
+	LastStatusCode      int        `json:"last_status_code,omitempty"`
+	AverageLatency      float64    `json:"average_latency_seconds"`
+	BudgetPerMinute     int        `json:"budget_per_minute,omitempty"`
+	BudgetRemaining     *int       `json:"budget_remaining,omitempty"`
+}
+
+type statusJSON struct {
+	Kind          string         `json:"kind"`
+	Code          string         `json:"code,omitempty"`
+	Provider      string         `json:"provider,omitempty"`
+	Error         string         `json:"error,omitempty"`
+	DataAge       float64        `json:"data_age_seconds,omitempty"`
+	Discarded     []string       `json:"discarded,omitempty"`
+	Fiat          string         `json:"fiat,omitempty"`
+	UpdatedAt     *time.Time     `json:"updated_at,omitempty"`
+	Providers     []providerJSON `json:"providers"`
+	StreamClients int            `json:"stream_clients"`
+}
+
+type errorJSON struct {
+	Error string `json:"error"`
+}
+
+func New() *Server {
+	return &Server{
+		subscribers: make(map[*subscriber]struct{}),
+		keepAlive:   defaultKeepAlive,
+		metrics:     metrics.Default,
+		done:        make(chan struct{}),
+	}
+}
+
+func (s *Server) SetSource(source Source) {
+	s.mu.Lock()
+	s.source = source
+	s.mu.Unlock()
+}
+
+func (s *Server) SetMetrics(registry *metrics.Registry) {
+	s.mu.Lock()
+	s.metrics = registry
+	s.mu.Unlock()
+}
+
+func (s *Server) Callbacks(next marketfeed.Callbacks) marketfeed.Callbacks {
+	return marketfeed.Callbacks{
+		OnMarketUpdate: func(coins []model.Coin) {
+			if next.OnMarketUpdate != nil {
+				next.OnMarketUpdate(coins)
+			}
+			s.broadcast(func(sub *subscriber) chan struct{} { return sub.quotes })
+		},
+		OnStatus: func(event marketfeed.StatusEvent) {
+			if next.OnStatus != nil {
+				next.OnStatus(event)
+			}
+			if event.Kind == marketfeed.StatusKindCircuit {
+				return
+			}
+			s.mu.Lock()
+			s.status = event
+			s.statusAt = time.Now()
+			s.mu.Unlock()
+			s.broadcast(func(sub *subscriber) chan struct{} { return sub.status })
+		},
+	}
+}
+
+func (s *Server) Handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /v1/quotes", s.handleQuotes)
+	mux.HandleFunc("GET /v1/status", s.handleStatus)
+	mux.HandleFunc("GET /v1/stream", s.handleStream)
+	s.mu.RLock()
+	registry := s.metrics
+	s.mu.RUnlock()
+	if registry != nil {
+		mux.Handle("GET /metrics", registry)
+	}
+	return mux
+}
+
+func (s *Server) Start(addr string) error {
+	listener, err := net.Listen("tcp", addr)
+	if err != nil {
+		return fmt.Errorf("server: listen %s: %w", addr, err)
+	}
+	httpServer := &http.Server{Handler: s.Handler(), ReadHeaderTimeout: 10 * time.Second}
+	s.mu.Lock()
+	s.listener = listener
+	s.httpServer = httpServer
+	s.mu.Unlock()
+
+	log.Printf("server: listening addr=%s", listener.Addr())
+	go func() {
+		if err := httpServer.Serve(listener); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			log.Printf("server: serve failed err=%v", err)
+		}
+	}()
+	return nil
+}
+
+func (s *Server) Addr() net.Addr {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	if s.listener == nil {
+		return nil
+	}
+	return s.listener.Addr()
+}
+
+func (s *Server) Shutdown(ctx context.Context) error {
+	s.closeOnce.Do(func() { close(s.done) })
+	s.mu.RLock()
+	httpServer := s.httpServer
+	s.mu.RUnlock()
+	if httpServer == nil {
+		return nil
+	}
+	return httpServer.Shutdown(ctx)
+}
+
+func (s *Server) handleQuotes(w http.ResponseWriter, r *http.Request) {
+	fiat, ok := parseFiat(r)
+	if !ok {
+		writeJSON(w, http.StatusBadRequest, errorJSON{Error: "unsupported fiat"})
+		return
+	}
+	payload, ok := s.quotes(fiat)
+	if !ok {
+		writeJSON(w, http.StatusServiceUnavailable, errorJSON{Error: "market data unavailable"})
+		return
+	}
+	writeJSON(w, http.StatusOK, payload)
+}
+
+func (s *Server) handleStatus(w http.ResponseWriter, _ *http.Request) {
+	writeJSON(w, http.StatusOK, s.statusPayload(time.Now()))
+}
+
+func (s *Server) handleStream(w http.ResponseWriter, r *http.Request) {
+	fiat, ok := parseFiat(r)
+	if !ok {
+		writeJSON(w, http.StatusBadRequest, errorJSON{Error: "unsupported fiat"})
+		return
+	}
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		writeJSON(w, http.StatusInternalServerError, errorJSON{Error: "streaming unsupported"})
+		return
+	}
+
+	sub := s.subscribe()
+	defer s.unsubscribe(sub)
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	w.WriteHeader(http.StatusOK)
+
+	writeStatus := func() error {
+		return writeEvent(w, "status", s.statusPayload(time.Now()))
+	}
+	writeQuotes := func() error {
+		payload, ok := s.quotes(fiat)
+		if !ok {
+			return nil
+		}
+		return writeEvent(w, "quotes", payload)
+	}
+	if writeStatus() != nil || writeQuotes() != nil {
+		return
+	}
+	flusher.Flush()
+
+	keepAlive := time.NewTicker(s.keepAlive)
+	defer keepAlive.Stop()
+	for {
+		var err error
+		select {
+		case <-r.Context().Done():
+			return
+		case <-s.done:
+			return
+		case <-sub.status:
+			err = writeStatus()
+		case <-sub.quotes:
+			err = writeQuotes()
+		case <-keepAlive.C:
+			_, err = fmt.Fprint(w, ": keepalive\n\n")
+		}
+		if err != nil {
+			return
+		}
+		flusher.Flush()
+	}
+}
+
+func (s *Server) quotes(fiat i18n.FiatCurrency) (quotesJSON, bool) {
+	s.mu.RLock()
+	source := s.source
+	s.mu.RUnlock()
+	if source == nil {
+		return quotesJSON{}, false
+	}
+	coins, ok := source.Quotes(fiat)
+	if !ok {
+		return quotesJSON{}, false
+	}
+	payload := quotesJSON{Fiat: string(fiat), Time: time.Now().UTC(), Quotes: make([]quoteJSON, 0, len(coins))}
+	for _, coin := range coins {
+		payload.Quotes = append(payload.Quotes, quoteJSON{
+			ID:        coin.ID,
+			Name:      coin.Name,
+			Ticker:    coin.Ticker,
+			Price:     coin.Price,
+			PriceUSD:  coin.PriceUSD,
+			Converted: coin.Converted,
+			Change24h: coin.Change24h,
+			Updated:   coin.LastUpdateTime,
+		})
+	}
+	return payload, true
+}
+
+func (s *Server) statusPayload(now time.Time) statusJSON {
+	s.mu.RLock()
+	source := s.source
+	event := s.status
+	at := s.statusAt
+	clients := len(s.subscribers)
+	s.mu.RUnlock()
+
+	payload := statusJSON{
+		Kind:          string(event.Kind),
+		Code:          string(event.Code),
+		Provider:      event.Provider,
+		DataAge:       event.DataAge.Seconds(),
+		Discarded:     event.Discarded,
+		Fiat:          string(event.Fiat),
+		Providers:     []providerJSON{},
+		StreamClients: clients,
+	}
+	if payload.Kind == "" {
+		payload.Kind = string(marketfeed.StatusKindLoading)
+	}
+	if event.Err != nil {
+		payload.Error = event.Err.Error()
+	}
+	if !at.IsZero() {
+		stamp := at.UTC()
+		payload.UpdatedAt = &stamp
+	}
+	if source == nil {
+		return payload
+	}
+	for _, state := range source.ProviderStates() {
+		p := providerJSON{
+			Name:                state.Name,
+			FX:                  state.FX,
+			Circuit:             string(state.Circuit),
+			ConsecutiveFailures: state.ConsecutiveFailures,
+			LastSuccess:         optionalTime(state.LastSuccess),
+			LastFailure:         optionalTime(state.LastFailure),
+			LastErrorKind:       string(state.LastErrorKind),
+			LastStatusCode:      state.LastStatusCode,
+			AverageLatency:      state.AverageLatency.Seconds(),
+		}
+		if state.LastError != nil {
+			p.LastError = state.LastError.Error()
+		}
+		if state.BudgetPerMinute > 0 {
+			remaining := state.BudgetRemaining
+			p.BudgetPerMinute = state.BudgetPerMinute
+			p.BudgetRemaining = &remaining
+		}
+		if state.CooldownUntil.After(now) {
+			until := state.CooldownUntil.UTC()
+			p.CoolingDown = true
+			p.CooldownUntil = &until
+			p.CooldownRemaining = state.CooldownUntil.Sub(now).Seconds()
+		}
+		payload.Providers = append(payload.Providers, p)
+	}
+	return payload
+}
+
+func (s *Server) subscribe() *subscriber {
+	sub := &subscriber{quotes: make(chan struct{}, 1), status: make(chan struct{}, 1)}
+	s.mu.Lock()
+	s.subscribers[sub] = struct{}{}
+	s.mu.Unlock()
+	return sub
+}
+
+func (s *Server) unsubscribe(sub *subscriber) {
+	s.mu.Lock()
+	delete(s.subscribers, sub)
+	s.mu.Unlock()
+}
+
+func (s *Server) broadcast(pick func(*subscriber) chan struct{}) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	for sub := range s.subscribers {
+		select {
+		case pick(sub) <- struct{}{}:
+		default:
+		}
+	}
+}
+
+func optionalTime(t time.Time) *time.Time {
+	if t.IsZero() {
+		return nil
+	}
+	utc := t.UTC()
+	return &utc
+}
+
+func parseFiat(r *http.Request) (i18n.FiatCurrency, bool) {
+	raw := r.URL.Query().Get("fiat")
+	if raw == "" {
+		return i18n.FiatUSD, true
+	}
+	return i18n.ParseFiatCurrency(raw)
+}
+
+func writeJSON(w http.ResponseWriter, status int, payload any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	if err := json.NewEncoder(w).Encode(payload); err != nil {
+		log.Printf("server: encode response failed err=%v", err)
+	}
+}
+
+func writeEvent(w http.ResponseWriter, name string, payload any) error {
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintf(w, "event: %s\ndata: %s\n\n", name, data)
+	return err
+}
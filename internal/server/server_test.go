@@ -0,0 +1,208 @@
+package server
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"cryptoview/internal/metrics"
+	"cryptoview/internal/model"
+	"cryptoview/internal/service/marketfeed"
+	"cryptoview/internal/ui/i18n"
+)
+
+type fakeSource struct {
+	mu     sync.Mutex
+	prices map[i18n.FiatCurrency]float64
+	states []marketfeed.ProviderState
+}
+
+func (s *fakeSource) Quotes(fiat i18n.FiatCurrency) ([]model.Coin, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	price, ok := s.prices[fiat]
+	if !ok {
+		return nil, false
+	}
+	return []model.Coin{{ID: "bitcoin", Name: "Bitcoin", Ticker: "BTC", Price: price, PriceUSD: s.prices[i18n.FiatUSD], Change24h: 1.5}}, true
+}
+
+func (s *fakeSource) ProviderStates() []marketfeed.ProviderState {
+	return s.states
+}
+
+func (s *fakeSource) setPrice(fiat i18n.FiatCurrency, price float64) {
+	s.mu.Lock()
+	s.prices[fiat] = price
+	s.mu.Unlock()
+}
+
+func TestQuotesEndpoint(t *testing.T) {
+	srv := New()
+	srv.SetSource(&fakeSource{prices: map[i18n.FiatCurrency]float64{i18n.FiatUSD: 100, i18n.FiatEUR: 90}})
+	handler := srv.Handler()
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/v1/quotes?fiat=eur", nil))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", rec.Code, rec.Body)
+	}
+	var payload quotesJSON
+	if err := json.Unmarshal(rec.Body.Bytes(), &payload); err != nil {
+		t.Fatal(err)
+	}
+	if payload.Fiat != "EUR" || len(payload.Quotes) != 1 || payload.Quotes[0].Price != 90 || payload.Quotes[0].PriceUSD != 100 {
+		t.Fatalf("unexpected payload %+v", payload)
+	}
+
+	rec = httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/v1/quotes?fiat=XYZ", nil))
+	if rec.Code != http.StatusBadRequest {
+		t.Fatalf("expected 400 for unsupported fiat, got %d", rec.Code)
+	}
+	rec = httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/v1/quotes?fiat=RUB", nil))
+	if rec.Code != http.StatusServiceUnavailable {
+		t.Fatalf("expected 503 without data, got %d", rec.Code)
+	}
+	rec = httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/v1/quotes", nil))
+	if rec.Code != http.StatusMethodNotAllowed {
+		t.Fatalf("expected 405 for POST, got %d", rec.Code)
+	}
+}
+
+func TestStatusEndpointReportsLatestEventAndCooldowns(t *testing.T) {
+	srv := New()
+	srv.SetSource(&fakeSource{states: []marketfeed.ProviderState{
+		{Name: "coingecko", Circuit: marketfeed.CircuitOpen, ConsecutiveFailures: 2, CooldownUntil: time.Now().Add(10 * time.Second), BudgetPerMinute: 25, BudgetRemaining: 0},
+		{Name: "cryptocompare"},
+	}})
+	srv.Callbacks(marketfeed.Callbacks{}).OnStatus(marketfeed.StatusEvent{
+		Kind: marketfeed.StatusKindWarning,
+		Code: marketfeed.StatusCodeRateLimited,
+		Err:  errors.New("429"),
+	})
+	srv.Callbacks(marketfeed.Callbacks{}).OnStatus(marketfeed.StatusEvent{
+		Kind:     marketfeed.StatusKindCircuit,
+		Code:     marketfeed.StatusCodeCircuitOpen,
+		Provider: "coingecko",
+	})
+
+	rec := httptest.NewRecorder()
+	srv.Handler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/v1/status", nil))
+	var payload statusJSON
+	if err := json.Unmarshal(rec.Body.Bytes(), &payload); err != nil {
+		t.Fatal(err)
+	}
+	if payload.Kind != "warning" || payload.Code != "rate_limited" || payload.Error != "429" || payload.UpdatedAt == nil {
+		t.Fatalf("unexpected status %+v", payload)
+	}
+	if len(payload.Providers) != 2 || payload.Providers[0].Circuit != "open" || !payload.Providers[0].CoolingDown || payload.Providers[0].CooldownRemaining <= 0 {
+		t.Fatalf("expected cooldown state for first provider, got %+v", payload.Providers)
+	}
+	if payload.Providers[1].CoolingDown || payload.Providers[1].CooldownUntil != nil {
+		t.Fatalf("expected healthy second provider, got %+v", payload.Providers[1])
+	}
+	if payload.Providers[0].BudgetPerMinute != 25 || payload.Providers[0].BudgetRemaining == nil || *payload.Providers[0].BudgetRemaining != 0 {
+		t.Fatalf("expected exhausted budget to be reported, got %+v", payload.Providers[0])
+	}
+	if payload.Providers[1].BudgetRemaining != nil {
+		t.Fatalf("expected unlimited provider without budget, got %+v", payload.Providers[1])
+	}
+}
+
+func TestCallbacksForwardToNext(t *testing.T) {
+	srv := New()
+	var gotCoins []model.Coin
+	var gotStatus marketfeed.StatusEvent
+	callbacks := srv.Callbacks(marketfeed.Callbacks{
+		OnMarketUpdate: func(coins []model.Coin) { gotCoins = coins },
+		OnStatus:       func(event marketfeed.StatusEvent) { gotStatus = event },
+	})
+	callbacks.OnMarketUpdate([]model.Coin{{ID: "bitcoin"}})
+	callbacks.OnStatus(marketfeed.StatusEvent{Kind: marketfeed.StatusKindOK})
+	if len(gotCoins) != 1 || gotStatus.Kind != marketfeed.StatusKindOK {
+		t.Fatalf("expected callbacks to be forwarded, got %v %+v", gotCoins, gotStatus)
+	}
+}
+
+func TestStreamPushesMarketUpdates(t *testing.T) {
+	source := &fakeSource{prices: map[i18n.FiatCurrency]float64{i18n.FiatUSD: 100}}
+	srv := New()
+	srv.SetSource(source)
+	callbacks := srv.Callbacks(marketfeed.Callbacks{})
+	ts := httptest.NewServer(srv.Handler())
+	defer ts.Close()
+	defer srv.Shutdown(context.Background())
+
+	resp, err := http.Get(ts.URL + "/v1/stream")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	if ct := resp.Header.Get("Content-Type"); ct != "text/event-stream" {
+		t.Fatalf("unexpected content type %q", ct)
+	}
+
+	events := make(chan string, 8)
+	go func() {
+		scanner := bufio.NewScanner(resp.Body)
+		var name string
+		for scanner.Scan() {
+			line := scanner.Text()
+			switch {
+			case strings.HasPrefix(line, "event: "):
+				name = strings.TrimPrefix(line, "event: ")
+			case strings.HasPrefix(line, "data: "):
+				events <- name + " " + strings.TrimPrefix(line, "data: ")
+			}
+		}
+		close(events)
+	}()
+
+	next := func() string {
+		select {
+		case ev := <-events:
+			return ev
+		case <-time.After(2 * time.Second):
+			t.Fatal("timed out waiting for stream event")
+			return ""
+		}
+	}
+	if ev := next(); !strings.HasPrefix(ev, "status ") {
+		t.Fatalf("expected initial status event, got %q", ev)
+	}
+	if ev := next(); !strings.HasPrefix(ev, "quotes ") || !strings.Contains(ev, `"price":100`) {
+		t.Fatalf("expected initial quotes event, got %q", ev)
+	}
+
+	source.setPrice(i18n.FiatUSD, 105)
+	callbacks.OnMarketUpdate(nil)
+	if ev := next(); !strings.HasPrefix(ev, "quotes ") || !strings.Contains(ev, `"price":105`) {
+		t.Fatalf("expected pushed quotes event, got %q", ev)
+	}
+}
+
+func TestMetricsEndpointServesRegistry(t *testing.T) {
+	registry := metrics.NewRegistry()
+	registry.Counter("cryptoview_test_total", "Test counter.", "provider").Inc("coingecko")
+	srv := New()
+	srv.SetMetrics(registry)
+
+	rec := httptest.NewRecorder()
+	srv.Handler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	if rec.Code != http.StatusOK || !strings.HasPrefix(rec.Header().Get("Content-Type"), "text/plain") {
+		t.Fatalf("unexpected response %d %q", rec.Code, rec.Header().Get("Content-Type"))
+	}
+	if !strings.Contains(rec.Body.String(), `cryptoview_test_total{provider="coingecko"} 1`) {
+		t.Fatalf("unexpected metrics body:\n%s", rec.Body)
+	}
+}
@@ -0,0 +1,495 @@
+package alerts
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"cryptoview/internal/atomicfile"
+	"cryptoview/internal/model"
+	"cryptoview/internal/ui/i18n"
+)
+
+type Kind string
+
+const (
+	KindAbove       Kind = "above"
+	KindBelow       Kind = "below"
+	KindPercentMove Kind = "percent_move"
+	KindCrossOpen   Kind = "cross_open"
+)
+
+const (
+	fileVersion       = 1
+	defaultCooldown   = 15 * time.Minute
+	defaultHysteresis = 0.005
+	maxHistory        = 100
+	maxSampleWindow   = 24 * time.Hour
+	// Streaming updates arrive several times a second; one low/high bucket
+	// per sampleSpacing bounds a 24h window to a few thousand samples.
+	sampleSpacing = 15 * time.Second
+)
+
+type Rule struct {
+	ID        string            `json:"id"`
+	CoinID    string            `json:"coin_id"`
+	Kind      Kind              `json:"kind"`
+	Threshold float64           `json:"threshold,omitempty"`
+	Fiat      i18n.FiatCurrency `json:"fiat,omitempty"`
+	Percent   float64           `json:"percent,omitempty"`
+	Window    time.Duration     `json:"window,omitempty"`
+	Cooldown  time.Duration     `json:"cooldown,omitempty"`
+	Disabled  bool              `json:"disabled,omitempty"`
+}
+
+type Alert struct {
+	RuleID string
+	CoinID string
+	Ticker string
+	Kind   Kind
+	Rule   Rule
+	Price  float64
+	Fiat   i18n.FiatCurrency
+	Change float64
+	Up     bool
+	At     time.Time
+}
+
+type ruleState struct {
+	armed     bool
+	primed    bool
+	side      int
+	lastFired time.Time
+}
+
+type sample struct {
+	at        time.Time
+	low, high float64
+}
+
+type Engine struct {
+	mu      sync.Mutex
+	path    string
+	rules   []Rule
+	state   map[string]*ruleState
+	samples map[string][]sample
+	rates   map[i18n.FiatCurrency]float64
+	history []Alert
+}
+
+type rulesFile struct {
+	Version int    `json:"version"`
+	Rules   []Rule `json:"rules"`
+}
+
+func DefaultPath() (string, error) {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "CryptoView", "alerts.json"), nil
+}
+
+func NewEngine(path string) *Engine {
+	return &Engine{
+		path:    path,
+		state:   make(map[string]*ruleState),
+		samples: make(map[string][]sample),
+		rates:   map[i18n.FiatCurrency]float64{i18n.FiatUSD: 1},
+	}
+}
+
+func (e *Engine) Load() error {
+	if e.path == "" {
+		return nil
+	}
+	data, err := os.ReadFile(e.path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("alerts: read: %w", err)
+	}
+	var payload rulesFile
+	if err := json.Unmarshal(data, &payload); err != nil {
+		return fmt.Errorf("alerts: decode: %w", err)
+	}
+	if payload.Version != fileVersion {
+		return fmt.Errorf("alerts: unsupported version %d", payload.Version)
+	}
+	rules := make([]Rule, 0, len(payload.Rules))
+	for _, r := range payload.Rules {
+		r, err := normalizeRule(r)
+		if err != nil {
+			continue
+		}
+		rules = append(rules, r)
+	}
+	e.mu.Lock()
+	e.rules = rules
+	e.state = make(map[string]*ruleState, len(rules))
+	e.mu.Unlock()
+	return nil
+}
+
+func (e *Engine) Rules() []Rule {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	out := make([]Rule, len(e.rules))
+	copy(out, e.rules)
+	return out
+}
+
+func (e *Engine) AddRule(rule Rule) (Rule, error) {
+	rule, err := normalizeRule(rule)
+	if err != nil {
+		return Rule{}, err
+	}
+	if rule.ID == "" {
+		rule.ID = newRuleID()
+	}
+	e.mu.Lock()
+	e.rules = append(e.rules, rule)
+	e.mu.Unlock()
+	return rule, e.save()
+}
+
+func (e *Engine) RemoveRule(id string) error {
+	e.mu.Lock()
+	kept := e.rules[:0]
+	removed := false
+	for _, r := range e.rules {
+		if r.ID == id {
+			removed = true
+			continue
+		}
+		kept = append(kept, r)
+	}
+	e.rules = kept
+	delete(e.state, id)
+	e.mu.Unlock()
+	if !removed {
+		return nil
+	}
+	return e.save()
+}
+
+func (e *Engine) History() []Alert {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	out := make([]Alert, len(e.history))
+	for i, a := range e.history {
+		out[len(out)-1-i] = a
+	}
+	return out
+}
+
+// SetRates updates the USD-based rates used to compare price rules set in
+// a currency other than the displayed one.
+func (e *Engine) SetRates(rates map[i18n.FiatCurrency]float64) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	for fiat, rate := range rates {
+		if rate > 0 {
+			e.rates[fiat] = rate
+		}
+	}
+}
+
+// WaitingForRate reports a price rule that can't be checked yet because
+// there is no exchange rate for its currency.
+func (e *Engine) WaitingForRate(rule Rule) bool {
+	if rule.Kind != KindAbove && rule.Kind != KindBelow {
+		return false
+	}
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	return e.rates[rule.Fiat] <= 0
+}
+
+func (e *Engine) Evaluate(coins []model.Coin, fiat i18n.FiatCurrency, now time.Time) []Alert {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	sampled := make(map[string]bool)
+	for _, rule := range e.rules {
+		if rule.Kind == KindPercentMove && !rule.Disabled {
+			sampled[rule.CoinID] = true
+		}
+	}
+	for id := range e.samples {
+		if !sampled[id] {
+			delete(e.samples, id)
+		}
+	}
+
+	byID := make(map[string]model.Coin, len(coins))
+	for _, c := range coins {
+		if c.Price <= 0 {
+			continue
+		}
+		byID[c.ID] = c
+		if info, ok := i18n.LookupCurrency(fiat); ok && !info.IsCrypto() && c.PriceUSD > 0 {
+			e.rates[fiat] = c.Price / c.PriceUSD
+		}
+		if sampled[c.ID] {
+			e.recordSampleLocked(c.ID, usdPrice(c), now)
+		}
+	}
+	e.refreshCryptoRatesLocked(byID)
+
+	var fired []Alert
+	for _, rule := range e.rules {
+		if rule.Disabled {
+			continue
+		}
+		coin, ok := byID[rule.CoinID]
+		if !ok {
+			continue
+		}
+		st := e.state[rule.ID]
+		if st == nil {
+			st = &ruleState{armed: true}
+			e.state[rule.ID] = st
+		}
+		alert, ok := e.evaluateRuleLocked(rule, st, coin, fiat, now)
+		if !ok {
+			continue
+		}
+		cooldown := rule.Cooldown
+		if cooldown <= 0 {
+			cooldown = defaultCooldown
+		}
+		if !st.lastFired.IsZero() && now.Sub(st.lastFired) < cooldown {
+			continue
+		}
+		st.lastFired = now
+		fired = append(fired, alert)
+		e.history = append(e.history, alert)
+		if len(e.history) > maxHistory {
+			e.history = e.history[len(e.history)-maxHistory:]
+		}
+	}
+	return fired
+}
+
+// refreshCryptoRatesLocked derives the rates of crypto quote currencies from
+// the reference coin in this batch. They move with every tick, so a rate is
+// dropped rather than kept when the coin is missing.
+func (e *Engine) refreshCryptoRatesLocked(byID map[string]model.Coin) {
+	for _, rule := range e.rules {
+		info, ok := i18n.LookupCurrency(rule.Fiat)
+		if !ok || !info.IsCrypto() {
+			continue
+		}
+		coin, ok := byID[info.CoinID]
+		if !ok || usdPrice(coin) <= 0 {
+			delete(e.rates, rule.Fiat)
+			continue
+		}
+		e.rates[rule.Fiat] = info.Units / usdPrice(coin)
+	}
+}
+
+func (e *Engine) evaluateRuleLocked(rule Rule, st *ruleState, coin model.Coin, fiat i18n.FiatCurrency, now time.Time) (Alert, bool) {
+	price := usdPrice(coin)
+	alert := Alert{
+		RuleID: rule.ID,
+		CoinID: coin.ID,
+		Ticker: coin.Ticker,
+		Kind:   rule.Kind,
+		Rule:   rule,
+		Price:  coin.Price,
+		Fiat:   fiat,
+		At:     now,
+	}
+
+	switch rule.Kind {
+	case KindAbove, KindBelow:
+		rate, ok := e.rates[rule.Fiat]
+		if !ok || rate <= 0 {
+			return Alert{}, false
+		}
+		threshold := rule.Threshold / rate
+		band := threshold * defaultHysteresis
+		triggered := price >= threshold
+		rearmed := price < threshold-band
+		if rule.Kind == KindBelow {
+			triggered = price <= threshold
+			rearmed = price > threshold+band
+		}
+		alert.Up = rule.Kind == KindAbove
+		return alert, latch(st, triggered, rearmed)
+
+	case KindPercentMove:
+		ref, ok := e.referencePriceLocked(coin.ID, rule, now)
+		if !ok {
+			return Alert{}, false
+		}
+		change := (price - ref) / ref * 100
+		target := rule.Percent
+		triggered := change >= target
+		rearmed := change < target/2
+		if target < 0 {
+			triggered = change <= target
+			rearmed = change > target/2
+		}
+		alert.Change = change
+		alert.Up = change > 0
+		return alert, latch(st, triggered, rearmed)
+
+	case KindCrossOpen:
+		open := price / (1 + coin.Change24h/100)
+		if open <= 0 {
+			return Alert{}, false
+		}
+		band := open * defaultHysteresis
+		side := st.side
+		switch {
+		case price > open+band:
+			side = 1
+		case price < open-band:
+			side = -1
+		}
+		if !st.primed {
+			st.primed = true
+			st.side = side
+			return Alert{}, false
+		}
+		crossed := side != 0 && st.side != 0 && side != st.side
+		if side != 0 {
+			st.side = side
+		}
+		alert.Up = side > 0
+		alert.Change = coin.Change24h
+		return alert, crossed
+	}
+	return Alert{}, false
+}
+
+func latch(st *ruleState, triggered, rearmed bool) bool {
+	if st.armed && triggered {
+		st.armed = false
+		return true
+	}
+	if !st.armed && rearmed {
+		st.armed = true
+	}
+	return false
+}
+
+func (e *Engine) referencePriceLocked(coinID string, rule Rule, now time.Time) (float64, bool) {
+	window := rule.Window
+	if window <= 0 {
+		window = time.Hour
+	}
+	samples := e.samples[coinID]
+	if len(samples) < 2 {
+		return 0, false
+	}
+	ref := 0.0
+	for _, s := range samples[firstSampleAfter(samples, now.Add(-window)):] {
+		switch {
+		case ref == 0 && rule.Percent < 0:
+			ref = s.high
+		case ref == 0:
+			ref = s.low
+		case rule.Percent < 0:
+			ref = math.Max(ref, s.high)
+		default:
+			ref = math.Min(ref, s.low)
+		}
+	}
+	return ref, ref > 0
+}
+
+func (e *Engine) recordSampleLocked(coinID string, price float64, now time.Time) {
+	samples := e.samples[coinID]
+	if n := len(samples); n > 0 && now.Sub(samples[n-1].at) < sampleSpacing {
+		last := &samples[n-1]
+		last.low = math.Min(last.low, price)
+		last.high = math.Max(last.high, price)
+		return
+	}
+	samples = append(samples, sample{at: now, low: price, high: price})
+	e.samples[coinID] = samples[firstSampleAfter(samples, now.Add(-maxSampleWindow)):]
+}
+
+// firstSampleAfter returns the index of the first sample not older than
+// cutoff; samples are kept in time order.
+func firstSampleAfter(samples []sample, cutoff time.Time) int {
+	return sort.Search(len(samples), func(i int) bool {
+		return !samples[i].at.Before(cutoff)
+	})
+}
+
+func (e *Engine) save() error {
+	if e.path == "" {
+		return nil
+	}
+	data, err := json.MarshalIndent(rulesFile{Version: fileVersion, Rules: e.Rules()}, "", "  ")
+	if err != nil {
+		return fmt.Errorf("alerts: encode: %w", err)
+	}
+	if err := atomicfile.Write(e.path, data, 0o600); err != nil {
+		return fmt.Errorf("alerts: save: %w", err)
+	}
+	return nil
+}
+
+func normalizeRule(r Rule) (Rule, error) {
+	r.CoinID = strings.ToLower(strings.TrimSpace(r.CoinID))
+	if r.CoinID == "" {
+		return r, errors.New("alerts: coin id is required")
+	}
+	switch r.Kind {
+	case KindAbove, KindBelow:
+		if r.Threshold <= 0 {
+			return r, errors.New("alerts: threshold must be positive")
+		}
+		if _, ok := i18n.ParseFiatCurrency(string(r.Fiat)); !ok {
+			r.Fiat = i18n.FiatUSD
+		}
+	case KindPercentMove:
+		if r.Percent == 0 {
+			return r, errors.New("alerts: percent must be non-zero")
+		}
+		if r.Window <= 0 {
+			r.Window = time.Hour
+		}
+		if r.Window > maxSampleWindow {
+			r.Window = maxSampleWindow
+		}
+	case KindCrossOpen:
+	default:
+		return r, fmt.Errorf("alerts: unknown rule kind %q", r.Kind)
+	}
+	if r.Cooldown < 0 {
+		r.Cooldown = 0
+	}
+	return r, nil
+}
+
+func usdPrice(c model.Coin) float64 {
+	if c.PriceUSD > 0 {
+		return c.PriceUSD
+	}
+	return c.Price
+}
+
+func newRuleID() string {
+	buf := make([]byte, 6)
+	if _, err := rand.Read(buf); err != nil {
+		return fmt.Sprintf("rule-%d", time.Now().UnixNano())
+	}
+	return hex.EncodeToString(buf)
+}
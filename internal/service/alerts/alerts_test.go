@@ -0,0 +1,203 @@
+package alerts
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"cryptoview/internal/model"
+	"cryptoview/internal/ui/i18n"
+)
+
+func btc(price, change float64) []model.Coin {
+	return []model.Coin{{ID: "bitcoin", Ticker: "BTC", Price: price, PriceUSD: price, Change24h: change}}
+}
+
+func TestThresholdRuleHysteresisAndCooldown(t *testing.T) {
+	e := NewEngine("")
+	if _, err := e.AddRule(Rule{CoinID: "bitcoin", Kind: KindAbove, Threshold: 100000, Fiat: i18n.FiatUSD, Cooldown: time.Minute}); err != nil {
+		t.Fatal(err)
+	}
+	now := time.Unix(1700000000, 0)
+
+	if got := e.Evaluate(btc(99000, 0), i18n.FiatUSD, now); len(got) != 0 {
+		t.Fatalf("expected no alert below threshold, got %+v", got)
+	}
+	if got := e.Evaluate(btc(100100, 0), i18n.FiatUSD, now.Add(time.Second)); len(got) != 1 || !got[0].Up {
+		t.Fatalf("expected alert when crossing above, got %+v", got)
+	}
+	if got := e.Evaluate(btc(99900, 0), i18n.FiatUSD, now.Add(2*time.Second)); len(got) != 0 {
+		t.Fatalf("expected dip inside hysteresis band not to re-arm, got %+v", got)
+	}
+	if got := e.Evaluate(btc(100200, 0), i18n.FiatUSD, now.Add(3*time.Second)); len(got) != 0 {
+		t.Fatalf("expected no refire while latched, got %+v", got)
+	}
+	e.Evaluate(btc(90000, 0), i18n.FiatUSD, now.Add(4*time.Second))
+	if got := e.Evaluate(btc(100500, 0), i18n.FiatUSD, now.Add(5*time.Second)); len(got) != 0 {
+		t.Fatalf("expected cooldown to suppress refire, got %+v", got)
+	}
+	e.Evaluate(btc(90000, 0), i18n.FiatUSD, now.Add(2*time.Minute))
+	if got := e.Evaluate(btc(100500, 0), i18n.FiatUSD, now.Add(3*time.Minute)); len(got) != 1 {
+		t.Fatalf("expected rule to fire again after cooldown, got %+v", got)
+	}
+	if len(e.History()) != 2 {
+		t.Fatalf("expected two alerts in history, got %d", len(e.History()))
+	}
+}
+
+func TestThresholdRuleUsesRuleFiat(t *testing.T) {
+	e := NewEngine("")
+	if _, err := e.AddRule(Rule{CoinID: "bitcoin", Kind: KindBelow, Threshold: 45000, Fiat: i18n.FiatEUR}); err != nil {
+		t.Fatal(err)
+	}
+	now := time.Unix(1700000000, 0)
+	coins := []model.Coin{{ID: "bitcoin", Ticker: "BTC", Price: 44100, PriceUSD: 49000}}
+	if got := e.Evaluate(coins, i18n.FiatEUR, now); len(got) != 1 {
+		t.Fatalf("expected EUR threshold to fire, got %+v", got)
+	}
+}
+
+func TestThresholdRuleInHiddenFiatWaitsForFeedRate(t *testing.T) {
+	e := NewEngine("")
+	rule, err := e.AddRule(Rule{CoinID: "bitcoin", Kind: KindAbove, Threshold: 90000, Fiat: i18n.FiatEUR})
+	if err != nil {
+		t.Fatal(err)
+	}
+	now := time.Unix(1700000000, 0)
+	if got := e.Evaluate(btc(100000, 0), i18n.FiatUSD, now); len(got) != 0 {
+		t.Fatalf("expected no alert without an EUR rate, got %+v", got)
+	}
+	if !e.WaitingForRate(rule) {
+		t.Fatal("expected rule to report it is waiting for a rate")
+	}
+
+	e.SetRates(map[i18n.FiatCurrency]float64{i18n.FiatUSD: 1, i18n.FiatEUR: 0.92})
+	if e.WaitingForRate(rule) {
+		t.Fatal("expected rate from the feed to unblock the rule")
+	}
+	if got := e.Evaluate(btc(100000, 0), i18n.FiatUSD, now.Add(time.Second)); len(got) != 1 {
+		t.Fatalf("expected EUR rule to fire while USD is displayed, got %+v", got)
+	}
+}
+
+func TestCryptoQuotedRuleFollowsReferenceCoinAcrossDisplayFiats(t *testing.T) {
+	e := NewEngine("")
+	inBTC, err := e.AddRule(Rule{CoinID: "ethereum", Kind: KindAbove, Threshold: 0.05, Fiat: i18n.QuoteBTC})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := e.AddRule(Rule{CoinID: "ethereum", Kind: KindBelow, Threshold: 5_000_000, Fiat: i18n.QuoteSATS}); err != nil {
+		t.Fatal(err)
+	}
+	coins := func(btcUSD, ethUSD, rate float64) []model.Coin {
+		return []model.Coin{
+			{ID: "bitcoin", Ticker: "BTC", Price: btcUSD * rate, PriceUSD: btcUSD},
+			{ID: "ethereum", Ticker: "ETH", Price: ethUSD * rate, PriceUSD: ethUSD},
+		}
+	}
+	now := time.Unix(1700000000, 0)
+	if got := e.Evaluate(coins(100000, 4000, 1.0/100000), i18n.QuoteBTC, now); len(got) != 1 || got[0].Kind != KindBelow {
+		t.Fatalf("expected only the sats rule at 0.04 BTC, got %+v", got)
+	}
+
+	got := e.Evaluate(coins(50000, 3000, 1), i18n.FiatUSD, now.Add(time.Second))
+	if len(got) != 1 || got[0].RuleID != inBTC.ID {
+		t.Fatalf("expected the BTC rule to use the current BTC price after switching to USD, got %+v", got)
+	}
+
+	e.Evaluate([]model.Coin{{ID: "ethereum", Ticker: "ETH", Price: 3000, PriceUSD: 3000}}, i18n.FiatUSD, now.Add(2*time.Second))
+	if !e.WaitingForRate(inBTC) {
+		t.Fatal("expected the BTC rule to wait while bitcoin is missing from the batch")
+	}
+}
+
+func TestPercentMoveSamplesAreBucketedPerRuleCoin(t *testing.T) {
+	e := NewEngine("")
+	if _, err := e.AddRule(Rule{CoinID: "bitcoin", Kind: KindPercentMove, Percent: 5, Window: time.Hour}); err != nil {
+		t.Fatal(err)
+	}
+	start := time.Unix(1700000000, 0)
+	coins := append(btc(100, 0), model.Coin{ID: "ethereum", Ticker: "ETH", Price: 2000, PriceUSD: 2000})
+	for i := 0; i < 4*60*30; i++ {
+		coins[0].Price = 100 + float64(i%4)
+		coins[0].PriceUSD = coins[0].Price
+		e.Evaluate(coins, i18n.FiatUSD, start.Add(time.Duration(i)*250*time.Millisecond))
+	}
+	if got, max := len(e.samples["bitcoin"]), int(30*time.Minute/sampleSpacing)+1; got > max {
+		t.Fatalf("expected at most %d bucketed samples, got %d", max, got)
+	}
+	if _, ok := e.samples["ethereum"]; ok {
+		t.Fatal("expected no samples for a coin without percent-move rules")
+	}
+
+	now := start.Add(30 * time.Minute)
+	if got := e.Evaluate(btc(105.5, 0), i18n.FiatUSD, now); len(got) != 1 || got[0].Change < 5 {
+		t.Fatalf("expected move from the in-window low to fire, got %+v", got)
+	}
+}
+
+func TestPercentMoveRuleUsesWindowPeak(t *testing.T) {
+	e := NewEngine("")
+	if _, err := e.AddRule(Rule{CoinID: "ethereum", Kind: KindPercentMove, Percent: -5, Window: time.Hour}); err != nil {
+		t.Fatal(err)
+	}
+	now := time.Unix(1700000000, 0)
+	eth := func(price float64) []model.Coin {
+		return []model.Coin{{ID: "ethereum", Ticker: "ETH", Price: price, PriceUSD: price}}
+	}
+	e.Evaluate(eth(2000), i18n.FiatUSD, now.Add(-2*time.Hour))
+	e.Evaluate(eth(2100), i18n.FiatUSD, now.Add(-30*time.Minute))
+	if got := e.Evaluate(eth(2010), i18n.FiatUSD, now.Add(-10*time.Minute)); len(got) != 0 {
+		t.Fatalf("expected no alert for small drop, got %+v", got)
+	}
+	got := e.Evaluate(eth(1990), i18n.FiatUSD, now)
+	if len(got) != 1 || got[0].Change > -5 || got[0].Up {
+		t.Fatalf("expected drop alert from in-window peak, got %+v", got)
+	}
+}
+
+func TestCrossOpenRuleFiresOnSideChange(t *testing.T) {
+	e := NewEngine("")
+	if _, err := e.AddRule(Rule{CoinID: "bitcoin", Kind: KindCrossOpen, Cooldown: time.Second}); err != nil {
+		t.Fatal(err)
+	}
+	now := time.Unix(1700000000, 0)
+	if got := e.Evaluate(btc(99, -1), i18n.FiatUSD, now); len(got) != 0 {
+		t.Fatalf("expected first observation to only prime the rule, got %+v", got)
+	}
+	if got := e.Evaluate(btc(100.2, 0.2), i18n.FiatUSD, now.Add(time.Minute)); len(got) != 0 {
+		t.Fatalf("expected move inside band to be ignored, got %+v", got)
+	}
+	got := e.Evaluate(btc(102, 2), i18n.FiatUSD, now.Add(2*time.Minute))
+	if len(got) != 1 || !got[0].Up {
+		t.Fatalf("expected upward cross alert, got %+v", got)
+	}
+}
+
+func TestRulesPersistAcrossLoads(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "alerts.json")
+	e := NewEngine(path)
+	rule, err := e.AddRule(Rule{CoinID: " SOLANA ", Kind: KindCrossOpen})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := e.AddRule(Rule{CoinID: "bitcoin", Kind: KindAbove}); err == nil {
+		t.Fatal("expected threshold rule without threshold to be rejected")
+	}
+
+	reloaded := NewEngine(path)
+	if err := reloaded.Load(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	rules := reloaded.Rules()
+	if len(rules) != 1 || rules[0].ID != rule.ID || rules[0].CoinID != "solana" {
+		t.Fatalf("unexpected reloaded rules %+v", rules)
+	}
+	if err := reloaded.RemoveRule(rule.ID); err != nil {
+		t.Fatal(err)
+	}
+	again := NewEngine(path)
+	if err := again.Load(); err != nil || len(again.Rules()) != 0 {
+		t.Fatalf("expected removal to persist, got %+v err=%v", again.Rules(), err)
+	}
+}
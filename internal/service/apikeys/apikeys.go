@@ -0,0 +1,159 @@
+package apikeys
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/zalando/go-keyring"
+)
+
+const (
+	// Service names the keychain entries; each provider is one account.
+	Service       = "CryptoView"
+	legacyVersion = 1
+)
+
+// Store keeps provider API keys in the OS keychain: Keychain on macOS,
+// Credential Manager on Windows and the Secret Service on Linux. Keys are
+// never written to the preferences or to plain files.
+type Store struct {
+	mu     sync.RWMutex
+	legacy string
+	keys   map[string]string
+}
+
+type legacyFile struct {
+	Version int               `json:"version"`
+	Keys    map[string]string `json:"keys"`
+}
+
+// LegacyPath is where earlier versions kept the keys in plain JSON.
+func LegacyPath() (string, error) {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "CryptoView", "api_keys.json"), nil
+}
+
+// New returns a store that migrates the plain JSON file at legacyPath into
+// the keychain on Load. An empty path skips the migration.
+func New(legacyPath string) *Store {
+	return &Store{legacy: legacyPath, keys: make(map[string]string)}
+}
+
+// Load reads the keys of providers from the keychain. The keychain cannot
+// list its entries, so callers name the providers that take a key.
+func (s *Store) Load(providers []string) error {
+	keys := make(map[string]string, len(providers))
+	var errs []error
+	for _, provider := range providers {
+		key, err := keyring.Get(Service, provider)
+		if errors.Is(err, keyring.ErrNotFound) {
+			continue
+		}
+		if err != nil {
+			errs = append(errs, fmt.Errorf("apikeys: read %s: %w", provider, err))
+			continue
+		}
+		if key = strings.TrimSpace(key); key != "" {
+			keys[provider] = key
+		}
+	}
+	s.mu.Lock()
+	s.keys = keys
+	s.mu.Unlock()
+	if err := s.migrate(); err != nil {
+		errs = append(errs, err)
+	}
+	return errors.Join(errs...)
+}
+
+// migrate moves keys from the legacy file into the keychain and deletes the
+// file once every key is stored. On failure the file is left in place and
+// its keys stay usable for this session.
+func (s *Store) migrate() error {
+	if s.legacy == "" {
+		return nil
+	}
+	data, err := os.ReadFile(s.legacy)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("apikeys: read legacy file: %w", err)
+	}
+	var payload legacyFile
+	if err := json.Unmarshal(data, &payload); err != nil {
+		return fmt.Errorf("apikeys: decode legacy file: %w", err)
+	}
+	if payload.Version != legacyVersion {
+		return fmt.Errorf("apikeys: unsupported legacy version %d", payload.Version)
+	}
+	var errs []error
+	for provider, key := range payload.Keys {
+		if key = strings.TrimSpace(key); key == "" || s.Key(provider) != "" {
+			continue
+		}
+		s.mu.Lock()
+		s.keys[provider] = key
+		s.mu.Unlock()
+		if err := keyring.Set(Service, provider, key); err != nil {
+			errs = append(errs, fmt.Errorf("apikeys: migrate %s: %w", provider, err))
+		}
+	}
+	if len(errs) > 0 {
+		return errors.Join(errs...)
+	}
+	if err := os.Remove(s.legacy); err != nil {
+		return fmt.Errorf("apikeys: remove legacy file: %w", err)
+	}
+	log.Printf("apikeys: moved keys to the keychain path=%s keys=%d", s.legacy, len(payload.Keys))
+	return nil
+}
+
+func (s *Store) Key(provider string) string {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.keys[provider]
+}
+
+func (s *Store) Providers() []string {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	providers := make([]string, 0, len(s.keys))
+	for provider := range s.keys {
+		providers = append(providers, provider)
+	}
+	sort.Strings(providers)
+	return providers
+}
+
+// Set stores key for provider in the keychain; an empty key removes it. The
+// in-memory copy only changes once the keychain accepted the write.
+func (s *Store) Set(provider, key string) error {
+	key = strings.TrimSpace(key)
+	if key == "" {
+		if err := keyring.Delete(Service, provider); err != nil && !errors.Is(err, keyring.ErrNotFound) {
+			return fmt.Errorf("apikeys: delete %s: %w", provider, err)
+		}
+		s.mu.Lock()
+		delete(s.keys, provider)
+		s.mu.Unlock()
+		return nil
+	}
+	if err := keyring.Set(Service, provider, key); err != nil {
+		return fmt.Errorf("apikeys: save %s: %w", provider, err)
+	}
+	s.mu.Lock()
+	s.keys[provider] = key
+	s.mu.Unlock()
+	return nil
+}
@@ -0,0 +1,93 @@
+package apikeys
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/zalando/go-keyring"
+)
+
+func TestStoreKeepsKeysInKeychain(t *testing.T) {
+	keyring.MockInit()
+	store := New("")
+	if err := store.Set("coingecko", "  CG-secret  "); err != nil {
+		t.Fatalf("set: %v", err)
+	}
+	if err := store.Set("cryptocompare", "cc-secret"); err != nil {
+		t.Fatalf("set: %v", err)
+	}
+	if got, err := keyring.Get(Service, "coingecko"); err != nil || got != "CG-secret" {
+		t.Fatalf("expected trimmed key in the keychain, got %q err=%v", got, err)
+	}
+
+	loaded := New("")
+	if err := loaded.Load([]string{"coingecko", "cryptocompare", "coinlore"}); err != nil {
+		t.Fatalf("load: %v", err)
+	}
+	if got := strings.Join(loaded.Providers(), ","); got != "coingecko,cryptocompare" {
+		t.Fatalf("unexpected providers %q", got)
+	}
+
+	if err := loaded.Set("coingecko", ""); err != nil {
+		t.Fatalf("clear: %v", err)
+	}
+	if err := loaded.Set("coingecko", ""); err != nil {
+		t.Fatalf("clearing a missing key: %v", err)
+	}
+	reloaded := New("")
+	if err := reloaded.Load([]string{"coingecko", "cryptocompare"}); err != nil {
+		t.Fatalf("reload: %v", err)
+	}
+	if reloaded.Key("coingecko") != "" || reloaded.Key("cryptocompare") != "cc-secret" {
+		t.Fatalf("expected empty key to remove only that provider, got %v", reloaded.Providers())
+	}
+}
+
+func TestStoreMigratesLegacyFile(t *testing.T) {
+	keyring.MockInit()
+	path := filepath.Join(t.TempDir(), "api_keys.json")
+	if err := os.WriteFile(path, []byte(`{"version":1,"keys":{"coingecko":"k"}}`), 0o600); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	store := New(path)
+	if err := store.Load([]string{"coingecko"}); err != nil {
+		t.Fatalf("load: %v", err)
+	}
+	if store.Key("coingecko") != "k" {
+		t.Fatal("expected the legacy key to load")
+	}
+	if got, _ := keyring.Get(Service, "coingecko"); got != "k" {
+		t.Fatalf("expected the legacy key in the keychain, got %q", got)
+	}
+	if _, err := os.Stat(path); !errors.Is(err, os.ErrNotExist) {
+		t.Fatalf("expected the plain file removed, stat err=%v", err)
+	}
+}
+
+func TestStoreSurfacesKeychainFailures(t *testing.T) {
+	denied := errors.New("access denied")
+	keyring.MockInitWithError(denied)
+	path := filepath.Join(t.TempDir(), "api_keys.json")
+	if err := os.WriteFile(path, []byte(`{"version":1,"keys":{"coingecko":"k"}}`), 0o600); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	store := New(path)
+	if err := store.Load([]string{"coingecko"}); !errors.Is(err, denied) {
+		t.Fatalf("expected the keychain error from load, got %v", err)
+	}
+	if store.Key("coingecko") != "k" {
+		t.Fatal("expected the legacy key to stay usable for the session")
+	}
+	if _, err := os.Stat(path); err != nil {
+		t.Fatalf("expected the legacy file kept after a failed migration: %v", err)
+	}
+	if err := store.Set("cryptocompare", "secret"); !errors.Is(err, denied) {
+		t.Fatalf("expected the keychain error from set, got %v", err)
+	}
+	if store.Key("cryptocompare") != "" {
+		t.Fatal("expected a rejected key not to be kept")
+	}
+}
@@ -0,0 +1,358 @@
+package coinregistry
+
+import (
+	_ "embed"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+)
+
+type Provider string
+
+const (
+	ProviderCoinGecko     Provider = "coingecko"
+	ProviderCoinPaprika   Provider = "coinpaprika"
+	ProviderCoinCap       Provider = "coincap"
+	ProviderBinance       Provider = "binance"
+	ProviderCryptoCompare Provider = "cryptocompare"
+	ProviderCoinLore      Provider = "coinlore"
+)
+
+const binanceQuoteAsset = "USDT"
+
+var allProviders = []Provider{
+	ProviderCoinGecko,
+	ProviderCoinPaprika,
+	ProviderCoinCap,
+	ProviderBinance,
+	ProviderCryptoCompare,
+	ProviderCoinLore,
+}
+
+type Coin struct {
+	ID        string              `json:"id"`
+	Name      string              `json:"name"`
+	Ticker    string              `json:"ticker"`
+	Aliases   []string            `json:"aliases,omitempty"`
+	Providers map[Provider]string `json:"providers,omitempty"`
+}
+
+type Collision struct {
+	Provider Provider
+	Key      string
+	CoinIDs  []string
+}
+
+func (c Collision) String() string {
+	return fmt.Sprintf("%s key %q shared by %s", c.Provider, c.Key, strings.Join(c.CoinIDs, ", "))
+}
+
+type Registry struct {
+	coins      []Coin
+	byID       map[string]int
+	byKey      map[Provider]map[string][]string
+	collisions []Collision
+}
+
+type file struct {
+	Coins []Coin `json:"coins"`
+}
+
+//go:embed coins.json
+var embeddedCoins []byte
+
+var (
+	defaultOnce     sync.Once
+	defaultRegistry *Registry
+)
+
+func Default() *Registry {
+	defaultOnce.Do(func() {
+		reg, err := Parse(embeddedCoins)
+		if err != nil {
+			panic("coinregistry: invalid embedded registry: " + err.Error())
+		}
+		defaultRegistry = reg
+	})
+	return defaultRegistry
+}
+
+func Parse(data []byte) (*Registry, error) {
+	var payload file
+	if err := json.Unmarshal(data, &payload); err != nil {
+		return nil, fmt.Errorf("coinregistry: decode: %w", err)
+	}
+	return New(payload.Coins)
+}
+
+func Load(overridePath string) (*Registry, error) {
+	base := Default()
+	if strings.TrimSpace(overridePath) == "" {
+		return base, nil
+	}
+	data, err := os.ReadFile(overridePath)
+	if errors.Is(err, os.ErrNotExist) {
+		return base, nil
+	}
+	if err != nil {
+		return base, fmt.Errorf("coinregistry: read override: %w", err)
+	}
+	var payload file
+	if err := json.Unmarshal(data, &payload); err != nil {
+		return base, fmt.Errorf("coinregistry: decode override %s: %w", overridePath, err)
+	}
+	merged, err := New(mergeCoins(base.Coins(), payload.Coins))
+	if err != nil {
+		return base, err
+	}
+	return merged, nil
+}
+
+func UserOverridePath() (string, error) {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "CryptoView", "coins.json"), nil
+}
+
+func New(coins []Coin) (*Registry, error) {
+	r := &Registry{
+		coins: make([]Coin, 0, len(coins)),
+		byID:  make(map[string]int, len(coins)),
+		byKey: make(map[Provider]map[string][]string, len(allProviders)),
+	}
+	for _, raw := range coins {
+		coin := normalizeCoin(raw)
+		if coin.ID == "" {
+			return nil, fmt.Errorf("coinregistry: coin %q has empty id", raw.Name)
+		}
+		if _, dup := r.byID[coin.ID]; dup {
+			return nil, fmt.Errorf("coinregistry: duplicate coin id %q", coin.ID)
+		}
+		r.byID[coin.ID] = len(r.coins)
+		r.coins = append(r.coins, coin)
+	}
+	for _, provider := range allProviders {
+		keys := make(map[string][]string, len(r.coins))
+		for _, coin := range r.coins {
+			for _, key := range r.Keys(provider, coin) {
+				keys[key] = appendUnique(keys[key], coin.ID)
+			}
+		}
+		r.byKey[provider] = keys
+	}
+	r.collisions = detectCollisions(r.byKey)
+	return r, nil
+}
+
+func (r *Registry) Subset(coins []Coin) (*Registry, error) {
+	merged := make([]Coin, 0, len(coins))
+	for _, raw := range coins {
+		coin := normalizeCoin(raw)
+		if known, ok := r.Lookup(coin.ID); ok {
+			coin = mergeCoin(known, coin)
+		}
+		merged = append(merged, coin)
+	}
+	return New(merged)
+}
+
+func (r *Registry) Coins() []Coin {
+	coins := make([]Coin, len(r.coins))
+	copy(coins, r.coins)
+	return coins
+}
+
+func (r *Registry) Lookup(id string) (Coin, bool) {
+	idx, ok := r.byID[strings.ToLower(strings.TrimSpace(id))]
+	if !ok {
+		return Coin{}, false
+	}
+	return r.coins[idx], true
+}
+
+func (r *Registry) Collisions() []Collision {
+	collisions := make([]Collision, len(r.collisions))
+	copy(collisions, r.collisions)
+	return collisions
+}
+
+func (r *Registry) Resolve(provider Provider, key string) (string, bool) {
+	ids := r.byKey[provider][normalizeKey(key)]
+	if len(ids) != 1 {
+		return "", false
+	}
+	return ids[0], true
+}
+
+func (r *Registry) Keys(provider Provider, coin Coin) []string {
+	coin = normalizeCoin(coin)
+	if known, ok := r.Lookup(coin.ID); ok {
+		coin = mergeCoin(known, coin)
+	}
+	keys := []string{normalizeKey(r.ProviderKey(provider, coin))}
+	if providerKeyOnly(provider) {
+		return compactKeys(keys)
+	}
+	keys = append(keys, normalizeKey(coin.ID))
+	for _, alias := range coin.Aliases {
+		keys = append(keys, normalizeKey(alias))
+	}
+	return compactKeys(keys)
+}
+
+func (r *Registry) ProviderKey(provider Provider, coin Coin) string {
+	coin = normalizeCoin(coin)
+	if known, ok := r.Lookup(coin.ID); ok {
+		coin = mergeCoin(known, coin)
+	}
+	if key := coin.Providers[provider]; key != "" {
+		return key
+	}
+	switch provider {
+	case ProviderBinance:
+		if coin.Ticker == "" {
+			return ""
+		}
+		return coin.Ticker + binanceQuoteAsset
+	case ProviderCryptoCompare:
+		return coin.Ticker
+	case ProviderCoinLore:
+		// CoinLore tickers are looked up by numeric id, which can't be
+		// derived from the coin; it has to come from the registry.
+		return ""
+	case ProviderCoinPaprika:
+		if coin.Ticker == "" || coin.Name == "" {
+			return ""
+		}
+		return strings.ToLower(coin.Ticker) + "-" + strings.ToLower(strings.Join(strings.Fields(coin.Name), "-"))
+	default:
+		return coin.ID
+	}
+}
+
+// providerKeyOnly reports providers whose responses carry only their own
+// key (a symbol or numeric id), never the CoinGecko id or aliases.
+func providerKeyOnly(provider Provider) bool {
+	switch provider {
+	case ProviderBinance, ProviderCryptoCompare, ProviderCoinLore:
+		return true
+	default:
+		return false
+	}
+}
+
+func normalizeCoin(coin Coin) Coin {
+	out := Coin{
+		ID:     strings.ToLower(strings.TrimSpace(coin.ID)),
+		Name:   strings.TrimSpace(coin.Name),
+		Ticker: strings.ToUpper(strings.TrimSpace(coin.Ticker)),
+	}
+	for _, alias := range coin.Aliases {
+		if alias = strings.ToLower(strings.TrimSpace(alias)); alias != "" {
+			out.Aliases = append(out.Aliases, alias)
+		}
+	}
+	if len(coin.Providers) > 0 {
+		out.Providers = make(map[Provider]string, len(coin.Providers))
+		for provider, key := range coin.Providers {
+			if key = strings.TrimSpace(key); key != "" {
+				out.Providers[Provider(strings.ToLower(string(provider)))] = key
+			}
+		}
+	}
+	return out
+}
+
+func mergeCoin(base, override Coin) Coin {
+	merged := base
+	if override.Name != "" {
+		merged.Name = override.Name
+	}
+	if override.Ticker != "" {
+		merged.Ticker = override.Ticker
+	}
+	if len(override.Aliases) > 0 {
+		merged.Aliases = append(append([]string(nil), base.Aliases...), override.Aliases...)
+	}
+	if len(override.Providers) > 0 {
+		merged.Providers = make(map[Provider]string, len(base.Providers)+len(override.Providers))
+		for provider, key := range base.Providers {
+			merged.Providers[provider] = key
+		}
+		for provider, key := range override.Providers {
+			merged.Providers[provider] = key
+		}
+	}
+	return merged
+}
+
+func mergeCoins(base, overrides []Coin) []Coin {
+	merged := make([]Coin, len(base))
+	copy(merged, base)
+	index := make(map[string]int, len(base))
+	for i, coin := range merged {
+		index[coin.ID] = i
+	}
+	for _, raw := range overrides {
+		coin := normalizeCoin(raw)
+		if i, ok := index[coin.ID]; ok {
+			merged[i] = mergeCoin(merged[i], coin)
+			continue
+		}
+		index[coin.ID] = len(merged)
+		merged = append(merged, coin)
+	}
+	return merged
+}
+
+func detectCollisions(byKey map[Provider]map[string][]string) []Collision {
+	var collisions []Collision
+	for _, provider := range allProviders {
+		for key, ids := range byKey[provider] {
+			if len(ids) < 2 {
+				continue
+			}
+			sorted := append([]string(nil), ids...)
+			sort.Strings(sorted)
+			collisions = append(collisions, Collision{Provider: provider, Key: key, CoinIDs: sorted})
+		}
+	}
+	sort.Slice(collisions, func(i, j int) bool {
+		if collisions[i].Provider != collisions[j].Provider {
+			return collisions[i].Provider < collisions[j].Provider
+		}
+		return collisions[i].Key < collisions[j].Key
+	})
+	return collisions
+}
+
+func normalizeKey(key string) string {
+	return strings.ToUpper(strings.TrimSpace(key))
+}
+
+func compactKeys(keys []string) []string {
+	out := keys[:0]
+	for _, key := range keys {
+		if key == "" {
+			continue
+		}
+		out = appendUnique(out, key)
+	}
+	return out
+}
+
+func appendUnique(values []string, value string) []string {
+	for _, existing := range values {
+		if existing == value {
+			return values
+		}
+	}
+	return append(values, value)
+}
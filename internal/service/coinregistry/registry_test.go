@@ -0,0 +1,150 @@
+package coinregistry
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestDefaultResolvesProviderIDs(t *testing.T) {
+	reg := Default()
+	tests := []struct {
+		provider Provider
+		key      string
+		want     string
+	}{
+		{ProviderCoinGecko, "bitcoin", "bitcoin"},
+		{ProviderCoinGecko, "toncoin", "the-open-network"},
+		{ProviderCoinPaprika, "btc-bitcoin", "bitcoin"},
+		{ProviderCoinPaprika, "toncoin-toncoin", "the-open-network"},
+		{ProviderCoinCap, "xrp", "ripple"},
+		{ProviderBinance, "ADAUSDT", "cardano"},
+		{ProviderCryptoCompare, "avax", "avalanche-2"},
+		{ProviderCoinLore, "45219", "polkadot"},
+	}
+	for _, tt := range tests {
+		got, ok := reg.Resolve(tt.provider, tt.key)
+		if !ok || got != tt.want {
+			t.Errorf("Resolve(%s, %q) = (%q, %v), want %q", tt.provider, tt.key, got, ok, tt.want)
+		}
+	}
+	if collisions := reg.Collisions(); len(collisions) != 0 {
+		t.Fatalf("expected embedded registry to be collision-free, got %v", collisions)
+	}
+}
+
+func TestProviderKeyDerivesForUnknownCoins(t *testing.T) {
+	reg := Default()
+	coin := Coin{ID: "sui", Name: "Sui Network", Ticker: "sui"}
+	tests := []struct {
+		provider Provider
+		want     string
+	}{
+		{ProviderCoinGecko, "sui"},
+		{ProviderCoinCap, "sui"},
+		{ProviderBinance, "SUIUSDT"},
+		{ProviderCryptoCompare, "SUI"},
+		{ProviderCoinPaprika, "sui-sui-network"},
+		{ProviderCoinLore, ""},
+	}
+	for _, tt := range tests {
+		if got := reg.ProviderKey(tt.provider, coin); got != tt.want {
+			t.Errorf("ProviderKey(%s) = %q, want %q", tt.provider, got, tt.want)
+		}
+	}
+}
+
+func TestCollisionsMakeSymbolAmbiguous(t *testing.T) {
+	reg, err := New([]Coin{
+		{ID: "the-open-network", Name: "TON Coin", Ticker: "TON"},
+		{ID: "tokamak-network", Name: "Tokamak Network", Ticker: "TON"},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, ok := reg.Resolve(ProviderCryptoCompare, "TON"); ok {
+		t.Fatal("expected shared ticker to be unresolvable")
+	}
+	if id, ok := reg.Resolve(ProviderCoinGecko, "tokamak-network"); !ok || id != "tokamak-network" {
+		t.Fatalf("expected id-keyed provider to stay unambiguous, got %q", id)
+	}
+	collisions := reg.Collisions()
+	if len(collisions) == 0 {
+		t.Fatal("expected ticker collision to be reported")
+	}
+	found := false
+	for _, c := range collisions {
+		if c.Provider == ProviderCryptoCompare && c.Key == "TON" && len(c.CoinIDs) == 2 {
+			found = true
+		}
+	}
+	if !found {
+		t.Fatalf("expected cryptocompare TON collision, got %v", collisions)
+	}
+}
+
+func TestSubsetDisambiguatesByWatchlist(t *testing.T) {
+	reg, err := Default().Subset([]Coin{{ID: "bitcoin"}, {ID: "tokamak-network", Name: "Tokamak", Ticker: "TON"}})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if id, ok := reg.Resolve(ProviderCryptoCompare, "TON"); !ok || id != "tokamak-network" {
+		t.Fatalf("expected TON to resolve to the watched coin, got %q", id)
+	}
+	if id, ok := reg.Resolve(ProviderCoinPaprika, "btc-bitcoin"); !ok || id != "bitcoin" {
+		t.Fatalf("expected known mappings to be kept in subset, got %q", id)
+	}
+}
+
+func TestLoadMergesOverrideFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "coins.json")
+	override := `{"coins":[
+		{"id":"bitcoin","name":"Bitcoin (override)","providers":{"binance":"BTCFDUSD"}},
+		{"id":"sui","name":"Sui","ticker":"SUI","providers":{"coinpaprika":"sui-sui"}}
+	]}`
+	if err := os.WriteFile(path, []byte(override), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	reg, err := Load(path)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	btc, ok := reg.Lookup("bitcoin")
+	if !ok || btc.Name != "Bitcoin (override)" || btc.Ticker != "BTC" {
+		t.Fatalf("expected merged bitcoin entry, got %+v", btc)
+	}
+	if id, ok := reg.Resolve(ProviderBinance, "BTCFDUSD"); !ok || id != "bitcoin" {
+		t.Fatalf("expected override pair to resolve, got %q", id)
+	}
+	if id, ok := reg.Resolve(ProviderCoinPaprika, "btc-bitcoin"); !ok || id != "bitcoin" {
+		t.Fatalf("expected untouched mappings to survive merge, got %q", id)
+	}
+	if id, ok := reg.Resolve(ProviderCoinPaprika, "sui-sui"); !ok || id != "sui" {
+		t.Fatalf("expected new coin from override, got %q", id)
+	}
+}
+
+func TestLoadMissingOverrideUsesEmbedded(t *testing.T) {
+	reg, err := Load(filepath.Join(t.TempDir(), "missing.json"))
+	if err != nil {
+		t.Fatalf("expected missing override to be ignored, got %v", err)
+	}
+	if reg != Default() {
+		t.Fatal("expected embedded registry when override is absent")
+	}
+}
+
+func TestLoadInvalidOverrideFallsBack(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "coins.json")
+	if err := os.WriteFile(path, []byte(`{"coins":[{"id":""}]}`), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	reg, err := Load(path)
+	if err == nil {
+		t.Fatal("expected error for invalid override")
+	}
+	if reg != Default() {
+		t.Fatal("expected embedded registry as fallback")
+	}
+}
@@ -0,0 +1,256 @@
+package marketfeed
+
+import (
+	"log"
+	"math"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"cryptoview/internal/ui/i18n"
+)
+
+type AggregationMethod string
+
+const (
+	AggregationMedian         AggregationMethod = "median"
+	AggregationVolumeWeighted AggregationMethod = "volume_weighted"
+)
+
+const (
+	defaultAggregationProviders    = 3
+	defaultAggregationMaxDeviation = 0.02
+	minQuotesForOutlierRejection   = 3
+)
+
+type AggregationPolicy struct {
+	Enabled      bool
+	Providers    int
+	Method       AggregationMethod
+	MaxDeviation float64
+}
+
+func DefaultAggregationPolicy() AggregationPolicy {
+	return AggregationPolicy{
+		Enabled:      false,
+		Providers:    defaultAggregationProviders,
+		Method:       AggregationMedian,
+		MaxDeviation: defaultAggregationMaxDeviation,
+	}
+}
+
+func (p AggregationPolicy) normalized() AggregationPolicy {
+	if p.Providers < 2 {
+		p.Providers = defaultAggregationProviders
+	}
+	if p.Method != AggregationVolumeWeighted {
+		p.Method = AggregationMedian
+	}
+	if p.MaxDeviation <= 0 {
+		p.MaxDeviation = defaultAggregationMaxDeviation
+	}
+	return p
+}
+
+func (f *Feed) SetAggregation(policy AggregationPolicy) {
+	f.mu.Lock()
+	f.aggregation = policy.normalized()
+	f.mu.Unlock()
+}
+
+func (f *Feed) Aggregation() AggregationPolicy {
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+	return f.aggregation
+}
+
+type providerResult struct {
+	provider string
+	snapshot MarketSnapshot
+	err      error
+}
+
+func (f *Feed) runAggregatedCycle(now time.Time, tracked []CoinRef, policy AggregationPolicy, priority requestPriority) {
+	candidates := make([]MarketProvider, 0, policy.Providers)
+	for _, provider := range f.marketProviders() {
+		if len(candidates) == policy.Providers {
+			break
+		}
+		if !f.acquireProvider(provider.Name(), now) {
+			continue
+		}
+		if !f.takeBudget(provider.Name(), priority, now) {
+			log.Printf("marketfeed: skip provider=%s reason=budget", provider.Name())
+			f.releaseProbe(provider.Name())
+			continue
+		}
+		candidates = append(candidates, provider)
+	}
+	if len(candidates) == 0 {
+		f.handleMarketFailure(nil, 0)
+		return
+	}
+
+	results := make([]providerResult, len(candidates))
+	var wg sync.WaitGroup
+	for i, provider := range candidates {
+		log.Printf("marketfeed: fetch attempt provider=%s mode=consensus", provider.Name())
+		wg.Add(1)
+		go func(i int, provider MarketProvider) {
+			defer wg.Done()
+			snapshot, err := f.fetchProvider(now, provider, tracked)
+			results[i] = providerResult{provider: provider.Name(), snapshot: snapshot, err: err}
+		}(i, provider)
+	}
+	wg.Wait()
+	if f.isStopping() {
+		return
+	}
+
+	failures := make([]attemptFailure, 0, len(results))
+	snapshots := make([]MarketSnapshot, 0, len(results))
+	for _, result := range results {
+		if result.err != nil {
+			log.Printf("marketfeed: fetch failed provider=%s err=%v", result.provider, result.err)
+			failures = append(failures, attemptFailure{err: result.err})
+			continue
+		}
+		log.Printf("marketfeed: fetch success provider=%s coins=%d", result.provider, len(result.snapshot.Coins))
+		snapshots = append(snapshots, result.snapshot)
+	}
+	if len(snapshots) == 0 {
+		f.handleMarketFailure(failures, len(candidates))
+		return
+	}
+
+	snapshot, discarded := aggregateSnapshots(snapshots, policy)
+	for _, name := range discarded {
+		log.Printf("marketfeed: discarded outlier quotes provider=%s", name)
+	}
+
+	f.acceptMarketSnapshot(snapshot, snapshot.Provider, results[0].err == nil)
+	if len(discarded) > 0 {
+		f.emitStatus(StatusEvent{
+			Kind:      StatusKindWarning,
+			Code:      StatusCodeOutliers,
+			Provider:  snapshot.Provider,
+			Discarded: discarded,
+		})
+	}
+}
+
+func aggregateSnapshots(snapshots []MarketSnapshot, policy AggregationPolicy) (MarketSnapshot, []string) {
+	type sourcedQuote struct {
+		provider string
+		quote    CoinQuoteUSD
+	}
+	byCoin := make(map[string][]sourcedQuote)
+	names := make([]string, 0, len(snapshots))
+	fetchedAt := time.Time{}
+	for _, snapshot := range snapshots {
+		names = append(names, snapshot.Provider)
+		if snapshot.FetchedAt.After(fetchedAt) {
+			fetchedAt = snapshot.FetchedAt
+		}
+		for id, quote := range snapshot.Coins {
+			if quote.PriceUSD <= 0 {
+				continue
+			}
+			byCoin[id] = append(byCoin[id], sourcedQuote{provider: snapshot.Provider, quote: quote})
+		}
+	}
+
+	discardedSet := make(map[string]bool)
+	coins := make(map[string]CoinQuoteUSD, len(byCoin))
+	for id, quotes := range byCoin {
+		kept := quotes
+		if len(quotes) >= minQuotesForOutlierRejection {
+			prices := make([]float64, len(quotes))
+			for i, q := range quotes {
+				prices[i] = q.quote.PriceUSD
+			}
+			mid := median(prices)
+			kept = make([]sourcedQuote, 0, len(quotes))
+			for _, q := range quotes {
+				if math.Abs(q.quote.PriceUSD-mid)/mid > policy.MaxDeviation {
+					log.Printf("marketfeed: outlier coin=%s provider=%s price=%g median=%g", id, q.provider, q.quote.PriceUSD, mid)
+					discardedSet[q.provider] = true
+					continue
+				}
+				kept = append(kept, q)
+			}
+			if len(kept) == 0 {
+				kept = quotes
+			}
+		}
+
+		merged := kept[0].quote
+		prices := make([]float64, 0, len(kept))
+		changes := make([]float64, 0, len(kept))
+		natives := make(map[i18n.FiatCurrency][]float64)
+		weighted, volume := 0.0, 0.0
+		for _, q := range kept {
+			prices = append(prices, q.quote.PriceUSD)
+			for fiat, price := range q.quote.Native {
+				natives[fiat] = append(natives[fiat], price)
+			}
+			if q.quote.Change24h != nil {
+				changes = append(changes, *q.quote.Change24h)
+			}
+			if q.quote.Volume24hUSD > 0 {
+				weighted += q.quote.PriceUSD * q.quote.Volume24hUSD
+				volume += q.quote.Volume24hUSD
+			}
+			if q.quote.LastUpdate.After(merged.LastUpdate) {
+				merged.LastUpdate = q.quote.LastUpdate
+			}
+			merged.Name = chooseString(merged.Name, q.quote.Name)
+			merged.Ticker = chooseString(merged.Ticker, q.quote.Ticker)
+		}
+		merged.PriceUSD = median(prices)
+		if policy.Method == AggregationVolumeWeighted && volume > 0 {
+			merged.PriceUSD = weighted / volume
+		}
+		merged.Change24h = nil
+		if len(changes) > 0 {
+			change := median(changes)
+			merged.Change24h = &change
+		}
+		merged.Volume24hUSD = volume
+		merged.Native = nil
+		for fiat, values := range natives {
+			if merged.Native == nil {
+				merged.Native = make(map[i18n.FiatCurrency]float64, len(natives))
+			}
+			merged.Native[fiat] = median(values)
+		}
+		coins[id] = merged
+	}
+
+	discarded := make([]string, 0, len(discardedSet))
+	for _, name := range names {
+		if discardedSet[name] {
+			discarded = append(discarded, name)
+		}
+	}
+	return MarketSnapshot{
+		Provider:  strings.Join(names, "+"),
+		FetchedAt: fetchedAt,
+		Coins:     coins,
+	}, discarded
+}
+
+func median(values []float64) float64 {
+	if len(values) == 0 {
+		return 0
+	}
+	sorted := make([]float64, len(values))
+	copy(sorted, values)
+	sort.Float64s(sorted)
+	mid := len(sorted) / 2
+	if len(sorted)%2 == 0 {
+		return (sorted[mid-1] + sorted[mid]) / 2
+	}
+	return sorted[mid]
+}
@@ -0,0 +1,133 @@
+package marketfeed
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"cryptoview/internal/model"
+	"cryptoview/internal/ui/i18n"
+)
+
+func TestFeedConsensusDiscardsOutlierProvider(t *testing.T) {
+	prices := map[string]float64{"cg": 100, "cc": 101, "lore": 150}
+	providers := make([]MarketProvider, 0, len(prices))
+	fakes := make([]*fakeMarketProvider, 0, len(prices))
+	for _, name := range []string{"cg", "cc", "lore"} {
+		price := prices[name]
+		name := name
+		p := &fakeMarketProvider{
+			name: name,
+			fetchFunc: func(context.Context) (MarketSnapshot, error) {
+				return snapshotWithBTC(name, price), nil
+			},
+		}
+		providers = append(providers, p)
+		fakes = append(fakes, p)
+	}
+	var gotCoins []model.Coin
+	var gotStatus StatusEvent
+	feed := New(providers, []FXProvider{&fakeFXProvider{}}, Callbacks{
+		OnMarketUpdate: func(coins []model.Coin) { gotCoins = coins },
+		OnStatus:       func(event StatusEvent) { gotStatus = event },
+	})
+	feed.SetAggregation(AggregationPolicy{Enabled: true, Providers: 3, MaxDeviation: 0.05})
+
+	feed.runMarketCycle()
+
+	for _, p := range fakes {
+		if p.calls != 1 {
+			t.Fatalf("expected every provider to be queried once, %s got %d", p.name, p.calls)
+		}
+	}
+	if got := firstBTCPrice(t, gotCoins); got != 100.5 {
+		t.Fatalf("expected median of surviving quotes 100.5, got %v", got)
+	}
+	if gotStatus.Kind != StatusKindWarning || gotStatus.Code != StatusCodeOutliers {
+		t.Fatalf("expected outlier warning, got %+v", gotStatus)
+	}
+	if len(gotStatus.Discarded) != 1 || gotStatus.Discarded[0] != "lore" {
+		t.Fatalf("expected lore to be reported as discarded, got %v", gotStatus.Discarded)
+	}
+	if gotStatus.Provider != "cg+cc+lore" {
+		t.Fatalf("unexpected consensus provider label %q", gotStatus.Provider)
+	}
+}
+
+func TestFeedConsensusToleratesFailedProvider(t *testing.T) {
+	failing := &fakeMarketProvider{
+		name: "cg",
+		fetchFunc: func(context.Context) (MarketSnapshot, error) {
+			return MarketSnapshot{}, &ProviderError{Provider: "cg", Kind: FailureKindNetwork}
+		},
+	}
+	ok := &fakeMarketProvider{
+		name: "cc",
+		fetchFunc: func(context.Context) (MarketSnapshot, error) {
+			return snapshotWithBTC("cc", 200), nil
+		},
+	}
+	var gotStatus StatusEvent
+	feed := New([]MarketProvider{failing, ok}, []FXProvider{&fakeFXProvider{}}, Callbacks{
+		OnStatus: func(event StatusEvent) { gotStatus = event },
+	})
+	feed.SetAggregation(AggregationPolicy{Enabled: true})
+
+	feed.runMarketCycle()
+
+	if gotStatus.Kind != StatusKindWarning || gotStatus.Code != StatusCodeFallback || gotStatus.Provider != "cc" {
+		t.Fatalf("expected fallback warning from surviving provider, got %+v", gotStatus)
+	}
+	if feed.providerAvailable("cg", time.Now()) {
+		t.Fatal("expected failed provider to enter cooldown")
+	}
+}
+
+func TestFeedConsensusReportsMissingFXRate(t *testing.T) {
+	providers := make([]MarketProvider, 0, 2)
+	for _, name := range []string{"cg", "cc"} {
+		name := name
+		providers = append(providers, &fakeMarketProvider{
+			name: name,
+			fetchFunc: func(context.Context) (MarketSnapshot, error) {
+				return snapshotWithBTC(name, 100), nil
+			},
+		})
+	}
+	var gotStatus StatusEvent
+	feed := New(providers, []FXProvider{&fakeFXProvider{}}, Callbacks{
+		OnStatus: func(event StatusEvent) { gotStatus = event },
+	})
+	feed.SetAggregation(AggregationPolicy{Enabled: true})
+	feed.SetFiat(i18n.FiatEUR)
+
+	feed.runMarketCycle()
+
+	if gotStatus.Kind != StatusKindWarning || gotStatus.Code != StatusCodeFXMissing || gotStatus.Fiat != i18n.FiatEUR {
+		t.Fatalf("expected missing fx warning in consensus mode, got %+v", gotStatus)
+	}
+}
+
+func TestAggregateSnapshotsVolumeWeighted(t *testing.T) {
+	a := snapshotWithBTC("a", 100)
+	b := snapshotWithBTC("b", 110)
+	qa := a.Coins["bitcoin"]
+	qa.Volume24hUSD = 3
+	a.Coins["bitcoin"] = qa
+	qb := b.Coins["bitcoin"]
+	qb.Volume24hUSD = 1
+	b.Coins["bitcoin"] = qb
+
+	policy := AggregationPolicy{Enabled: true, Method: AggregationVolumeWeighted}.normalized()
+	snapshot, discarded := aggregateSnapshots([]MarketSnapshot{a, b}, policy)
+
+	if len(discarded) != 0 {
+		t.Fatalf("expected no outlier rejection with two quotes, got %v", discarded)
+	}
+	if got := snapshot.Coins["bitcoin"].PriceUSD; got != 102.5 {
+		t.Fatalf("expected volume weighted price 102.5, got %v", got)
+	}
+	if got := snapshot.Coins["bitcoin"].Volume24hUSD; got != 4 {
+		t.Fatalf("expected summed volume 4, got %v", got)
+	}
+}
@@ -0,0 +1,112 @@
+package marketfeed
+
+import (
+	"errors"
+	"math"
+	"sort"
+	"time"
+)
+
+var ErrBudgetExhausted = errors.New("marketfeed: request budget exhausted")
+
+type requestPriority int
+
+const (
+	priorityPoll requestPriority = iota
+	priorityInteractive
+)
+
+const budgetReserveFraction = 0.1
+
+type RequestBudget struct {
+	Provider  string
+	PerMinute int
+	Remaining int
+}
+
+type tokenBucket struct {
+	perMinute int
+	tokens    float64
+	last      time.Time
+}
+
+func newTokenBucket(perMinute int, now time.Time) *tokenBucket {
+	return &tokenBucket{perMinute: perMinute, tokens: float64(perMinute), last: now}
+}
+
+func (b *tokenBucket) refill(now time.Time) {
+	if elapsed := now.Sub(b.last); elapsed > 0 {
+		b.tokens = math.Min(float64(b.perMinute), b.tokens+elapsed.Minutes()*float64(b.perMinute))
+		b.last = now
+	}
+}
+
+// Polling leaves a reserve in the bucket so manual refreshes and chart
+// requests still go through when the scheduler has used up the rest.
+func (b *tokenBucket) take(now time.Time, priority requestPriority) bool {
+	b.refill(now)
+	need := 1.0
+	if priority == priorityPoll {
+		need += math.Floor(float64(b.perMinute) * budgetReserveFraction)
+	}
+	if b.tokens < need {
+		return false
+	}
+	b.tokens--
+	return true
+}
+
+func (b *tokenBucket) remaining(now time.Time) int {
+	b.refill(now)
+	return int(math.Floor(b.tokens))
+}
+
+func (f *Feed) SetRequestBudget(provider string, perMinute int) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if perMinute <= 0 {
+		delete(f.budgets, provider)
+		return
+	}
+	f.budgets[provider] = newTokenBucket(perMinute, time.Now())
+}
+
+func (f *Feed) RequestBudgets() []RequestBudget {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	now := time.Now()
+	order := make(map[string]int, len(f.providers)+len(f.fxProviders))
+	for i, p := range f.providers {
+		order[p.Name()] = i
+	}
+	for i, p := range f.fxProviders {
+		order[p.Name()] = len(f.providers) + i
+	}
+
+	budgets := make([]RequestBudget, 0, len(f.budgets))
+	for name, bucket := range f.budgets {
+		budgets = append(budgets, RequestBudget{Provider: name, PerMinute: bucket.perMinute, Remaining: bucket.remaining(now)})
+	}
+	sort.Slice(budgets, func(i, j int) bool {
+		oi, iKnown := order[budgets[i].Provider]
+		oj, jKnown := order[budgets[j].Provider]
+		if iKnown != jKnown {
+			return iKnown
+		}
+		if oi != oj {
+			return oi < oj
+		}
+		return budgets[i].Provider < budgets[j].Provider
+	})
+	return budgets
+}
+
+func (f *Feed) takeBudget(provider string, priority requestPriority, now time.Time) bool {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	bucket := f.budgets[provider]
+	if bucket == nil {
+		return true
+	}
+	return bucket.take(now, priority)
+}
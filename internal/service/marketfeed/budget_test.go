@@ -0,0 +1,122 @@
+package marketfeed
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"cryptoview/internal/model"
+	"cryptoview/internal/ui/i18n"
+)
+
+func TestTokenBucketKeepsReserveForInteractiveRequests(t *testing.T) {
+	now := time.Unix(1700000000, 0)
+	bucket := newTokenBucket(20, now)
+
+	polled := 0
+	for bucket.take(now, priorityPoll) {
+		polled++
+	}
+	if polled != 18 {
+		t.Fatalf("expected polling to stop before the reserve, got %d requests", polled)
+	}
+	if !bucket.take(now, priorityInteractive) || !bucket.take(now, priorityInteractive) {
+		t.Fatal("expected interactive requests to use the reserve")
+	}
+	if bucket.take(now, priorityInteractive) {
+		t.Fatal("expected empty bucket to refuse requests")
+	}
+	if got := bucket.remaining(now.Add(30 * time.Second)); got != 10 {
+		t.Fatalf("expected half the budget back after 30s, got %d", got)
+	}
+	if got := bucket.remaining(now.Add(5 * time.Minute)); got != 20 {
+		t.Fatalf("expected refill capped at the budget, got %d", got)
+	}
+}
+
+func TestFeedSkipsProviderBeforeBudgetIsExhausted(t *testing.T) {
+	p1 := &fakeMarketProvider{
+		name: "cg",
+		fetchFunc: func(context.Context) (MarketSnapshot, error) {
+			return snapshotWithBTC("cg", 100), nil
+		},
+	}
+	p2 := &fakeMarketProvider{
+		name: "coincap",
+		fetchFunc: func(context.Context) (MarketSnapshot, error) {
+			return snapshotWithBTC("coincap", 100), nil
+		},
+	}
+	var gotStatus StatusEvent
+	feed := New([]MarketProvider{p1, p2}, []FXProvider{&fakeFXProvider{}}, Callbacks{
+		OnStatus: func(event StatusEvent) { gotStatus = event },
+	})
+	feed.SetRequestBudget("cg", 10)
+
+	for i := 0; i < 10; i++ {
+		feed.runMarketCycle()
+	}
+	if p1.calls != 9 || p2.calls != 1 {
+		t.Fatalf("expected polling to stop at the reserve and fall back, got cg=%d coincap=%d", p1.calls, p2.calls)
+	}
+	if gotStatus.Code != StatusCodeFallback || gotStatus.Provider != "coincap" {
+		t.Fatalf("expected fallback status, got %+v", gotStatus)
+	}
+
+	feed.marketCycle(priorityInteractive)
+	if p1.calls != 10 {
+		t.Fatalf("expected manual refresh to spend the reserve, got cg=%d", p1.calls)
+	}
+
+	states := feed.ProviderStates()
+	if states[0].BudgetPerMinute != 10 || states[0].BudgetRemaining != 0 {
+		t.Fatalf("expected exhausted budget in provider state, got %+v", states[0])
+	}
+	if states[1].BudgetPerMinute != 0 {
+		t.Fatalf("expected unlimited provider without budget, got %+v", states[1])
+	}
+}
+
+func TestFeedBudgetSharedWithChartsAndFX(t *testing.T) {
+	charts := &fakeChartProvider{
+		fakeMarketProvider: fakeMarketProvider{name: "cg"},
+		chartFunc: func(string, i18n.FiatCurrency, int) ([]model.PricePoint, error) {
+			return []model.PricePoint{{Time: time.Unix(1700000000, 0), Price: 1}}, nil
+		},
+	}
+	fx := &fakeFXProvider{}
+	feed := New([]MarketProvider{charts}, []FXProvider{fx}, Callbacks{})
+	feed.SetRequestBudget("cg", 1)
+	feed.SetRequestBudget("fakefx", 1)
+
+	if _, err := feed.MarketChart(context.Background(), "bitcoin", i18n.FiatUSD, 1); err != nil {
+		t.Fatalf("unexpected chart error: %v", err)
+	}
+	if _, err := feed.MarketChart(context.Background(), "bitcoin", i18n.FiatUSD, 1); !errors.Is(err, ErrBudgetExhausted) {
+		t.Fatalf("expected ErrBudgetExhausted once the shared budget is spent, got %v", err)
+	}
+	feed.runMarketCycle()
+	if charts.calls != 0 {
+		t.Fatalf("expected polling to skip provider with spent budget, got %d calls", charts.calls)
+	}
+
+	feed.runFXCycle()
+	feed.runFXCycle()
+	if fx.calls != 1 {
+		t.Fatalf("expected fx polling limited by its budget, got %d calls", fx.calls)
+	}
+
+	budgets := feed.RequestBudgets()
+	if len(budgets) != 2 || budgets[0].Provider != "cg" || budgets[1].Provider != "fakefx" {
+		t.Fatalf("expected market budgets before fx, got %+v", budgets)
+	}
+	if budgets[1].PerMinute != 1 || budgets[1].Remaining != 0 {
+		t.Fatalf("expected spent fx budget, got %+v", budgets[1])
+	}
+
+	feed.SetRequestBudget("fakefx", 0)
+	if got := feed.RequestBudgets(); len(got) != 1 {
+		t.Fatalf("expected zero budget to remove the limit, got %+v", got)
+	}
+}
@@ -0,0 +1,141 @@
+package marketfeed
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"cryptoview/internal/model"
+	"cryptoview/internal/ui/i18n"
+)
+
+const chartRequestTimeout = 15 * time.Second
+
+var (
+	ErrChartUnavailable = errors.New("marketfeed: no chart provider configured")
+	ErrUnsupportedFiat  = errors.New("marketfeed: unsupported fiat currency")
+)
+
+type ChartProvider interface {
+	Name() string
+	FetchMarketChart(ctx context.Context, id string, fiat i18n.FiatCurrency, days int) ([]model.PricePoint, error)
+}
+
+// ChartFiatChecker is implemented by chart providers that know without a
+// request which currencies they chart. Other providers are asked directly
+// and converted from USD when they answer ErrUnsupportedFiat.
+type ChartFiatChecker interface {
+	SupportsChartFiat(fiat i18n.FiatCurrency) bool
+}
+
+func (f *Feed) MarketChart(ctx context.Context, id string, fiat i18n.FiatCurrency, days int) ([]model.PricePoint, error) {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	ctx, cancel := context.WithTimeout(ctx, chartRequestTimeout)
+	defer cancel()
+
+	var lastErr error = ErrChartUnavailable
+	for _, provider := range f.marketProviders() {
+		charts, ok := provider.(ChartProvider)
+		if !ok {
+			continue
+		}
+		var points []model.PricePoint
+		var err error
+		if checker, ok := charts.(ChartFiatChecker); ok && fiat != i18n.FiatUSD && !checker.SupportsChartFiat(fiat) {
+			points, err = f.convertedChart(ctx, charts, id, fiat, days)
+		} else {
+			points, err = f.fetchChart(ctx, charts, id, fiat, days)
+			if errors.Is(err, ErrUnsupportedFiat) && fiat != i18n.FiatUSD {
+				points, err = f.convertedChart(ctx, charts, id, fiat, days)
+			}
+		}
+		if err != nil {
+			log.Printf("marketfeed: chart fetch failed provider=%s coin=%s days=%d err=%v", charts.Name(), id, days, err)
+			lastErr = err
+			if ctx.Err() != nil {
+				break
+			}
+			continue
+		}
+		return points, nil
+	}
+	return nil, lastErr
+}
+
+// fetchChart takes one interactive budget token per request it sends.
+func (f *Feed) fetchChart(ctx context.Context, charts ChartProvider, id string, fiat i18n.FiatCurrency, days int) ([]model.PricePoint, error) {
+	if !f.takeBudget(charts.Name(), priorityInteractive, time.Now()) {
+		log.Printf("marketfeed: skip chart provider=%s reason=budget", charts.Name())
+		return nil, fmt.Errorf("%s: %w", charts.Name(), ErrBudgetExhausted)
+	}
+	return charts.FetchMarketChart(ctx, id, fiat, days)
+}
+
+// convertedChart serves currencies the chart provider cannot quote natively
+// from the USD series: fiats are scaled with the current FX rate, crypto
+// quotes are divided by the reference coin's USD series.
+func (f *Feed) convertedChart(ctx context.Context, charts ChartProvider, id string, fiat i18n.FiatCurrency, days int) ([]model.PricePoint, error) {
+	info, ok := i18n.LookupCurrency(fiat)
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrUnsupportedFiat, fiat)
+	}
+	if info.IsCrypto() {
+		return f.chartInReferenceCoin(ctx, charts, id, info, days)
+	}
+	f.mu.RLock()
+	var rate float64
+	if f.lastFX != nil {
+		rate = f.lastFX.Rates[fiat]
+	}
+	f.mu.RUnlock()
+	if rate <= 0 {
+		return nil, fmt.Errorf("%w: no fx rate for %s", ErrUnsupportedFiat, fiat)
+	}
+	log.Printf("marketfeed: chart via usd provider=%s coin=%s fiat=%s rate=%g", charts.Name(), id, fiat, rate)
+	points, err := f.fetchChart(ctx, charts, id, i18n.FiatUSD, days)
+	if err != nil {
+		return nil, err
+	}
+	converted := make([]model.PricePoint, len(points))
+	for i, point := range points {
+		converted[i] = model.PricePoint{Time: point.Time, Price: point.Price * rate}
+	}
+	return converted, nil
+}
+
+func (f *Feed) chartInReferenceCoin(ctx context.Context, charts ChartProvider, id string, info i18n.CurrencyInfo, days int) ([]model.PricePoint, error) {
+	log.Printf("marketfeed: chart via usd provider=%s coin=%s quote=%s", charts.Name(), id, info.Code)
+	points, err := f.fetchChart(ctx, charts, id, i18n.FiatUSD, days)
+	if err != nil {
+		return nil, err
+	}
+	reference := points
+	if id != info.CoinID {
+		if reference, err = f.fetchChart(ctx, charts, info.CoinID, i18n.FiatUSD, days); err != nil {
+			return nil, err
+		}
+	}
+	if len(reference) == 0 {
+		return nil, fmt.Errorf("%w: no %s series for %s", ErrUnsupportedFiat, info.CoinID, info.Code)
+	}
+	converted := make([]model.PricePoint, 0, len(points))
+	j := 0
+	for _, point := range points {
+		for j+1 < len(reference) && !reference[j+1].Time.After(point.Time) {
+			j++
+		}
+		ref := reference[j]
+		if j+1 < len(reference) && reference[j+1].Time.Sub(point.Time) < point.Time.Sub(ref.Time) {
+			ref = reference[j+1]
+		}
+		if ref.Price <= 0 {
+			continue
+		}
+		converted = append(converted, model.PricePoint{Time: point.Time, Price: point.Price / ref.Price * info.Units})
+	}
+	return converted, nil
+}
@@ -0,0 +1,145 @@
+package marketfeed
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"cryptoview/internal/model"
+	"cryptoview/internal/ui/i18n"
+)
+
+type fakeChartProvider struct {
+	fakeMarketProvider
+	chartFunc func(id string, fiat i18n.FiatCurrency, days int) ([]model.PricePoint, error)
+}
+
+func (p *fakeChartProvider) FetchMarketChart(_ context.Context, id string, fiat i18n.FiatCurrency, days int) ([]model.PricePoint, error) {
+	return p.chartFunc(id, fiat, days)
+}
+
+func TestFeedMarketChartUsesFirstChartProvider(t *testing.T) {
+	failing := &fakeChartProvider{
+		fakeMarketProvider: fakeMarketProvider{name: "cg"},
+		chartFunc: func(string, i18n.FiatCurrency, int) ([]model.PricePoint, error) {
+			return nil, &ProviderError{Provider: "cg", Kind: FailureKindRateLimit}
+		},
+	}
+	var got string
+	working := &fakeChartProvider{
+		fakeMarketProvider: fakeMarketProvider{name: "alt"},
+		chartFunc: func(id string, fiat i18n.FiatCurrency, days int) ([]model.PricePoint, error) {
+			got = id + "/" + string(fiat)
+			return []model.PricePoint{{Time: time.Unix(1700000000, 0), Price: 1}}, nil
+		},
+	}
+	plain := &fakeMarketProvider{name: "plain"}
+	feed := New([]MarketProvider{plain, failing, working}, []FXProvider{&fakeFXProvider{}}, Callbacks{})
+
+	points, err := feed.MarketChart(context.Background(), "bitcoin", i18n.FiatEUR, 7)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(points) != 1 || got != "bitcoin/EUR" {
+		t.Fatalf("expected chart from fallback provider, got %v %q", points, got)
+	}
+}
+
+func TestFeedMarketChartConvertsUnsupportedFiatFromUSD(t *testing.T) {
+	var requested []i18n.FiatCurrency
+	provider := &fakeChartProvider{
+		fakeMarketProvider: fakeMarketProvider{name: "cg"},
+		chartFunc: func(_ string, fiat i18n.FiatCurrency, _ int) ([]model.PricePoint, error) {
+			requested = append(requested, fiat)
+			if fiat != i18n.FiatUSD {
+				return nil, &ProviderError{Provider: "cg", Kind: FailureKindOther, Err: ErrUnsupportedFiat}
+			}
+			return []model.PricePoint{{Time: time.Unix(1700000000, 0), Price: 2}}, nil
+		},
+	}
+	feed := New([]MarketProvider{provider}, []FXProvider{&fakeFXProvider{}}, Callbacks{})
+	feed.lastFX.Rates[i18n.FiatKZT] = 450
+
+	points, err := feed.MarketChart(context.Background(), "bitcoin", i18n.FiatKZT, 1)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(points) != 1 || points[0].Price != 900 {
+		t.Fatalf("expected converted points, got %v", points)
+	}
+	if len(requested) != 2 || requested[0] != i18n.FiatKZT || requested[1] != i18n.FiatUSD {
+		t.Fatalf("unexpected request order %v", requested)
+	}
+
+	if _, err := feed.MarketChart(context.Background(), "bitcoin", i18n.FiatUAH, 1); !errors.Is(err, ErrUnsupportedFiat) {
+		t.Fatalf("expected ErrUnsupportedFiat without a rate, got %v", err)
+	}
+}
+
+type usdOnlyChartProvider struct {
+	fakeChartProvider
+}
+
+func (p *usdOnlyChartProvider) SupportsChartFiat(fiat i18n.FiatCurrency) bool {
+	return fiat == i18n.FiatUSD
+}
+
+func TestFeedMarketChartSpendsOneTokenPerRequest(t *testing.T) {
+	var requests []string
+	provider := &usdOnlyChartProvider{fakeChartProvider{
+		fakeMarketProvider: fakeMarketProvider{name: "cg"},
+		chartFunc: func(id string, fiat i18n.FiatCurrency, _ int) ([]model.PricePoint, error) {
+			requests = append(requests, id+"/"+string(fiat))
+			price := 100000.0
+			if id == "ethereum" {
+				price = 4000
+			}
+			start := time.Unix(1700000000, 0)
+			return []model.PricePoint{{Time: start, Price: price}, {Time: start.Add(time.Hour), Price: price * 2}}, nil
+		},
+	}}
+	feed := New([]MarketProvider{provider}, []FXProvider{&fakeFXProvider{}}, Callbacks{})
+	feed.SetRequestBudget("cg", 10)
+	feed.lastFX.Rates[i18n.FiatKZT] = 450
+	remaining := func() int {
+		return feed.RequestBudgets()[0].Remaining
+	}
+
+	if _, err := feed.MarketChart(context.Background(), "bitcoin", i18n.FiatKZT, 1); err != nil {
+		t.Fatalf("kzt chart: %v", err)
+	}
+	if len(requests) != 1 || requests[0] != "bitcoin/USD" || remaining() != 9 {
+		t.Fatalf("expected one USD request for one token, got %v remaining=%d", requests, remaining())
+	}
+
+	if _, err := feed.MarketChart(context.Background(), "bitcoin", i18n.FiatUAH, 1); !errors.Is(err, ErrUnsupportedFiat) || remaining() != 9 {
+		t.Fatalf("expected a local unsupported error without spending a token, got %v remaining=%d", err, remaining())
+	}
+
+	requests = nil
+	points, err := feed.MarketChart(context.Background(), "ethereum", i18n.QuoteSATS, 1)
+	if err != nil {
+		t.Fatalf("sats chart: %v", err)
+	}
+	if len(requests) != 2 || requests[1] != "bitcoin/USD" || remaining() != 7 {
+		t.Fatalf("expected the coin and reference series for two tokens, got %v remaining=%d", requests, remaining())
+	}
+	if len(points) != 2 || points[0].Price != 4_000_000 || points[1].Price != 4_000_000 {
+		t.Fatalf("expected ETH priced in sats, got %v", points)
+	}
+}
+
+func TestFeedMarketChartWithoutProvider(t *testing.T) {
+	feed := New([]MarketProvider{&fakeMarketProvider{name: "plain"}}, []FXProvider{&fakeFXProvider{}}, Callbacks{})
+	if _, err := feed.MarketChart(context.Background(), "bitcoin", i18n.FiatUSD, 1); !errors.Is(err, ErrChartUnavailable) {
+		t.Fatalf("expected ErrChartUnavailable, got %v", err)
+	}
+}
+
+func TestCoinGeckoProviderServesCharts(t *testing.T) {
+	var provider MarketProvider = NewCoinGeckoProvider(time.Second)
+	if _, ok := provider.(ChartProvider); !ok {
+		t.Fatal("expected coingecko provider to implement ChartProvider")
+	}
+}
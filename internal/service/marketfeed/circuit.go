@@ -0,0 +1,194 @@
+package marketfeed
+
+import (
+	"errors"
+	"log"
+	"time"
+)
+
+type CircuitState string
+
+const (
+	CircuitClosed   CircuitState = "closed"
+	CircuitOpen     CircuitState = "open"
+	CircuitHalfOpen CircuitState = "half_open"
+)
+
+const (
+	defaultCircuitMaxBackoff = 10 * time.Minute
+	defaultCircuitJitter     = 0.2
+	maxBackoffShift          = 20
+)
+
+type CircuitPolicy struct {
+	MaxBackoff time.Duration
+	Jitter     float64
+}
+
+func DefaultCircuitPolicy() CircuitPolicy {
+	return CircuitPolicy{
+		MaxBackoff: defaultCircuitMaxBackoff,
+		Jitter:     defaultCircuitJitter,
+	}
+}
+
+func (p CircuitPolicy) normalized() CircuitPolicy {
+	if p.MaxBackoff <= 0 {
+		p.MaxBackoff = defaultCircuitMaxBackoff
+	}
+	if p.Jitter < 0 {
+		p.Jitter = 0
+	}
+	if p.Jitter > 1 {
+		p.Jitter = 1
+	}
+	return p
+}
+
+// backoff grows the base delay for the failure kind exponentially with the
+// number of consecutive failures. random is expected in [0, 1).
+func (p CircuitPolicy) backoff(failures int, err error, random float64) time.Duration {
+	base := 20 * time.Second
+	var retryAfter time.Duration
+	var pe *ProviderError
+	if errors.As(err, &pe) {
+		switch pe.Kind {
+		case FailureKindRateLimit:
+			base = 5 * time.Second
+			retryAfter = pe.RetryAfter
+		case FailureKindNetwork:
+			base = 4 * time.Second
+		}
+	}
+	shift := failures - 1
+	if shift < 0 {
+		shift = 0
+	}
+	if shift > maxBackoffShift {
+		shift = maxBackoffShift
+	}
+	delay := base << shift
+	if retryAfter > delay {
+		delay = retryAfter
+	}
+	if delay > p.MaxBackoff {
+		delay = p.MaxBackoff
+	}
+	delay += time.Duration(float64(delay) * p.Jitter * (2*random - 1))
+	if delay > p.MaxBackoff {
+		delay = p.MaxBackoff
+	}
+	return delay
+}
+
+func (st *providerState) circuitState() CircuitState {
+	if st.circuit == "" {
+		return CircuitClosed
+	}
+	return st.circuit
+}
+
+func (st *providerState) available(now time.Time) bool {
+	switch st.circuitState() {
+	case CircuitOpen:
+		return !now.Before(st.cooldownUntil)
+	case CircuitHalfOpen:
+		return !st.probing
+	default:
+		return true
+	}
+}
+
+func (st *providerState) acquire(now time.Time) (allowed bool, halfOpened bool) {
+	if !st.available(now) {
+		return false, false
+	}
+	switch st.circuitState() {
+	case CircuitOpen:
+		st.circuit = CircuitHalfOpen
+		st.probing = true
+		return true, true
+	case CircuitHalfOpen:
+		st.probing = true
+	}
+	return true, false
+}
+
+func (st *providerState) onSuccess(now time.Time) (closed bool) {
+	closed = st.circuitState() != CircuitClosed
+	st.circuit = CircuitClosed
+	st.probing = false
+	st.consecutiveFailures = 0
+	st.cooldownUntil = time.Time{}
+	st.lastSuccess = now
+	return closed
+}
+
+func (st *providerState) onFailure(at time.Time, err error, openUntil time.Time) {
+	st.circuit = CircuitOpen
+	st.probing = false
+	st.consecutiveFailures++
+	st.lastFailure = at
+	st.lastErr = err
+	st.cooldownUntil = openUntil
+}
+
+func (f *Feed) SetCircuitPolicy(policy CircuitPolicy) {
+	f.mu.Lock()
+	f.circuitPolicy = policy.normalized()
+	f.mu.Unlock()
+}
+
+func (f *Feed) CircuitPolicy() CircuitPolicy {
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+	return f.circuitPolicy
+}
+
+func (f *Feed) stateLocked(name string) *providerState {
+	st := f.state[name]
+	if st == nil {
+		st = &providerState{}
+		f.state[name] = st
+	}
+	return st
+}
+
+func (f *Feed) acquireProvider(name string, now time.Time) bool {
+	f.mu.Lock()
+	allowed, halfOpened := f.stateLocked(name).acquire(now)
+	f.mu.Unlock()
+	if !allowed {
+		if remaining := f.providerCooldownRemaining(name, now); remaining > 0 {
+			log.Printf("marketfeed: skip provider=%s reason=cooldown remaining=%s", name, remaining.Round(time.Second))
+		} else {
+			log.Printf("marketfeed: skip provider=%s reason=probe_in_flight", name)
+		}
+		return false
+	}
+	if halfOpened {
+		f.emitCircuit(name, CircuitHalfOpen, nil, 0)
+	}
+	return true
+}
+
+func (f *Feed) releaseProbe(name string) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if st := f.state[name]; st != nil {
+		st.probing = false
+	}
+}
+
+func (f *Feed) emitCircuit(name string, state CircuitState, err error, retryIn time.Duration) {
+	log.Printf("marketfeed: circuit provider=%s state=%s retry_in=%s", name, state, retryIn.Round(time.Second))
+	f.currentMetrics().observeCircuit(name, state)
+	code := StatusCodeCircuitClosed
+	switch state {
+	case CircuitOpen:
+		code = StatusCodeCircuitOpen
+	case CircuitHalfOpen:
+		code = StatusCodeCircuitHalfOpen
+	}
+	f.emitStatus(StatusEvent{Kind: StatusKindCircuit, Code: code, Provider: name, Err: err, RetryIn: retryIn})
+}
@@ -0,0 +1,126 @@
+package marketfeed
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestCircuitPolicyBackoff(t *testing.T) {
+	policy := CircuitPolicy{MaxBackoff: time.Minute, Jitter: 0.2}
+	rateLimit := &ProviderError{Kind: FailureKindRateLimit}
+	network := &ProviderError{Kind: FailureKindNetwork}
+
+	cases := []struct {
+		name     string
+		failures int
+		err      error
+		random   float64
+		want     time.Duration
+	}{
+		{"first rate limit", 1, rateLimit, 0.5, 5 * time.Second},
+		{"third rate limit", 3, rateLimit, 0.5, 20 * time.Second},
+		{"network", 2, network, 0.5, 8 * time.Second},
+		{"other", 1, errors.New("boom"), 0.5, 20 * time.Second},
+		{"retry-after wins", 1, &ProviderError{Kind: FailureKindRateLimit, RetryAfter: 30 * time.Second}, 0.5, 30 * time.Second},
+		{"ceiling", 40, network, 0.5, time.Minute},
+		{"jitter low", 1, rateLimit, 0, 4 * time.Second},
+		{"jitter never exceeds ceiling", 40, network, 0.99, time.Minute},
+	}
+	for _, tc := range cases {
+		if got := policy.backoff(tc.failures, tc.err, tc.random); got != tc.want {
+			t.Fatalf("%s: expected %s, got %s", tc.name, tc.want, got)
+		}
+	}
+}
+
+func TestFeedCircuitBreakerTransitions(t *testing.T) {
+	healthy := false
+	p1 := &fakeMarketProvider{
+		name: "cg",
+		fetchFunc: func(context.Context) (MarketSnapshot, error) {
+			if healthy {
+				return snapshotWithBTC("cg", 100), nil
+			}
+			return MarketSnapshot{}, &ProviderError{Provider: "cg", Kind: FailureKindNetwork}
+		},
+	}
+	p2 := &fakeMarketProvider{
+		name: "coincap",
+		fetchFunc: func(context.Context) (MarketSnapshot, error) {
+			return snapshotWithBTC("coincap", 100), nil
+		},
+	}
+	var circuit []StatusCode
+	feed := New([]MarketProvider{p1, p2}, []FXProvider{&fakeFXProvider{}}, Callbacks{
+		OnStatus: func(event StatusEvent) {
+			if event.Kind == StatusKindCircuit && event.Provider == "cg" {
+				circuit = append(circuit, event.Code)
+			}
+		},
+	})
+	feed.random = func() float64 { return 0.5 }
+	expire := func() {
+		feed.mu.Lock()
+		feed.state["cg"].cooldownUntil = time.Now().Add(-time.Millisecond)
+		feed.mu.Unlock()
+	}
+	openFor := func() time.Duration {
+		state := feed.ProviderStates()[0]
+		return state.CooldownUntil.Sub(state.LastFailure).Round(time.Second)
+	}
+
+	feed.runMarketCycle()
+	if state := feed.ProviderStates()[0]; state.Circuit != CircuitOpen || openFor() != 4*time.Second {
+		t.Fatalf("expected circuit to open for 4s, got %+v", state)
+	}
+	feed.runMarketCycle()
+	if p1.calls != 1 {
+		t.Fatalf("expected open circuit to reject requests, got %d calls", p1.calls)
+	}
+
+	expire()
+	feed.runMarketCycle()
+	if p1.calls != 2 || openFor() != 8*time.Second {
+		t.Fatalf("expected failed probe to reopen with doubled backoff, calls=%d open=%s", p1.calls, openFor())
+	}
+
+	expire()
+	healthy = true
+	feed.runMarketCycle()
+	if state := feed.ProviderStates()[0]; state.Circuit != CircuitClosed || state.ConsecutiveFailures != 0 || !state.CooldownUntil.IsZero() {
+		t.Fatalf("expected successful probe to close the circuit, got %+v", state)
+	}
+
+	want := []StatusCode{
+		StatusCodeCircuitOpen,
+		StatusCodeCircuitHalfOpen, StatusCodeCircuitOpen,
+		StatusCodeCircuitHalfOpen, StatusCodeCircuitClosed,
+	}
+	if len(circuit) != len(want) {
+		t.Fatalf("expected transitions %v, got %v", want, circuit)
+	}
+	for i := range want {
+		if circuit[i] != want[i] {
+			t.Fatalf("expected transitions %v, got %v", want, circuit)
+		}
+	}
+}
+
+func TestHalfOpenCircuitAllowsSingleProbe(t *testing.T) {
+	now := time.Now()
+	st := &providerState{circuit: CircuitOpen, cooldownUntil: now.Add(-time.Second)}
+
+	allowed, halfOpened := st.acquire(now)
+	if !allowed || !halfOpened || st.circuitState() != CircuitHalfOpen {
+		t.Fatalf("expected expired open circuit to admit a probe, got allowed=%t halfOpened=%t", allowed, halfOpened)
+	}
+	if allowed, _ := st.acquire(now); allowed {
+		t.Fatal("expected concurrent request to be rejected while the probe is in flight")
+	}
+	st.probing = false
+	if allowed, halfOpened := st.acquire(now); !allowed || halfOpened {
+		t.Fatal("expected released probe slot to admit the next probe without a new transition")
+	}
+}
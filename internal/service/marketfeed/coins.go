@@ -0,0 +1,164 @@
+package marketfeed
+
+import (
+	"log"
+	"strings"
+
+	"cryptoview/internal/service/coinregistry"
+)
+
+type CoinRef struct {
+	ID     string
+	Name   string
+	Ticker string
+}
+
+func DefaultTrackedCoins() []CoinRef {
+	return coinRefsFromRegistry(coinregistry.Default(), defaultTrackedIDs)
+}
+
+func LoadUserRegistry() *coinregistry.Registry {
+	path, err := coinregistry.UserOverridePath()
+	if err != nil {
+		return coinregistry.Default()
+	}
+	registry, err := coinregistry.Load(path)
+	if err != nil {
+		log.Printf("marketfeed: coin registry override ignored: %v", err)
+	}
+	return registry
+}
+
+func coinRefsFromRegistry(registry *coinregistry.Registry, ids []string) []CoinRef {
+	coins := make([]CoinRef, 0, len(ids))
+	for _, id := range ids {
+		coin, ok := registry.Lookup(id)
+		if !ok {
+			continue
+		}
+		coins = append(coins, CoinRef{ID: coin.ID, Name: coin.Name, Ticker: coin.Ticker})
+	}
+	return coins
+}
+
+func normalizeCoinRefs(registry *coinregistry.Registry, coins []CoinRef) []CoinRef {
+	seen := make(map[string]struct{}, len(coins))
+	normalized := make([]CoinRef, 0, len(coins))
+	for _, coin := range coins {
+		id := strings.ToLower(strings.TrimSpace(coin.ID))
+		if id == "" {
+			continue
+		}
+		if _, ok := seen[id]; ok {
+			continue
+		}
+		seen[id] = struct{}{}
+		ref := CoinRef{
+			ID:     id,
+			Name:   strings.TrimSpace(coin.Name),
+			Ticker: strings.ToUpper(strings.TrimSpace(coin.Ticker)),
+		}
+		if known, ok := registry.Lookup(id); ok {
+			ref.Name = chooseString(ref.Name, known.Name)
+			ref.Ticker = chooseString(ref.Ticker, known.Ticker)
+		}
+		normalized = append(normalized, ref)
+	}
+	return normalized
+}
+
+func toRegistryCoins(coins []CoinRef) []coinregistry.Coin {
+	out := make([]coinregistry.Coin, 0, len(coins))
+	for _, coin := range coins {
+		out = append(out, coinregistry.Coin{ID: coin.ID, Name: coin.Name, Ticker: coin.Ticker})
+	}
+	return out
+}
+
+func logTrackedCollisions(registry *coinregistry.Registry, coins []CoinRef) {
+	subset, err := registry.Subset(toRegistryCoins(coins))
+	if err != nil {
+		log.Printf("marketfeed: invalid watchlist: %v", err)
+		return
+	}
+	for _, collision := range subset.Collisions() {
+		log.Printf("marketfeed: ambiguous watchlist mapping %s; affected quotes are skipped", collision)
+	}
+}
+
+type registryBinding struct {
+	registry *coinregistry.Registry
+}
+
+func (b *registryBinding) SetRegistry(registry *coinregistry.Registry) {
+	b.registry = registry
+}
+
+func (b *registryBinding) coinIndex(provider coinregistry.Provider, tracked []CoinRef) coinIndex {
+	registry := b.registry
+	if registry == nil {
+		registry = coinregistry.Default()
+	}
+	return newCoinIndex(registry, provider, tracked)
+}
+
+type coinIndex struct {
+	provider coinregistry.Provider
+	subset   *coinregistry.Registry
+	refs     map[string]CoinRef
+	order    []CoinRef
+}
+
+func newCoinIndex(registry *coinregistry.Registry, provider coinregistry.Provider, tracked []CoinRef) coinIndex {
+	idx := coinIndex{
+		provider: provider,
+		refs:     make(map[string]CoinRef, len(tracked)),
+		order:    tracked,
+	}
+	for _, coin := range tracked {
+		idx.refs[coin.ID] = coin
+	}
+	subset, err := registry.Subset(toRegistryCoins(tracked))
+	if err != nil {
+		log.Printf("marketfeed: %s watchlist index failed: %v", provider, err)
+		subset, _ = coinregistry.New(nil)
+	}
+	idx.subset = subset
+	return idx
+}
+
+func (idx coinIndex) size() int {
+	return len(idx.refs)
+}
+
+func (idx coinIndex) ref(id string) CoinRef {
+	return idx.refs[id]
+}
+
+func (idx coinIndex) resolve(key string) string {
+	id, ok := idx.subset.Resolve(idx.provider, key)
+	if !ok {
+		return ""
+	}
+	return id
+}
+
+func (idx coinIndex) requestKeys() []string {
+	keys := make([]string, 0, len(idx.order))
+	for _, coin := range idx.order {
+		if key := idx.subset.ProviderKey(idx.provider, coinregistry.Coin{ID: coin.ID, Name: coin.Name, Ticker: coin.Ticker}); key != "" {
+			keys = append(keys, key)
+		}
+	}
+	return keys
+}
+
+var defaultTrackedIDs = []string{
+	"bitcoin",
+	"ethereum",
+	"the-open-network",
+	"solana",
+	"dogecoin",
+	"ripple",
+	"litecoin",
+}
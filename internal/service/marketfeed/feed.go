@@ -5,18 +5,24 @@ import (
 	"errors"
 	"fmt"
 	"log"
+	"math/rand/v2"
 	"sync"
 	"time"
 
+	"cryptoview/internal/metrics"
 	"cryptoview/internal/model"
+	"cryptoview/internal/service/coinregistry"
 	"cryptoview/internal/ui/i18n"
 )
 
 const (
 	defaultMarketPollInterval = 2 * time.Second
 	defaultFXPollInterval     = 30 * time.Second
+	latencyWindow             = 20
 )
 
+var ErrFeedNotRunning = errors.New("marketfeed: feed is not running")
+
 type StatusKind string
 
 const (
@@ -24,6 +30,7 @@ const (
 	StatusKindOK      StatusKind = "ok"
 	StatusKindWarning StatusKind = "warning"
 	StatusKindError   StatusKind = "error"
+	StatusKindCircuit StatusKind = "circuit"
 )
 
 type StatusCode string
@@ -33,13 +40,25 @@ const (
 	StatusCodeOffline     StatusCode = "offline_cached"
 	StatusCodeFallback    StatusCode = "fallback_active"
 	StatusCodeNoData      StatusCode = "no_data"
+	StatusCodeOutliers    StatusCode = "outliers_discarded"
+	StatusCodeFXStale     StatusCode = "fx_stale"
+	StatusCodeFXMissing   StatusCode = "fx_missing"
+	StatusCodeKeyRejected StatusCode = "api_key_rejected"
+
+	StatusCodeCircuitOpen     StatusCode = "circuit_open"
+	StatusCodeCircuitHalfOpen StatusCode = "circuit_half_open"
+	StatusCodeCircuitClosed   StatusCode = "circuit_closed"
 )
 
 type StatusEvent struct {
-	Kind     StatusKind
-	Code     StatusCode
-	Provider string
-	Err      error
+	Kind      StatusKind
+	Code      StatusCode
+	Provider  string
+	Err       error
+	DataAge   time.Duration
+	Discarded []string
+	RetryIn   time.Duration
+	Fiat      i18n.FiatCurrency
 }
 
 type Callbacks struct {
@@ -49,7 +68,7 @@ type Callbacks struct {
 
 type MarketProvider interface {
 	Name() string
-	FetchUSD(ctx context.Context) (MarketSnapshot, error)
+	FetchUSD(ctx context.Context, coins []CoinRef) (MarketSnapshot, error)
 }
 
 type FXProvider interface {
@@ -58,12 +77,14 @@ type FXProvider interface {
 }
 
 type CoinQuoteUSD struct {
-	ID         string
-	Name       string
-	Ticker     string
-	PriceUSD   float64
-	Change24h  *float64
-	LastUpdate time.Time
+	ID           string
+	Name         string
+	Ticker       string
+	PriceUSD     float64
+	Change24h    *float64
+	Volume24hUSD float64
+	LastUpdate   time.Time
+	Native       map[i18n.FiatCurrency]float64
 }
 
 type MarketSnapshot struct {
@@ -76,11 +97,45 @@ type FXSnapshot struct {
 	Base      string
 	FetchedAt time.Time
 	Rates     map[i18n.FiatCurrency]float64
+	// RatesAt records when each rate was last fetched, so a currency only one
+	// provider quotes keeps its own age after another provider refreshes the rest.
+	RatesAt map[i18n.FiatCurrency]time.Time
 }
 
 type providerState struct {
+	circuit             CircuitState
+	probing             bool
 	cooldownUntil       time.Time
 	consecutiveFailures int
+	lastSuccess         time.Time
+	lastFailure         time.Time
+	lastErr             error
+	latencies           []time.Duration
+	keyRejected         bool
+}
+
+type ProviderState struct {
+	Name                string
+	Circuit             CircuitState
+	ConsecutiveFailures int
+	CooldownUntil       time.Time
+	LastSuccess         time.Time
+	LastFailure         time.Time
+	LastError           error
+	LastErrorKind       FailureKind
+	LastStatusCode      int
+	AverageLatency      time.Duration
+	BudgetPerMinute     int
+	BudgetRemaining     int
+	// FX marks an exchange rate provider.
+	FX bool
+}
+
+func (s ProviderState) CooldownRemaining(now time.Time) time.Duration {
+	if s.CooldownUntil.IsZero() || !now.Before(s.CooldownUntil) {
+		return 0
+	}
+	return s.CooldownUntil.Sub(now)
 }
 
 type attemptFailure struct {
@@ -90,20 +145,59 @@ type attemptFailure struct {
 type Feed struct {
 	mu sync.RWMutex
 
-	providers   []MarketProvider
-	fxProvider  FXProvider
-	callbacks   Callbacks
-	currentFiat i18n.FiatCurrency
+	providers      []MarketProvider
+	fxProviders    []FXProvider
+	allProviders   []MarketProvider
+	allFXProviders []FXProvider
+	timeouts       map[string]time.Duration
+	callbacks      Callbacks
+	currentFiat    i18n.FiatCurrency
+	tracked        []CoinRef
+	registry       *coinregistry.Registry
+	aggregation    AggregationPolicy
 
 	lastMarket *MarketSnapshot
 	lastFX     *FXSnapshot
 	state      map[string]*providerState
+	history    map[string]*priceHistory
+	cache      *SnapshotCache
+	recorder   *Recorder
+	metrics    *feedMetrics
+
+	// savedAt is when the snapshot cache was last written, and savedMarketAt
+	// and savedFXAt the FetchedAt of the snapshots it holds.
+	savedAt       time.Time
+	savedMarketAt time.Time
+	savedFXAt     time.Time
+
+	historyCapacity int
+
+	stream          StreamingMarketProvider
+	streamDisabled  bool
+	streamChanged   chan struct{}
+	streamCancel    context.CancelFunc
+	streamConnected bool
+	lastTickAt      time.Time
+	lastTickEmit    time.Time
 
 	marketPollInterval time.Duration
 	fxPollInterval     time.Duration
+	fxStaleAfter       time.Duration
+	pollPolicies       map[string]PollPolicy
+	pollStretch        int
+	sawRateLimit       bool
+	foreground         bool
+	pollChanged        chan struct{}
+	budgets            map[string]*tokenBucket
+	circuitPolicy      CircuitPolicy
+	hedging            HedgingPolicy
+	random             func() float64
 	runCtx             context.Context
 	runCancel          context.CancelFunc
 
+	cycleMu    sync.Mutex
+	refreshing chan struct{}
+
 	stopCh   chan struct{}
 	wg       sync.WaitGroup
 	started  bool
@@ -111,35 +205,141 @@ type Feed struct {
 }
 
 func NewDefault(callbacks Callbacks) *Feed {
-	return New(
-		[]MarketProvider{
-			NewCoinGeckoProvider(1 * time.Second),
-			NewCryptoCompareProvider(3 * time.Second),
-			NewCoinLoreProvider(3 * time.Second),
-		},
-		NewOpenExchangeRatesProvider(1*time.Second),
-		callbacks,
-	)
+	f := New(defaultMarketProviders(), defaultFXProviders(), callbacks)
+	f.applyDefaults()
+	f.SetStreamingProvider(NewBinanceStreamProvider())
+	if path, err := DefaultSnapshotCachePath(); err == nil {
+		f.SetSnapshotCache(NewSnapshotCache(path))
+	} else {
+		log.Printf("marketfeed: snapshot cache disabled: %v", err)
+	}
+	return f
+}
+
+// SourceOptions selects where market data comes from at startup: the live
+// providers, the live providers with every snapshot recorded to RecordPath,
+// or a recording played back from ReplayPath.
+type SourceOptions struct {
+	RecordPath  string
+	ReplayPath  string
+	ReplaySpeed float64
 }
 
-func New(providers []MarketProvider, fxProvider FXProvider, callbacks Callbacks) *Feed {
+var ErrRecordAndReplay = errors.New("marketfeed: record and replay cannot be combined")
+
+// NewDefaultWithSource is NewDefault for the given source. Recording turns
+// off streaming so the file holds everything that was shown; replay also
+// skips the snapshot cache so a demo never overwrites real data.
+func NewDefaultWithSource(callbacks Callbacks, source SourceOptions) (*Feed, error) {
+	switch {
+	case source.RecordPath != "" && source.ReplayPath != "":
+		return nil, ErrRecordAndReplay
+	case source.ReplayPath != "":
+		speed := source.ReplaySpeed
+		if speed == 0 {
+			speed = 1
+		}
+		replay, err := NewReplayProvider(source.ReplayPath, speed)
+		if err != nil {
+			return nil, err
+		}
+		f := New([]MarketProvider{replay}, []FXProvider{replay}, callbacks)
+		f.SetRegistry(LoadUserRegistry())
+		f.SetMetrics(metrics.Default)
+		return f, nil
+	case source.RecordPath != "":
+		recorder, err := NewRecorder(source.RecordPath)
+		if err != nil {
+			return nil, err
+		}
+		var providers []MarketProvider
+		for _, provider := range defaultMarketProviders() {
+			providers = append(providers, NewRecordingProvider(provider, recorder))
+		}
+		var fxProviders []FXProvider
+		for _, provider := range defaultFXProviders() {
+			fxProviders = append(fxProviders, NewRecordingFXProvider(provider, recorder))
+		}
+		f := New(providers, fxProviders, callbacks)
+		f.applyDefaults()
+		f.recorder = recorder
+		log.Printf("marketfeed: recording path=%s", recorder.Path())
+		return f, nil
+	default:
+		return NewDefault(callbacks), nil
+	}
+}
+
+// Provider clients get the largest allowed timeout; the effective one is
+// the per-request deadline set with SetProviderTimeout.
+func defaultMarketProviders() []MarketProvider {
+	return []MarketProvider{
+		NewCoinGeckoProvider(maxRequestTimeout),
+		NewCryptoCompareProvider(maxRequestTimeout),
+		NewCoinLoreProvider(maxRequestTimeout),
+	}
+}
+
+func defaultFXProviders() []FXProvider {
+	return []FXProvider{
+		NewOpenExchangeRatesProvider(maxRequestTimeout),
+		NewECBProvider(maxRequestTimeout),
+	}
+}
+
+func (f *Feed) applyDefaults() {
+	f.SetPollPolicy("coingecko", PollPolicy{Min: 6 * time.Second, Max: 2 * time.Minute})
+	f.SetPollPolicy("cryptocompare", PollPolicy{Min: 4 * time.Second, Max: 2 * time.Minute})
+	f.SetPollPolicy("coinlore", PollPolicy{Min: 2 * time.Second, Max: 2 * time.Minute})
+	f.SetRequestBudget("coingecko", 25)
+	f.SetRequestBudget("cryptocompare", 40)
+	f.SetRequestBudget("coinlore", 60)
+	f.SetRequestBudget("open-er-api", 10)
+	f.SetRequestBudget("ecb", 10)
+	f.SetProviderTimeout("coingecko", 1*time.Second)
+	f.SetProviderTimeout("cryptocompare", 3*time.Second)
+	f.SetProviderTimeout("coinlore", 3*time.Second)
+	f.SetProviderTimeout("open-er-api", 1*time.Second)
+	f.SetProviderTimeout("ecb", 3*time.Second)
+	f.SetRegistry(LoadUserRegistry())
+	f.SetMetrics(metrics.Default)
+}
+
+func New(providers []MarketProvider, fxProviders []FXProvider, callbacks Callbacks) *Feed {
 	if len(providers) == 0 {
 		panic("marketfeed: at least one market provider is required")
 	}
-	if fxProvider == nil {
-		panic("marketfeed: fx provider is required")
+	if len(fxProviders) == 0 {
+		panic("marketfeed: at least one fx provider is required")
 	}
 
 	runCtx, runCancel := context.WithCancel(context.Background())
 
 	f := &Feed{
 		providers:          providers,
-		fxProvider:         fxProvider,
+		fxProviders:        fxProviders,
+		allProviders:       providers,
+		allFXProviders:     fxProviders,
+		timeouts:           make(map[string]time.Duration),
 		callbacks:          callbacks,
 		currentFiat:        i18n.FiatUSD,
-		state:              make(map[string]*providerState, len(providers)),
+		tracked:            DefaultTrackedCoins(),
+		registry:           coinregistry.Default(),
+		aggregation:        DefaultAggregationPolicy(),
+		state:              make(map[string]*providerState, len(providers)+len(fxProviders)),
+		history:            make(map[string]*priceHistory),
+		historyCapacity:    defaultHistoryCapacity,
 		marketPollInterval: defaultMarketPollInterval,
 		fxPollInterval:     defaultFXPollInterval,
+		fxStaleAfter:       defaultFXStaleAfter,
+		pollPolicies:       make(map[string]PollPolicy),
+		foreground:         true,
+		pollChanged:        make(chan struct{}, 1),
+		streamChanged:      make(chan struct{}, 1),
+		budgets:            make(map[string]*tokenBucket),
+		circuitPolicy:      DefaultCircuitPolicy(),
+		hedging:            DefaultHedgingPolicy(),
+		random:             rand.Float64,
 		runCtx:             runCtx,
 		runCancel:          runCancel,
 		stopCh:             make(chan struct{}),
@@ -147,6 +347,9 @@ func New(providers []MarketProvider, fxProvider FXProvider, callbacks Callbacks)
 	for _, p := range providers {
 		f.state[p.Name()] = &providerState{}
 	}
+	for _, p := range fxProviders {
+		f.state[p.Name()] = &providerState{}
+	}
 	f.lastFX = &FXSnapshot{
 		Base:      "USD",
 		FetchedAt: time.Time{},
@@ -166,13 +369,26 @@ func (f *Feed) Start() {
 	f.started = true
 	f.mu.Unlock()
 
-	f.emitStatus(StatusEvent{Kind: StatusKindLoading})
+	if !f.restoreCachedSnapshots() {
+		f.emitStatus(StatusEvent{Kind: StatusKindLoading})
+	}
 
 	f.wg.Add(1)
 	go func() {
 		defer f.wg.Done()
 		f.runLoop()
 	}()
+
+	f.mu.RLock()
+	hasStream := f.stream != nil
+	f.mu.RUnlock()
+	if hasStream {
+		f.wg.Add(1)
+		go func() {
+			defer f.wg.Done()
+			f.runStream()
+		}()
+	}
 }
 
 func (f *Feed) Stop() {
@@ -183,6 +399,118 @@ func (f *Feed) Stop() {
 		close(f.stopCh)
 	})
 	f.wg.Wait()
+	f.persistSnapshots(true)
+	if f.recorder != nil {
+		if err := f.recorder.Close(); err != nil {
+			log.Printf("marketfeed: recorder close failed path=%s err=%v", f.recorder.Path(), err)
+		}
+	}
+}
+
+func (f *Feed) SetSnapshotCache(cache *SnapshotCache) {
+	f.mu.Lock()
+	f.cache = cache
+	f.mu.Unlock()
+}
+
+func (f *Feed) restoreCachedSnapshots() bool {
+	f.mu.Lock()
+	cache := f.cache
+	if cache == nil || f.lastMarket != nil {
+		f.mu.Unlock()
+		return false
+	}
+	f.mu.Unlock()
+
+	market, fx, err := cache.Load()
+	if err != nil {
+		log.Printf("marketfeed: snapshot cache load failed path=%s err=%v", cache.Path(), err)
+		return false
+	}
+	if market == nil {
+		return false
+	}
+
+	f.mu.Lock()
+	if f.lastMarket != nil {
+		f.mu.Unlock()
+		return false
+	}
+	f.lastMarket = market
+	f.savedMarketAt = market.FetchedAt
+	f.recordHistoryLocked(market)
+	if fx != nil {
+		if _, ok := fx.Rates[i18n.FiatUSD]; !ok {
+			fx.Rates[i18n.FiatUSD] = 1
+		}
+		f.lastFX = fx
+		f.savedFXAt = fx.FetchedAt
+	}
+	coins, ok := f.buildDisplayCoinsLocked()
+	age := f.marketAgeLocked(time.Now())
+	f.mu.Unlock()
+
+	if !ok {
+		return false
+	}
+	log.Printf("marketfeed: restored cached market snapshot provider=%s age=%s", market.Provider, age.Round(time.Second))
+	f.emitMarketUpdate(coins)
+	f.emitStatus(StatusEvent{
+		Kind:     StatusKindWarning,
+		Code:     StatusCodeOffline,
+		Provider: market.Provider,
+		DataAge:  age,
+	})
+	return true
+}
+
+// persistSnapshots writes the snapshot cache when a snapshot moved on since
+// the last write, at most once per snapshotSaveInterval unless the cache
+// still lacks a snapshot the feed has or force is set.
+func (f *Feed) persistSnapshots(force bool) {
+	now := time.Now()
+	f.mu.Lock()
+	cache := f.cache
+	var market *MarketSnapshot
+	var fx *FXSnapshot
+	if f.lastMarket != nil {
+		cp := *f.lastMarket
+		market = &cp
+	}
+	if f.lastFX != nil && !f.lastFX.FetchedAt.IsZero() {
+		cp := *f.lastFX
+		fx = &cp
+	}
+	marketMoved := market != nil && market.FetchedAt.After(f.savedMarketAt)
+	fxMoved := fx != nil && fx.FetchedAt.After(f.savedFXAt)
+	missing := (market != nil && f.savedMarketAt.IsZero()) || (fx != nil && f.savedFXAt.IsZero())
+	due := force || missing || now.Sub(f.savedAt) >= snapshotSaveInterval
+	if cache == nil || !(marketMoved || fxMoved) || !due {
+		f.mu.Unlock()
+		return
+	}
+	f.savedAt = now
+	if market != nil {
+		f.savedMarketAt = market.FetchedAt
+	}
+	if fx != nil {
+		f.savedFXAt = fx.FetchedAt
+	}
+	f.mu.Unlock()
+
+	if err := cache.Save(market, fx); err != nil {
+		log.Printf("marketfeed: snapshot cache save failed path=%s err=%v", cache.Path(), err)
+	}
+}
+
+func (f *Feed) marketAgeLocked(now time.Time) time.Duration {
+	if f.lastMarket == nil || f.lastMarket.FetchedAt.IsZero() {
+		return 0
+	}
+	if age := now.Sub(f.lastMarket.FetchedAt); age > 0 {
+		return age
+	}
+	return 0
 }
 
 func (f *Feed) SetFiat(currency i18n.FiatCurrency) {
@@ -192,116 +520,304 @@ func (f *Feed) SetFiat(currency i18n.FiatCurrency) {
 	f.mu.Lock()
 	f.currentFiat = currency
 	coins, ok := f.buildDisplayCoinsLocked()
+	missingReference := f.missingQuoteReferenceLocked()
+	restartStream := f.streamCancel
 	f.mu.Unlock()
 	if ok {
 		f.emitMarketUpdate(coins)
 	}
+	f.reportFXHealth(time.Now())
+	if missingReference {
+		log.Printf("marketfeed: fetching quote reference fiat=%s", currency)
+		if restartStream != nil {
+			restartStream()
+		}
+		go func() {
+			_ = f.RefreshNow(context.Background())
+		}()
+	}
+}
+
+// FetchCoins returns the coins requested from providers: the tracked list
+// plus the reference coin of a crypto quote currency when it is not tracked.
+func (f *Feed) FetchCoins() []CoinRef {
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+	coins := make([]CoinRef, len(f.tracked), len(f.tracked)+1)
+	copy(coins, f.tracked)
+	info, ok := i18n.LookupCurrency(f.currentFiat)
+	if !ok || !info.IsCrypto() {
+		return coins
+	}
+	for _, ref := range coins {
+		if ref.ID == info.CoinID {
+			return coins
+		}
+	}
+	return append(coins, normalizeCoinRefs(f.registry, []CoinRef{{ID: info.CoinID}})...)
+}
+
+func (f *Feed) missingQuoteReferenceLocked() bool {
+	info, ok := i18n.LookupCurrency(f.currentFiat)
+	if !ok || !info.IsCrypto() {
+		return false
+	}
+	if f.lastMarket == nil {
+		return false
+	}
+	_, ok = f.lastMarket.Coins[info.CoinID]
+	return !ok
+}
+
+func (f *Feed) SetRegistry(registry *coinregistry.Registry) {
+	if registry == nil {
+		return
+	}
+	f.mu.RLock()
+	stream := f.stream
+	providers := f.allProviders
+	f.mu.RUnlock()
+	bindables := make([]any, 0, len(providers)+1)
+	for _, p := range providers {
+		bindables = append(bindables, p)
+	}
+	if stream != nil {
+		bindables = append(bindables, stream)
+	}
+	for _, p := range bindables {
+		if binder, ok := p.(interface {
+			SetRegistry(*coinregistry.Registry)
+		}); ok {
+			binder.SetRegistry(registry)
+		}
+	}
+	f.mu.Lock()
+	f.registry = registry
+	f.tracked = normalizeCoinRefs(registry, f.tracked)
+	tracked := f.tracked
+	f.mu.Unlock()
+	for _, collision := range registry.Collisions() {
+		log.Printf("marketfeed: coin registry collision %s", collision)
+	}
+	logTrackedCollisions(registry, tracked)
+}
+
+func (f *Feed) SetTrackedCoins(coins []CoinRef) {
+	f.mu.RLock()
+	registry := f.registry
+	f.mu.RUnlock()
+	normalized := normalizeCoinRefs(registry, coins)
+	if len(normalized) == 0 {
+		return
+	}
+	logTrackedCollisions(registry, normalized)
+	f.mu.Lock()
+	f.tracked = normalized
+	display, ok := f.buildDisplayCoinsLocked()
+	restartStream := f.streamCancel
+	f.mu.Unlock()
+	if ok {
+		f.emitMarketUpdate(display)
+	}
+	if restartStream != nil {
+		restartStream()
+	}
+}
+
+func (f *Feed) TrackedCoins() []CoinRef {
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+	coins := make([]CoinRef, len(f.tracked))
+	copy(coins, f.tracked)
+	return coins
 }
 
 func (f *Feed) runLoop() {
-	fxTicker := time.NewTicker(f.fxPollInterval)
+	_, fxInterval := f.PollIntervals()
+	fxTicker := time.NewTicker(fxInterval)
 	defer fxTicker.Stop()
-	marketTicker := time.NewTicker(f.marketPollInterval)
-	defer marketTicker.Stop()
 
-	f.runFXCycle()
-	f.runMarketCycle()
+	f.withCycleLock(func() {
+		f.fxCycle(priorityPoll)
+		f.runScheduledMarketCycle(priorityPoll)
+	})
+	lastCycle := time.Now()
+	marketTimer := time.NewTimer(f.nextMarketInterval(lastCycle))
+	defer marketTimer.Stop()
 
 	for {
 		select {
-		case <-marketTicker.C:
-			f.runMarketCycle()
+		case <-marketTimer.C:
+			f.withCycleLock(func() { f.runScheduledMarketCycle(priorityPoll) })
+			lastCycle = time.Now()
+			marketTimer.Reset(f.nextMarketInterval(lastCycle))
+		case <-f.pollChanged:
+			if !marketTimer.Stop() {
+				select {
+				case <-marketTimer.C:
+				default:
+				}
+			}
+			now := time.Now()
+			wait := f.nextMarketInterval(now) - now.Sub(lastCycle)
+			if wait < 0 {
+				wait = 0
+			}
+			marketTimer.Reset(wait)
+			if _, interval := f.PollIntervals(); interval != fxInterval {
+				fxInterval = interval
+				fxTicker.Reset(fxInterval)
+			}
 		case <-fxTicker.C:
-			f.runFXCycle()
+			f.withCycleLock(func() { f.fxCycle(priorityPoll) })
 		case <-f.stopCh:
 			return
 		}
 	}
 }
 
-func (f *Feed) runFXCycle() {
-	if f.isStopping() {
-		return
-	}
-	ctx, cancel := context.WithTimeout(f.runCtx, 12*time.Second)
-	defer cancel()
+func (f *Feed) withCycleLock(cycle func()) {
+	f.cycleMu.Lock()
+	defer f.cycleMu.Unlock()
+	cycle()
+}
 
-	snapshot, err := f.fxProvider.FetchRates(ctx)
-	if err != nil {
-		log.Printf("fx fetch failed via %s: %v", f.fxProvider.Name(), err)
-		return
+func (f *Feed) RefreshNow(ctx context.Context) error {
+	f.mu.Lock()
+	if !f.started || f.isStopping() {
+		f.mu.Unlock()
+		return ErrFeedNotRunning
 	}
-	if snapshot.Rates == nil {
-		return
+	done := f.refreshing
+	if done == nil {
+		done = make(chan struct{})
+		f.refreshing = done
+		f.wg.Add(1)
+		go func() {
+			defer f.wg.Done()
+			log.Printf("marketfeed: manual refresh")
+			f.withCycleLock(func() {
+				f.fxCycle(priorityInteractive)
+				f.runScheduledMarketCycle(priorityInteractive)
+			})
+			f.mu.Lock()
+			f.refreshing = nil
+			f.mu.Unlock()
+			close(done)
+		}()
 	}
-	if _, ok := snapshot.Rates[i18n.FiatUSD]; !ok {
-		snapshot.Rates[i18n.FiatUSD] = 1
+	f.mu.Unlock()
+
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
 	}
+}
 
-	f.mu.Lock()
-	f.lastFX = &snapshot
-	f.mu.Unlock()
+func (f *Feed) runFXCycle() {
+	f.fxCycle(priorityPoll)
 }
 
 func (f *Feed) runMarketCycle() {
+	f.marketCycle(priorityPoll)
+}
+
+func (f *Feed) marketCycle(priority requestPriority) {
 	if f.isStopping() {
 		return
 	}
 	now := time.Now()
-	failures := make([]attemptFailure, 0, len(f.providers))
+	if f.streamOwnsQuotes(now) {
+		f.persistSnapshots(false)
+		return
+	}
+	defer func() {
+		f.currentMetrics().observeCycle(time.Since(now))
+	}()
+	tracked := f.FetchCoins()
+	if policy := f.Aggregation(); policy.Enabled {
+		f.runAggregatedCycle(now, tracked, policy, priority)
+		return
+	}
+	if policy := f.Hedging(); policy.Enabled {
+		f.runHedgedCycle(now, tracked, policy, priority)
+		return
+	}
+	providers := f.marketProviders()
+	failures := make([]attemptFailure, 0, len(providers))
 	attemptedProviders := 0
 
-	for idx, provider := range f.providers {
+	for idx, provider := range providers {
 		if f.isStopping() {
 			return
 		}
-		if !f.providerAvailable(provider.Name(), now) {
-			remaining := f.providerCooldownRemaining(provider.Name(), now)
-			log.Printf("marketfeed: skip provider=%s reason=cooldown remaining=%s", provider.Name(), remaining.Round(time.Second))
+		if !f.acquireProvider(provider.Name(), now) {
+			continue
+		}
+		if !f.takeBudget(provider.Name(), priority, now) {
+			log.Printf("marketfeed: skip provider=%s reason=budget", provider.Name())
+			f.releaseProbe(provider.Name())
 			continue
 		}
 		log.Printf("marketfeed: fetch attempt provider=%s", provider.Name())
 		attemptedProviders++
-		snapshot, err := f.fetchProvider(now, provider)
+		snapshot, err := f.fetchProvider(now, provider, tracked)
 		if err != nil {
 			log.Printf("marketfeed: fetch failed provider=%s err=%v", provider.Name(), err)
 			failures = append(failures, attemptFailure{err: err})
 			continue
 		}
 		log.Printf("marketfeed: fetch success provider=%s coins=%d", provider.Name(), len(snapshot.Coins))
+		f.acceptMarketSnapshot(snapshot, provider.Name(), idx == 0)
+		return
+	}
+	f.handleMarketFailure(failures, attemptedProviders)
+}
 
-		f.mu.Lock()
-		f.mergeMissingChangesLocked(&snapshot)
-		f.lastMarket = &snapshot
-		coins, ok := f.buildDisplayCoinsLocked()
-		f.mu.Unlock()
+func (f *Feed) acceptMarketSnapshot(snapshot MarketSnapshot, provider string, primary bool) {
+	f.mu.Lock()
+	f.mergeMissingChangesLocked(&snapshot)
+	f.lastMarket = &snapshot
+	f.recordHistoryLocked(f.lastMarket)
+	coins, ok := f.buildDisplayCoinsLocked()
+	fxWarning, fxUnhealthy := f.fxHealthLocked(time.Now())
+	f.mu.Unlock()
 
-		if ok {
-			f.emitMarketUpdate(coins)
-		}
+	if ok {
+		f.emitMarketUpdate(coins)
+	}
+	f.persistSnapshots(false)
 
-		if idx == 0 {
-			f.emitStatus(StatusEvent{Kind: StatusKindOK, Provider: provider.Name()})
+	if primary {
+		if fxUnhealthy {
+			f.emitStatus(fxWarning)
 		} else {
-			f.emitStatus(StatusEvent{
-				Kind:     StatusKindWarning,
-				Code:     StatusCodeFallback,
-				Provider: provider.Name(),
-			})
+			f.emitStatus(StatusEvent{Kind: StatusKindOK, Provider: provider})
 		}
-		return
+	} else {
+		f.currentMetrics().observeFallback(provider)
+		f.emitStatus(StatusEvent{
+			Kind:     StatusKindWarning,
+			Code:     StatusCodeFallback,
+			Provider: provider,
+		})
 	}
+}
 
+func (f *Feed) handleMarketFailure(failures []attemptFailure, attemptedProviders int) {
 	f.mu.RLock()
 	coins, hasCache := f.buildDisplayCoinsLocked()
+	age := f.marketAgeLocked(time.Now())
 	f.mu.RUnlock()
 	if hasCache {
-		log.Printf("marketfeed: all providers failed, using cached market snapshot")
+		log.Printf("marketfeed: all providers failed, using cached market snapshot age=%s", age.Round(time.Second))
 		f.emitMarketUpdate(coins)
 		if hasRateLimitFailure(failures) {
-			f.emitStatus(StatusEvent{Kind: StatusKindWarning, Code: StatusCodeRateLimited})
+			f.emitStatus(StatusEvent{Kind: StatusKindWarning, Code: StatusCodeRateLimited, DataAge: age})
 		} else {
-			f.emitStatus(StatusEvent{Kind: StatusKindWarning, Code: StatusCodeOffline})
+			f.emitStatus(StatusEvent{Kind: StatusKindWarning, Code: StatusCodeOffline, DataAge: age})
 		}
 		return
 	}
@@ -327,7 +843,7 @@ func (f *Feed) providerAvailable(name string, now time.Time) bool {
 	if st == nil {
 		return true
 	}
-	return !now.Before(st.cooldownUntil)
+	return st.available(now)
 }
 
 func (f *Feed) providerCooldownRemaining(name string, now time.Time) time.Duration {
@@ -343,65 +859,86 @@ func (f *Feed) providerCooldownRemaining(name string, now time.Time) time.Durati
 	return st.cooldownUntil.Sub(now)
 }
 
-func (f *Feed) fetchProvider(now time.Time, provider MarketProvider) (MarketSnapshot, error) {
-	ctx, cancel := context.WithTimeout(f.runCtx, 12*time.Second)
+func (f *Feed) fetchProvider(now time.Time, provider MarketProvider, coins []CoinRef) (MarketSnapshot, error) {
+	return f.fetchProviderWithContext(f.runCtx, now, provider, coins)
+}
+
+func (f *Feed) fetchProviderWithContext(parent context.Context, now time.Time, provider MarketProvider, coins []CoinRef) (MarketSnapshot, error) {
+	ctx, cancel := context.WithTimeout(parent, f.ProviderTimeout(provider.Name()))
 	defer cancel()
 
-	snapshot, err := provider.FetchUSD(ctx)
+	started := time.Now()
+	snapshot, err := f.fetchMarket(ctx, provider, coins)
+	latency := time.Since(started)
+	if err != nil && parent.Err() != nil && f.runCtx.Err() == nil {
+		log.Printf("marketfeed: fetch cancelled provider=%s reason=hedge_lost", provider.Name())
+		f.releaseProbe(provider.Name())
+		return MarketSnapshot{}, err
+	}
+	f.currentMetrics().observeFetch(provider.Name(), latency, err)
 	if err != nil {
-		f.recordProviderFailure(now, provider.Name(), err)
+		f.recordProviderFailure(now, provider.Name(), err, latency)
+		if isRateLimit(err) {
+			f.mu.Lock()
+			f.sawRateLimit = true
+			f.mu.Unlock()
+		}
 		return MarketSnapshot{}, err
 	}
-	f.recordProviderSuccess(provider.Name())
+	f.recordProviderSuccess(provider.Name(), latency)
 	return snapshot, nil
 }
 
-func (f *Feed) recordProviderSuccess(name string) {
+func (f *Feed) recordProviderSuccess(name string, latency time.Duration) {
 	f.mu.Lock()
-	defer f.mu.Unlock()
-	st := f.state[name]
-	if st == nil {
-		st = &providerState{}
-		f.state[name] = st
+	st := f.stateLocked(name)
+	closed := st.onSuccess(time.Now())
+	st.recordLatency(latency)
+	f.mu.Unlock()
+	if closed {
+		f.emitCircuit(name, CircuitClosed, nil, 0)
 	}
-	st.consecutiveFailures = 0
-	st.cooldownUntil = time.Time{}
 }
 
-func (f *Feed) recordProviderFailure(now time.Time, name string, err error) {
+func (f *Feed) recordProviderFailure(now time.Time, name string, err error, latency time.Duration) {
 	f.mu.Lock()
-	defer f.mu.Unlock()
-	st := f.state[name]
-	if st == nil {
-		st = &providerState{}
-		f.state[name] = st
+	st := f.stateLocked(name)
+	cooldown := f.circuitPolicy.backoff(st.consecutiveFailures+1, err, f.random())
+	st.onFailure(time.Now(), err, now.Add(cooldown))
+	st.recordLatency(latency)
+	f.metrics.observeCooldown(name, cooldown)
+	var pe *ProviderError
+	keyRejected := errors.As(err, &pe) && pe.Kind == FailureKindAuth && !st.keyRejected
+	if keyRejected {
+		st.keyRejected = true
 	}
-	st.consecutiveFailures++
-	cooldown := failureCooldown(st.consecutiveFailures, err)
-	if cooldown > 0 {
-		st.cooldownUntil = now.Add(cooldown)
+	f.mu.Unlock()
+	f.emitCircuit(name, CircuitOpen, err, cooldown)
+	if keyRejected {
+		log.Printf("marketfeed: api key rejected provider=%s err=%v", name, err)
+		f.emitStatus(StatusEvent{Kind: StatusKindWarning, Code: StatusCodeKeyRejected, Provider: name, Err: err})
 	}
 }
 
-func failureCooldown(failures int, err error) time.Duration {
-	var pe *ProviderError
-	if errors.As(err, &pe) {
-		switch pe.Kind {
-		case FailureKindRateLimit:
-			if pe.RetryAfter > 0 {
-				if pe.RetryAfter > 20*time.Second {
-					return 20 * time.Second
-				}
-				return pe.RetryAfter
-			}
-			steps := []time.Duration{5 * time.Second, 10 * time.Second, 20 * time.Second}
-			return steps[minInt(failures-1, len(steps)-1)]
-		case FailureKindNetwork:
-			steps := []time.Duration{4 * time.Second, 8 * time.Second, 20 * time.Second}
-			return steps[minInt(failures-1, len(steps)-1)]
-		}
+func (st *providerState) recordLatency(latency time.Duration) {
+	if latency <= 0 {
+		return
+	}
+	st.latencies = append(st.latencies, latency)
+	if len(st.latencies) > latencyWindow {
+		st.latencies = st.latencies[len(st.latencies)-latencyWindow:]
 	}
-	return 20 * time.Second
+}
+
+func (st *providerState) averageLatency() time.Duration {
+	if len(st.latencies) == 0 {
+		return 0
+	}
+	var total time.Duration
+	for _, latency := range st.latencies {
+		total += latency
+	}
+	return total / time.Duration(len(st.latencies))
 }
 
 func (f *Feed) mergeMissingChangesLocked(next *MarketSnapshot) {
@@ -422,29 +959,90 @@ func (f *Feed) mergeMissingChangesLocked(next *MarketSnapshot) {
 	}
 }
 
-func (f *Feed) buildDisplayCoinsLocked() ([]model.Coin, bool) {
-	if f.lastMarket == nil {
+func (f *Feed) Quotes(fiat i18n.FiatCurrency) ([]model.Coin, bool) {
+	if _, ok := i18n.ParseFiatCurrency(string(fiat)); !ok {
 		return nil, false
 	}
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+	return f.buildDisplayCoinsForLocked(fiat)
+}
 
-	fiat := f.currentFiat
-	rate := 1.0
-	if f.lastFX != nil {
-		if r, ok := f.lastFX.Rates[fiat]; ok && r > 0 {
-			rate = r
-		} else if fiat != i18n.FiatUSD {
-			return nil, false
+// ProviderStates lists the active market providers followed by the active FX
+// providers. A provider serving both, such as replay, is listed once.
+func (f *Feed) ProviderStates() []ProviderState {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	now := time.Now()
+	states := make([]ProviderState, 0, len(f.providers)+len(f.fxProviders))
+	seen := make(map[string]bool, len(f.providers))
+	for _, p := range f.providers {
+		seen[p.Name()] = true
+		states = append(states, f.providerStateLocked(p.Name(), now))
+	}
+	for _, p := range f.fxProviders {
+		if seen[p.Name()] {
+			continue
 		}
-	} else if fiat != i18n.FiatUSD {
+		state := f.providerStateLocked(p.Name(), now)
+		state.FX = true
+		states = append(states, state)
+	}
+	return states
+}
+
+func (f *Feed) providerStateLocked(name string, now time.Time) ProviderState {
+	state := ProviderState{Name: name}
+	if bucket := f.budgets[name]; bucket != nil {
+		state.BudgetPerMinute = bucket.perMinute
+		state.BudgetRemaining = bucket.remaining(now)
+	}
+	if st := f.state[name]; st != nil {
+		state.Circuit = st.circuitState()
+		state.ConsecutiveFailures = st.consecutiveFailures
+		state.CooldownUntil = st.cooldownUntil
+		state.LastSuccess = st.lastSuccess
+		state.LastFailure = st.lastFailure
+		state.LastError = st.lastErr
+		state.AverageLatency = st.averageLatency()
+		var pe *ProviderError
+		if errors.As(st.lastErr, &pe) {
+			state.LastErrorKind = pe.Kind
+			state.LastStatusCode = pe.StatusCode
+		} else if st.lastErr != nil {
+			state.LastErrorKind = FailureKindOther
+		}
+	}
+	return state
+}
+
+func (f *Feed) buildDisplayCoinsLocked() ([]model.Coin, bool) {
+	return f.buildDisplayCoinsForLocked(f.currentFiat)
+}
+
+func (f *Feed) buildDisplayCoinsForLocked(fiat i18n.FiatCurrency) ([]model.Coin, bool) {
+	if f.lastMarket == nil {
 		return nil, false
 	}
 
-	coins := make([]model.Coin, 0, len(trackedOrder))
-	for _, id := range trackedOrder {
+	convert, canConvert := f.quoteConverterLocked(fiat)
+	info, _ := i18n.LookupCurrency(fiat)
+	viaFX := fiat != i18n.FiatUSD && !info.IsCrypto()
+
+	coins := make([]model.Coin, 0, len(f.tracked))
+	for _, ref := range f.tracked {
+		id := ref.ID
 		quote, ok := f.lastMarket.Coins[id]
 		if !ok {
 			continue
 		}
+		price, converted := quote.Native[fiat], false
+		if price <= 0 {
+			if !canConvert {
+				continue
+			}
+			price, converted = convert(quote.PriceUSD), viaFX
+		}
 		change := 0.0
 		if quote.Change24h != nil {
 			change = *quote.Change24h
@@ -455,9 +1053,11 @@ func (f *Feed) buildDisplayCoinsLocked() ([]model.Coin, bool) {
 		}
 		coins = append(coins, model.Coin{
 			ID:             id,
-			Name:           chooseString(quote.Name, defaultCoinNames[id], id),
-			Ticker:         chooseString(quote.Ticker, defaultTickers[id]),
-			Price:          quote.PriceUSD * rate,
+			Name:           chooseString(quote.Name, ref.Name, id),
+			Ticker:         chooseString(quote.Ticker, ref.Ticker),
+			Price:          price,
+			PriceUSD:       quote.PriceUSD,
+			Converted:      converted,
 			Change24h:      change,
 			LastUpdateTime: lastTime,
 			IconPath:       model.IconPathForID(id),
@@ -466,6 +1066,33 @@ func (f *Feed) buildDisplayCoinsLocked() ([]model.Coin, bool) {
 	return coins, len(coins) > 0
 }
 
+// quoteConverterLocked maps USD prices into the display currency. Fiat
+// currencies use the FX snapshot; crypto quote currencies divide by the
+// reference coin's USD price from the same market snapshot.
+func (f *Feed) quoteConverterLocked(fiat i18n.FiatCurrency) (func(float64) float64, bool) {
+	if info, ok := i18n.LookupCurrency(fiat); ok && info.IsCrypto() {
+		reference, ok := f.lastMarket.Coins[info.CoinID]
+		if !ok || reference.PriceUSD <= 0 {
+			return nil, false
+		}
+		return func(usd float64) float64 {
+			return usd / reference.PriceUSD * info.Units
+		}, true
+	}
+
+	rate := 1.0
+	if f.lastFX != nil {
+		if r, ok := f.lastFX.Rates[fiat]; ok && r > 0 {
+			rate = r
+		} else if fiat != i18n.FiatUSD {
+			return nil, false
+		}
+	} else if fiat != i18n.FiatUSD {
+		return nil, false
+	}
+	return func(usd float64) float64 { return usd * rate }, true
+}
+
 func (f *Feed) emitMarketUpdate(coins []model.Coin) {
 	if f.callbacks.OnMarketUpdate != nil {
 		f.callbacks.OnMarketUpdate(coins)
@@ -480,8 +1107,7 @@ func (f *Feed) emitStatus(event StatusEvent) {
 
 func hasRateLimitFailure(failures []attemptFailure) bool {
 	for _, failure := range failures {
-		var pe *ProviderError
-		if errors.As(failure.err, &pe) && pe.Kind == FailureKindRateLimit {
+		if isRateLimit(failure.err) {
 			return true
 		}
 	}
@@ -507,13 +1133,6 @@ func chooseString(values ...string) string {
 	return ""
 }
 
-func minInt(a, b int) int {
-	if a < b {
-		return a
-	}
-	return b
-}
-
 func (f *Feed) isStopping() bool {
 	select {
 	case <-f.stopCh:
@@ -523,6 +1142,16 @@ func (f *Feed) isStopping() bool {
 	}
 }
 
+func (f *Feed) SetMarketPollInterval(interval time.Duration) {
+	if interval <= 0 {
+		return
+	}
+	f.mu.Lock()
+	f.marketPollInterval = interval
+	f.mu.Unlock()
+	f.signalPollChanged()
+}
+
 func (f *Feed) setIntervalsForTest(market, fx time.Duration) {
 	if market > 0 {
 		f.marketPollInterval = market
@@ -531,33 +1160,3 @@ func (f *Feed) setIntervalsForTest(market, fx time.Duration) {
 		f.fxPollInterval = fx
 	}
 }
-
-var trackedOrder = []string{
-	"bitcoin",
-	"ethereum",
-	"the-open-network",
-	"solana",
-	"dogecoin",
-	"ripple",
-	"litecoin",
-}
-
-var defaultCoinNames = map[string]string{
-	"bitcoin":          "Bitcoin",
-	"ethereum":         "Ethereum",
-	"the-open-network": "TON Coin",
-	"solana":           "Solana",
-	"dogecoin":         "Dogecoin",
-	"ripple":           "Ripple",
-	"litecoin":         "Litecoin",
-}
-
-var defaultTickers = map[string]string{
-	"bitcoin":          "BTC",
-	"ethereum":         "ETH",
-	"the-open-network": "TON",
-	"solana":           "SOL",
-	"dogecoin":         "DOGE",
-	"ripple":           "XRP",
-	"litecoin":         "LTC",
-}
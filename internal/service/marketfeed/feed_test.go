@@ -3,6 +3,9 @@ package marketfeed
 import (
 	"context"
 	"errors"
+	"path/filepath"
+	"sync"
+	"sync/atomic"
 	"testing"
 	"time"
 
@@ -13,13 +16,15 @@ import (
 type fakeMarketProvider struct {
 	name      string
 	calls     int
+	lastCoins []CoinRef
 	fetchFunc func(context.Context) (MarketSnapshot, error)
 }
 
 func (p *fakeMarketProvider) Name() string { return p.name }
 
-func (p *fakeMarketProvider) FetchUSD(ctx context.Context) (MarketSnapshot, error) {
+func (p *fakeMarketProvider) FetchUSD(ctx context.Context, coins []CoinRef) (MarketSnapshot, error) {
 	p.calls++
+	p.lastCoins = coins
 	if p.fetchFunc == nil {
 		return MarketSnapshot{}, nil
 	}
@@ -27,11 +32,17 @@ func (p *fakeMarketProvider) FetchUSD(ctx context.Context) (MarketSnapshot, erro
 }
 
 type fakeFXProvider struct {
+	name      string
 	calls     int
 	fetchFunc func(context.Context) (FXSnapshot, error)
 }
 
-func (p *fakeFXProvider) Name() string { return "fakefx" }
+func (p *fakeFXProvider) Name() string {
+	if p.name == "" {
+		return "fakefx"
+	}
+	return p.name
+}
 
 func (p *fakeFXProvider) FetchRates(ctx context.Context) (FXSnapshot, error) {
 	p.calls++
@@ -67,7 +78,7 @@ func TestFeedFallbackOn429UsesNextProvider(t *testing.T) {
 	}
 	var gotCoins []model.Coin
 	var gotStatus StatusEvent
-	feed := New([]MarketProvider{p1, p2}, fx, Callbacks{
+	feed := New([]MarketProvider{p1, p2}, []FXProvider{fx}, Callbacks{
 		OnMarketUpdate: func(coins []model.Coin) { gotCoins = coins },
 		OnStatus:       func(event StatusEvent) { gotStatus = event },
 	})
@@ -102,7 +113,7 @@ func TestFeedRateLimitCooldownSkipsProvider(t *testing.T) {
 	fx := &fakeFXProvider{fetchFunc: func(context.Context) (FXSnapshot, error) {
 		return FXSnapshot{Base: "USD", Rates: map[i18n.FiatCurrency]float64{i18n.FiatUSD: 1}}, nil
 	}}
-	feed := New([]MarketProvider{p1, p2}, fx, Callbacks{})
+	feed := New([]MarketProvider{p1, p2}, []FXProvider{fx}, Callbacks{})
 
 	feed.runMarketCycle()
 	feed.runMarketCycle()
@@ -135,7 +146,7 @@ func TestFeedOfflineFiatRecalculationUsesCachedFX(t *testing.T) {
 		},
 	}
 	var updates [][]model.Coin
-	feed := New([]MarketProvider{p1}, fx, Callbacks{
+	feed := New([]MarketProvider{p1}, []FXProvider{fx}, Callbacks{
 		OnMarketUpdate: func(coins []model.Coin) {
 			cp := make([]model.Coin, len(coins))
 			copy(cp, coins)
@@ -178,7 +189,7 @@ func TestFeedUsesCachedDataWarningWhenAllProvidersFail(t *testing.T) {
 		return FXSnapshot{Base: "USD", Rates: map[i18n.FiatCurrency]float64{i18n.FiatUSD: 1}}, nil
 	}}
 	var lastStatus StatusEvent
-	feed := New([]MarketProvider{p1}, fx, Callbacks{
+	feed := New([]MarketProvider{p1}, []FXProvider{fx}, Callbacks{
 		OnStatus: func(event StatusEvent) { lastStatus = event },
 	})
 
@@ -195,6 +206,161 @@ func TestFeedUsesCachedDataWarningWhenAllProvidersFail(t *testing.T) {
 	}
 }
 
+func TestFeedSetTrackedCoinsDrivesRequestsAndDisplay(t *testing.T) {
+	p1 := &fakeMarketProvider{
+		name: "cg",
+		fetchFunc: func(context.Context) (MarketSnapshot, error) {
+			snapshot := snapshotWithBTC("cg", 100)
+			snapshot.Coins["cardano"] = CoinQuoteUSD{ID: "cardano", PriceUSD: 0.5}
+			return snapshot, nil
+		},
+	}
+	fx := &fakeFXProvider{}
+	var updates [][]model.Coin
+	feed := New([]MarketProvider{p1}, []FXProvider{fx}, Callbacks{
+		OnMarketUpdate: func(coins []model.Coin) { updates = append(updates, coins) },
+	})
+
+	feed.SetTrackedCoins([]CoinRef{
+		{ID: " Cardano ", Name: "Cardano", Ticker: "ada"},
+		{ID: "cardano", Name: "Duplicate", Ticker: "ADA"},
+		{ID: ""},
+	})
+	feed.runMarketCycle()
+
+	if len(p1.lastCoins) != 1 || p1.lastCoins[0].ID != "cardano" || p1.lastCoins[0].Ticker != "ADA" {
+		t.Fatalf("expected provider to receive normalized watchlist, got %+v", p1.lastCoins)
+	}
+	if len(updates) == 0 {
+		t.Fatal("expected market update")
+	}
+	last := updates[len(updates)-1]
+	if len(last) != 1 || last[0].ID != "cardano" || last[0].Name != "Cardano" || last[0].Ticker != "ADA" {
+		t.Fatalf("expected only tracked coin with watchlist metadata, got %+v", last)
+	}
+
+	feed.SetTrackedCoins(nil)
+	if got := feed.TrackedCoins(); len(got) != 1 || got[0].ID != "cardano" {
+		t.Fatalf("expected empty watchlist to be ignored, got %+v", got)
+	}
+}
+
+func TestFeedPersistsAndRestoresSnapshotCache(t *testing.T) {
+	cachePath := filepath.Join(t.TempDir(), "snapshot.json")
+	online := &fakeMarketProvider{
+		name: "cg",
+		fetchFunc: func(context.Context) (MarketSnapshot, error) {
+			snapshot := snapshotWithBTC("cg", 100)
+			snapshot.FetchedAt = time.Now().Add(-5 * time.Minute)
+			return snapshot, nil
+		},
+	}
+	fx := &fakeFXProvider{fetchFunc: func(context.Context) (FXSnapshot, error) {
+		return FXSnapshot{
+			Base:      "USD",
+			FetchedAt: time.Now(),
+			Rates:     map[i18n.FiatCurrency]float64{i18n.FiatUSD: 1, i18n.FiatEUR: 0.5},
+		}, nil
+	}}
+	first := New([]MarketProvider{online}, []FXProvider{fx}, Callbacks{})
+	first.SetSnapshotCache(NewSnapshotCache(cachePath))
+	first.runFXCycle()
+	first.runMarketCycle()
+
+	offline := &fakeMarketProvider{
+		name: "cg",
+		fetchFunc: func(ctx context.Context) (MarketSnapshot, error) {
+			<-ctx.Done()
+			return MarketSnapshot{}, ctx.Err()
+		},
+	}
+	offlineFX := &fakeFXProvider{fetchFunc: func(ctx context.Context) (FXSnapshot, error) {
+		<-ctx.Done()
+		return FXSnapshot{}, ctx.Err()
+	}}
+	var firstUpdate []model.Coin
+	var firstStatus *StatusEvent
+	second := New([]MarketProvider{offline}, []FXProvider{offlineFX}, Callbacks{
+		OnMarketUpdate: func(coins []model.Coin) {
+			if firstUpdate == nil {
+				firstUpdate = coins
+			}
+		},
+		OnStatus: func(event StatusEvent) {
+			if firstStatus == nil {
+				firstStatus = &event
+			}
+		},
+	})
+	second.SetSnapshotCache(NewSnapshotCache(cachePath))
+	second.SetFiat(i18n.FiatEUR)
+	second.Start()
+	second.Stop()
+
+	if firstUpdate == nil {
+		t.Fatal("expected cached market update on start")
+	}
+	if got := firstBTCPrice(t, firstUpdate); got != 50 {
+		t.Fatalf("expected cached price converted with cached FX, got %.2f", got)
+	}
+	if firstStatus == nil || firstStatus.Kind != StatusKindWarning || firstStatus.Code != StatusCodeOffline {
+		t.Fatalf("expected offline warning as first status, got %+v", firstStatus)
+	}
+	if firstStatus.DataAge < 5*time.Minute {
+		t.Fatalf("expected data age of at least 5m, got %s", firstStatus.DataAge)
+	}
+}
+
+func TestFeedThrottlesSnapshotCacheWrites(t *testing.T) {
+	cache := NewSnapshotCache(filepath.Join(t.TempDir(), "snapshot.json"))
+	poll := &fakeMarketProvider{name: "cg", fetchFunc: func(context.Context) (MarketSnapshot, error) {
+		return snapshotWithBTC("cg", 100), nil
+	}}
+	feed := New([]MarketProvider{poll}, []FXProvider{&fakeFXProvider{}}, Callbacks{})
+	feed.SetSnapshotCache(cache)
+	cachedPrice := func() float64 {
+		t.Helper()
+		market, _, err := cache.Load()
+		if err != nil || market == nil {
+			t.Fatalf("load cache: market=%v err=%v", market, err)
+		}
+		return market.Coins["bitcoin"].PriceUSD
+	}
+
+	feed.runMarketCycle()
+	if got := cachedPrice(); got != 100 {
+		t.Fatalf("expected the first snapshot written at once, got %v", got)
+	}
+
+	feed.applyStreamTick("fake-stream", CoinQuoteUSD{ID: "bitcoin", PriceUSD: 120})
+	feed.runMarketCycle()
+	if got := cachedPrice(); got != 100 {
+		t.Fatalf("expected stream ticks within the save interval to skip the write, got %v", got)
+	}
+
+	feed.mu.Lock()
+	feed.savedAt = time.Now().Add(-snapshotSaveInterval)
+	feed.mu.Unlock()
+	feed.runMarketCycle()
+	if got := cachedPrice(); got != 120 {
+		t.Fatalf("expected a write once the interval passed, got %v", got)
+	}
+
+	feed.applyStreamTick("fake-stream", CoinQuoteUSD{ID: "bitcoin", PriceUSD: 130})
+	feed.Stop()
+	if got := cachedPrice(); got != 130 {
+		t.Fatalf("expected Stop to write the latest snapshot, got %v", got)
+	}
+}
+
+func TestSnapshotCacheLoadMissingFile(t *testing.T) {
+	cache := NewSnapshotCache(filepath.Join(t.TempDir(), "missing.json"))
+	market, fx, err := cache.Load()
+	if err != nil || market != nil || fx != nil {
+		t.Fatalf("expected empty cache without error, got %v %v %v", market, fx, err)
+	}
+}
+
 func TestFeedStopCancelsInFlightRequests(t *testing.T) {
 	p1 := &fakeMarketProvider{
 		name: "cg",
@@ -209,7 +375,7 @@ func TestFeedStopCancelsInFlightRequests(t *testing.T) {
 			return FXSnapshot{}, ctx.Err()
 		},
 	}
-	feed := New([]MarketProvider{p1}, fx, Callbacks{})
+	feed := New([]MarketProvider{p1}, []FXProvider{fx}, Callbacks{})
 	feed.setIntervalsForTest(5*time.Second, 5*time.Second)
 
 	feed.Start()
@@ -228,6 +394,97 @@ func TestFeedStopCancelsInFlightRequests(t *testing.T) {
 	}
 }
 
+func TestFeedRefreshNowCoalescesConcurrentRequests(t *testing.T) {
+	var calls atomic.Int32
+	gate := make(chan struct{})
+	p1 := &fakeMarketProvider{
+		name: "cg",
+		fetchFunc: func(context.Context) (MarketSnapshot, error) {
+			if calls.Add(1) > 1 {
+				<-gate
+			}
+			return snapshotWithBTC("cg", 100), nil
+		},
+	}
+	feed := New([]MarketProvider{p1}, []FXProvider{&fakeFXProvider{}}, Callbacks{})
+	feed.setIntervalsForTest(time.Hour, time.Hour)
+
+	if err := feed.RefreshNow(context.Background()); !errors.Is(err, ErrFeedNotRunning) {
+		t.Fatalf("expected ErrFeedNotRunning before Start, got %v", err)
+	}
+	feed.Start()
+	defer feed.Stop()
+	waitForCalls := func(want int32) {
+		deadline := time.Now().Add(time.Second)
+		for calls.Load() < want && time.Now().Before(deadline) {
+			time.Sleep(5 * time.Millisecond)
+		}
+	}
+	waitForCalls(1)
+
+	var wg sync.WaitGroup
+	errs := make(chan error, 3)
+	refresh := func() {
+		defer wg.Done()
+		errs <- feed.RefreshNow(context.Background())
+	}
+	wg.Add(1)
+	go refresh()
+	waitForCalls(2)
+	wg.Add(2)
+	go refresh()
+	go refresh()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	if err := feed.RefreshNow(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("expected caller context to bound the wait, got %v", err)
+	}
+
+	close(gate)
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		if err != nil {
+			t.Fatalf("expected refresh to succeed, got %v", err)
+		}
+	}
+	if got := calls.Load(); got != 2 {
+		t.Fatalf("expected concurrent refreshes to share one cycle, got %d fetches", got)
+	}
+}
+
+func TestFeedRefreshNowRespectsCooldown(t *testing.T) {
+	p1 := &fakeMarketProvider{
+		name: "cg",
+		fetchFunc: func(context.Context) (MarketSnapshot, error) {
+			return MarketSnapshot{}, &ProviderError{Provider: "cg", Kind: FailureKindRateLimit, StatusCode: 429}
+		},
+	}
+	p2 := &fakeMarketProvider{
+		name: "coincap",
+		fetchFunc: func(context.Context) (MarketSnapshot, error) {
+			return snapshotWithBTC("coincap", 100), nil
+		},
+	}
+	feed := New([]MarketProvider{p1, p2}, []FXProvider{&fakeFXProvider{}}, Callbacks{})
+	feed.setIntervalsForTest(time.Hour, time.Hour)
+	feed.Start()
+	defer feed.Stop()
+
+	if err := feed.RefreshNow(context.Background()); err != nil {
+		t.Fatalf("unexpected refresh error: %v", err)
+	}
+	if err := feed.RefreshNow(context.Background()); err != nil {
+		t.Fatalf("unexpected refresh error: %v", err)
+	}
+	feed.withCycleLock(func() {
+		if p1.calls != 1 || p2.calls < 2 {
+			t.Fatalf("expected cooling provider to be skipped on refresh, got cg=%d coincap=%d", p1.calls, p2.calls)
+		}
+	})
+}
+
 func snapshotWithBTC(provider string, price float64) MarketSnapshot {
 	change := 1.25
 	return MarketSnapshot{
@@ -256,3 +513,100 @@ func firstBTCPrice(t *testing.T, coins []model.Coin) float64 {
 	t.Fatal("bitcoin not found")
 	return 0
 }
+
+func TestFeedQuotesInCryptoCurrencies(t *testing.T) {
+	provider := &fakeMarketProvider{
+		name: "cg",
+		fetchFunc: func(context.Context) (MarketSnapshot, error) {
+			snapshot := snapshotWithBTC("cg", 50000)
+			snapshot.Coins["ethereum"] = CoinQuoteUSD{ID: "ethereum", Name: "Ethereum", Ticker: "ETH", PriceUSD: 2500}
+			return snapshot, nil
+		},
+	}
+	feed := New([]MarketProvider{provider}, []FXProvider{&fakeFXProvider{}}, Callbacks{})
+	feed.SetTrackedCoins([]CoinRef{{ID: "ethereum"}})
+	feed.SetFiat(i18n.QuoteBTC)
+
+	coins := feed.FetchCoins()
+	if len(coins) != 2 || coins[0].ID != "ethereum" || coins[1].ID != "bitcoin" {
+		t.Fatalf("expected the quote reference to be fetched alongside tracked coins, got %+v", coins)
+	}
+	if tracked := feed.TrackedCoins(); len(tracked) != 1 {
+		t.Fatalf("expected tracked coins to stay unchanged, got %+v", tracked)
+	}
+
+	feed.runMarketCycle()
+	if len(provider.lastCoins) != 2 {
+		t.Fatalf("expected reference coin in provider request, got %+v", provider.lastCoins)
+	}
+	btc, ok := feed.Quotes(i18n.QuoteBTC)
+	if !ok || len(btc) != 1 || btc[0].Price != 0.05 || btc[0].PriceUSD != 2500 {
+		t.Fatalf("expected ETH priced in BTC, got %+v", btc)
+	}
+	sats, ok := feed.Quotes(i18n.QuoteSATS)
+	if !ok || sats[0].Price != 5_000_000 {
+		t.Fatalf("expected ETH priced in sats, got %+v", sats)
+	}
+	eth, ok := feed.Quotes(i18n.QuoteETH)
+	if !ok || eth[0].Price != 1 {
+		t.Fatalf("expected the quote coin itself to show as 1, got %+v", eth)
+	}
+
+	feed.SetTrackedCoins([]CoinRef{{ID: "bitcoin"}})
+	if got, ok := feed.Quotes(i18n.QuoteBTC); !ok || firstBTCPrice(t, got) != 1 {
+		t.Fatalf("expected bitcoin priced in BTC to be 1, got %+v", got)
+	}
+	if coins := feed.FetchCoins(); len(coins) != 1 {
+		t.Fatalf("expected no duplicate reference coin, got %+v", coins)
+	}
+}
+
+func TestFeedQuotesAndProviderStates(t *testing.T) {
+	p1 := &fakeMarketProvider{
+		name: "cg",
+		fetchFunc: func(context.Context) (MarketSnapshot, error) {
+			return MarketSnapshot{}, &ProviderError{Provider: "cg", Kind: FailureKindRateLimit, StatusCode: 429}
+		},
+	}
+	p2 := &fakeMarketProvider{
+		name: "coincap",
+		fetchFunc: func(context.Context) (MarketSnapshot, error) {
+			return snapshotWithBTC("coincap", 100), nil
+		},
+	}
+	fx := &fakeFXProvider{fetchFunc: func(context.Context) (FXSnapshot, error) {
+		return FXSnapshot{Base: "USD", Rates: map[i18n.FiatCurrency]float64{i18n.FiatUSD: 1, i18n.FiatEUR: 0.9}}, nil
+	}}
+	feed := New([]MarketProvider{p1, p2}, []FXProvider{fx}, Callbacks{})
+
+	if _, ok := feed.Quotes(i18n.FiatUSD); ok {
+		t.Fatal("expected no quotes before the first cycle")
+	}
+	feed.runFXCycle()
+	feed.runMarketCycle()
+
+	eur, ok := feed.Quotes(i18n.FiatEUR)
+	if !ok || firstBTCPrice(t, eur) != 90 {
+		t.Fatalf("expected EUR quotes independent of display fiat, got %+v", eur)
+	}
+	if _, ok := feed.Quotes(i18n.FiatRUB); ok {
+		t.Fatal("expected missing FX rate to yield no quotes")
+	}
+
+	states := feed.ProviderStates()
+	if len(states) != 3 || states[0].Name != "cg" || states[1].Name != "coincap" {
+		t.Fatalf("expected provider order to be preserved, got %+v", states)
+	}
+	if fx := states[2]; !fx.FX || states[0].FX || fx.LastSuccess.IsZero() {
+		t.Fatalf("expected the fx provider listed last and tagged, got %+v", fx)
+	}
+	if states[0].ConsecutiveFailures != 1 || states[0].CooldownUntil.IsZero() {
+		t.Fatalf("expected rate-limited provider to be cooling down, got %+v", states[0])
+	}
+	if states[0].LastErrorKind != FailureKindRateLimit || states[0].LastStatusCode != 429 || states[0].LastFailure.IsZero() || states[0].CooldownRemaining(time.Now()) <= 0 {
+		t.Fatalf("expected last error details for rate-limited provider, got %+v", states[0])
+	}
+	if states[1].ConsecutiveFailures != 0 || !states[1].CooldownUntil.IsZero() || states[1].LastSuccess.IsZero() || states[1].LastError != nil {
+		t.Fatalf("expected healthy fallback provider, got %+v", states[1])
+	}
+}
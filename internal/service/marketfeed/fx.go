@@ -0,0 +1,181 @@
+package marketfeed
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"cryptoview/internal/ui/i18n"
+)
+
+// Reference rates are published once per working day, so a rate from Friday
+// is still the current one on Monday morning.
+const defaultFXStaleAfter = 72 * time.Hour
+
+func (f *Feed) SetFXStaleAfter(threshold time.Duration) {
+	if threshold <= 0 {
+		threshold = defaultFXStaleAfter
+	}
+	f.mu.Lock()
+	f.fxStaleAfter = threshold
+	f.mu.Unlock()
+}
+
+func (f *Feed) FXStaleAfter() time.Duration {
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+	return f.fxStaleAfter
+}
+
+// FXRates returns a copy of the latest USD-based exchange rates.
+func (f *Feed) FXRates() map[i18n.FiatCurrency]float64 {
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+	if f.lastFX == nil {
+		return nil
+	}
+	rates := make(map[i18n.FiatCurrency]float64, len(f.lastFX.Rates))
+	for fiat, rate := range f.lastFX.Rates {
+		rates[fiat] = rate
+	}
+	return rates
+}
+
+func (f *Feed) fxCycle(priority requestPriority) {
+	if f.isStopping() {
+		return
+	}
+	now := time.Now()
+	for _, provider := range f.activeFXProviders() {
+		if f.isStopping() {
+			return
+		}
+		if !f.acquireProvider(provider.Name(), now) {
+			continue
+		}
+		if !f.takeBudget(provider.Name(), priority, now) {
+			log.Printf("marketfeed: skip fx provider=%s reason=budget", provider.Name())
+			f.releaseProbe(provider.Name())
+			continue
+		}
+		snapshot, err := f.fetchFX(now, provider)
+		if err != nil {
+			log.Printf("marketfeed: fx fetch failed provider=%s err=%v", provider.Name(), err)
+			continue
+		}
+		if snapshot.Rates == nil {
+			continue
+		}
+		if _, ok := snapshot.Rates[i18n.FiatUSD]; !ok {
+			snapshot.Rates[i18n.FiatUSD] = 1
+		}
+		if snapshot.FetchedAt.IsZero() {
+			snapshot.FetchedAt = now
+		}
+
+		f.mu.Lock()
+		merged := mergeFXSnapshot(f.lastFX, snapshot)
+		f.lastFX = &merged
+		f.mu.Unlock()
+		log.Printf("marketfeed: fx fetch success provider=%s rates=%d total=%d", provider.Name(), len(snapshot.Rates), len(merged.Rates))
+		f.persistSnapshots(false)
+		f.reportFXHealth(time.Now())
+		return
+	}
+	f.reportFXHealth(time.Now())
+}
+
+// mergeFXSnapshot overlays next onto prev. Providers quote different
+// currency sets (ECB has no RUB, UAH or KZT), so a rate the new provider
+// lacks is kept with its original fetch time rather than dropped. Fresh maps
+// are built because readers may still hold the previous ones.
+func mergeFXSnapshot(prev *FXSnapshot, next FXSnapshot) FXSnapshot {
+	merged := FXSnapshot{
+		Base:      next.Base,
+		FetchedAt: next.FetchedAt,
+		Rates:     make(map[i18n.FiatCurrency]float64, len(next.Rates)),
+		RatesAt:   make(map[i18n.FiatCurrency]time.Time, len(next.Rates)),
+	}
+	if prev != nil {
+		for fiat, rate := range prev.Rates {
+			if rate <= 0 {
+				continue
+			}
+			merged.Rates[fiat] = rate
+			if at, ok := prev.RatesAt[fiat]; ok {
+				merged.RatesAt[fiat] = at
+			} else {
+				merged.RatesAt[fiat] = prev.FetchedAt
+			}
+		}
+	}
+	for fiat, rate := range next.Rates {
+		if rate <= 0 {
+			continue
+		}
+		merged.Rates[fiat] = rate
+		merged.RatesAt[fiat] = next.FetchedAt
+	}
+	return merged
+}
+
+func (f *Feed) fetchFX(now time.Time, provider FXProvider) (FXSnapshot, error) {
+	ctx, cancel := context.WithTimeout(f.runCtx, f.ProviderTimeout(provider.Name()))
+	defer cancel()
+
+	started := time.Now()
+	snapshot, err := provider.FetchRates(ctx)
+	latency := time.Since(started)
+	f.currentMetrics().observeFetch(provider.Name(), latency, err)
+	if err != nil {
+		f.recordProviderFailure(now, provider.Name(), err, latency)
+		return FXSnapshot{}, err
+	}
+	f.recordProviderSuccess(provider.Name(), latency)
+	return snapshot, nil
+}
+
+// fxHealthLocked reports a warning when the selected fiat has no rate or its
+// own rate is older than the staleness threshold. USD, crypto quote currencies
+// and fiats every provider quoted natively never depend on FX rates.
+func (f *Feed) fxHealthLocked(now time.Time) (StatusEvent, bool) {
+	fiat := f.currentFiat
+	if fiat == i18n.FiatUSD {
+		return StatusEvent{}, false
+	}
+	if info, ok := i18n.LookupCurrency(fiat); !ok || info.IsCrypto() {
+		return StatusEvent{}, false
+	}
+	if f.nativeCoversLocked(fiat) {
+		return StatusEvent{}, false
+	}
+	if f.lastFX == nil || f.lastFX.Rates[fiat] <= 0 {
+		return StatusEvent{Kind: StatusKindWarning, Code: StatusCodeFXMissing, Fiat: fiat}, true
+	}
+	fetchedAt, ok := f.lastFX.RatesAt[fiat]
+	if !ok {
+		fetchedAt = f.lastFX.FetchedAt
+	}
+	if fetchedAt.IsZero() {
+		return StatusEvent{}, false
+	}
+	if age := now.Sub(fetchedAt); age > f.fxStaleAfter {
+		return StatusEvent{Kind: StatusKindWarning, Code: StatusCodeFXStale, Fiat: fiat, DataAge: age}, true
+	}
+	return StatusEvent{}, false
+}
+
+func (f *Feed) reportFXHealth(now time.Time) {
+	f.mu.RLock()
+	if f.lastMarket == nil {
+		f.mu.RUnlock()
+		return
+	}
+	event, unhealthy := f.fxHealthLocked(now)
+	f.mu.RUnlock()
+	if !unhealthy {
+		return
+	}
+	log.Printf("marketfeed: fx warning code=%s fiat=%s age=%s", event.Code, event.Fiat, event.DataAge.Round(time.Second))
+	f.emitStatus(event)
+}
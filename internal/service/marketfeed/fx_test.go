@@ -0,0 +1,197 @@
+package marketfeed
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"cryptoview/internal/ui/i18n"
+)
+
+func TestFeedFXFallsBackToNextProvider(t *testing.T) {
+	primary := &fakeFXProvider{name: "primary", fetchFunc: func(context.Context) (FXSnapshot, error) {
+		return FXSnapshot{}, &ProviderError{Provider: "primary", Kind: FailureKindNetwork, Err: errors.New("dial")}
+	}}
+	backup := &fakeFXProvider{name: "backup", fetchFunc: func(context.Context) (FXSnapshot, error) {
+		return FXSnapshot{Base: "USD", Rates: map[i18n.FiatCurrency]float64{i18n.FiatEUR: 0.9}}, nil
+	}}
+	feed := New([]MarketProvider{&fakeMarketProvider{name: "cg"}}, []FXProvider{primary, backup}, Callbacks{})
+
+	feed.runFXCycle()
+	if primary.calls != 1 || backup.calls != 1 {
+		t.Fatalf("expected failover to the backup provider, got primary=%d backup=%d", primary.calls, backup.calls)
+	}
+	feed.mu.RLock()
+	rate := feed.lastFX.Rates[i18n.FiatEUR]
+	usd := feed.lastFX.Rates[i18n.FiatUSD]
+	fetchedAt := feed.lastFX.FetchedAt
+	circuit := feed.state["primary"].circuitState()
+	feed.mu.RUnlock()
+	if rate != 0.9 || usd != 1 || fetchedAt.IsZero() {
+		t.Fatalf("expected backup rates with USD and fetch time filled in, got eur=%v usd=%v at=%v", rate, usd, fetchedAt)
+	}
+	if circuit != CircuitOpen {
+		t.Fatalf("expected failing fx provider circuit to open, got %s", circuit)
+	}
+
+	feed.runFXCycle()
+	if primary.calls != 1 || backup.calls != 2 {
+		t.Fatalf("expected cooling fx provider to be skipped, got primary=%d backup=%d", primary.calls, backup.calls)
+	}
+}
+
+func TestFeedReportsStaleAndMissingFX(t *testing.T) {
+	fetchedAt := time.Now().Add(-100 * time.Hour)
+	fx := &fakeFXProvider{fetchFunc: func(context.Context) (FXSnapshot, error) {
+		return FXSnapshot{Base: "USD", FetchedAt: fetchedAt, Rates: map[i18n.FiatCurrency]float64{i18n.FiatEUR: 0.9}}, nil
+	}}
+	var mu sync.Mutex
+	var events []StatusEvent
+	market := &fakeMarketProvider{name: "cg", fetchFunc: func(context.Context) (MarketSnapshot, error) {
+		return snapshotWithBTC("cg", 100), nil
+	}}
+	feed := New([]MarketProvider{market}, []FXProvider{fx}, Callbacks{
+		OnStatus: func(event StatusEvent) {
+			mu.Lock()
+			events = append(events, event)
+			mu.Unlock()
+		},
+	})
+	last := func() StatusEvent {
+		mu.Lock()
+		defer mu.Unlock()
+		return events[len(events)-1]
+	}
+
+	feed.runFXCycle()
+	feed.runMarketCycle()
+	if got := last(); got.Kind != StatusKindOK {
+		t.Fatalf("expected USD to ignore fx age, got %+v", got)
+	}
+
+	feed.SetFiat(i18n.FiatEUR)
+	if got := last(); got.Code != StatusCodeFXStale || got.Fiat != i18n.FiatEUR || got.DataAge < 100*time.Hour {
+		t.Fatalf("expected stale fx warning, got %+v", got)
+	}
+	feed.runMarketCycle()
+	if got := last(); got.Kind != StatusKindWarning || got.Code != StatusCodeFXStale {
+		t.Fatalf("expected market cycle to keep reporting stale fx, got %+v", got)
+	}
+
+	feed.SetFXStaleAfter(200 * time.Hour)
+	feed.runMarketCycle()
+	if got := last(); got.Kind != StatusKindOK {
+		t.Fatalf("expected fresh enough rates to report OK, got %+v", got)
+	}
+
+	feed.SetFiat(i18n.FiatGBP)
+	if got := last(); got.Code != StatusCodeFXMissing || got.Fiat != i18n.FiatGBP {
+		t.Fatalf("expected missing fx warning, got %+v", got)
+	}
+
+	feed.SetFiat(i18n.QuoteBTC)
+	feed.runMarketCycle()
+	if got := last(); got.Kind != StatusKindOK {
+		t.Fatalf("expected crypto quotes to ignore fx, got %+v", got)
+	}
+}
+
+func TestFeedFXKeepsRatesTheFallbackProviderLacks(t *testing.T) {
+	primaryAt := time.Now().Add(-100 * time.Hour)
+	primaryDown := false
+	primary := &fakeFXProvider{name: "primary", fetchFunc: func(context.Context) (FXSnapshot, error) {
+		if primaryDown {
+			return FXSnapshot{}, &ProviderError{Provider: "primary", Kind: FailureKindNetwork, Err: errors.New("dial")}
+		}
+		return FXSnapshot{Base: "USD", FetchedAt: primaryAt, Rates: map[i18n.FiatCurrency]float64{i18n.FiatEUR: 0.8, i18n.FiatRUB: 90}}, nil
+	}}
+	ecb := &fakeFXProvider{name: "ecb", fetchFunc: func(context.Context) (FXSnapshot, error) {
+		return FXSnapshot{Base: "USD", Rates: map[i18n.FiatCurrency]float64{i18n.FiatEUR: 0.9}}, nil
+	}}
+	var mu sync.Mutex
+	var events []StatusEvent
+	market := &fakeMarketProvider{name: "cg", fetchFunc: func(context.Context) (MarketSnapshot, error) {
+		return snapshotWithBTC("cg", 100), nil
+	}}
+	feed := New([]MarketProvider{market}, []FXProvider{primary, ecb}, Callbacks{
+		OnStatus: func(event StatusEvent) {
+			mu.Lock()
+			events = append(events, event)
+			mu.Unlock()
+		},
+	})
+	last := func() StatusEvent {
+		mu.Lock()
+		defer mu.Unlock()
+		return events[len(events)-1]
+	}
+
+	feed.runFXCycle()
+	primaryDown = true
+	feed.runFXCycle()
+	if ecb.calls != 1 {
+		t.Fatalf("expected the fallback provider to answer the second cycle, got %d calls", ecb.calls)
+	}
+	rates := feed.FXRates()
+	if rates[i18n.FiatEUR] != 0.9 || rates[i18n.FiatRUB] != 90 {
+		t.Fatalf("expected fresh EUR and the retained RUB rate, got %v", rates)
+	}
+
+	feed.runMarketCycle()
+	feed.SetFiat(i18n.FiatEUR)
+	if got := last(); got.Kind != StatusKindOK {
+		t.Fatalf("expected the refreshed EUR rate to be fresh, got %+v", got)
+	}
+	feed.SetFiat(i18n.FiatRUB)
+	if got := last(); got.Code != StatusCodeFXStale || got.Fiat != i18n.FiatRUB || got.DataAge < 100*time.Hour {
+		t.Fatalf("expected RUB to keep its own age, got %+v", got)
+	}
+}
+
+func TestECBProviderRebasesOntoUSD(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`<?xml version="1.0" encoding="UTF-8"?>
+<gesmes:Envelope xmlns:gesmes="http://www.gesmes.org/xml/2002-08-01" xmlns="http://www.ecb.int/vocabulary/2002-08-01/eurofxref">
+	<gesmes:subject>Reference rates</gesmes:subject>
+	<Cube>
+		<Cube time='2026-03-06'>
+			<Cube currency='USD' rate='1.25'/>
+			<Cube currency='GBP' rate='0.85'/>
+			<Cube currency='JPY' rate='160'/>
+		</Cube>
+	</Cube>
+</gesmes:Envelope>`))
+	}))
+	defer srv.Close()
+
+	p := &ECBProvider{httpClient: srv.Client(), baseURL: srv.URL}
+	snapshot, err := p.FetchRates(context.Background())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := map[i18n.FiatCurrency]float64{i18n.FiatUSD: 1, i18n.FiatEUR: 0.8, i18n.FiatGBP: 0.68, i18n.FiatJPY: 128}
+	for code, rate := range want {
+		if got := snapshot.Rates[code]; got < rate-1e-9 || got > rate+1e-9 {
+			t.Fatalf("rate %s = %v, want %v", code, got, rate)
+		}
+	}
+	if want := time.Date(2026, time.March, 6, 15, 0, 0, 0, time.UTC); !snapshot.FetchedAt.Equal(want) {
+		t.Fatalf("expected publication time %v, got %v", want, snapshot.FetchedAt)
+	}
+}
+
+func TestECBProviderRejectsMissingUSD(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`<Envelope><Cube><Cube time='2026-03-06'><Cube currency='GBP' rate='0.85'/></Cube></Cube></Envelope>`))
+	}))
+	defer srv.Close()
+
+	p := &ECBProvider{httpClient: srv.Client(), baseURL: srv.URL}
+	if _, err := p.FetchRates(context.Background()); err == nil {
+		t.Fatal("expected error without a USD reference rate")
+	}
+}
@@ -0,0 +1,109 @@
+package marketfeed
+
+import (
+	"context"
+	"log"
+	"time"
+)
+
+const defaultHedgeDelay = 1500 * time.Millisecond
+
+type HedgingPolicy struct {
+	Enabled bool
+	Delay   time.Duration
+}
+
+func DefaultHedgingPolicy() HedgingPolicy {
+	return HedgingPolicy{
+		Enabled: false,
+		Delay:   defaultHedgeDelay,
+	}
+}
+
+func (p HedgingPolicy) normalized() HedgingPolicy {
+	if p.Delay <= 0 {
+		p.Delay = defaultHedgeDelay
+	}
+	return p
+}
+
+func (f *Feed) SetHedging(policy HedgingPolicy) {
+	f.mu.Lock()
+	f.hedging = policy.normalized()
+	f.mu.Unlock()
+}
+
+func (f *Feed) Hedging() HedgingPolicy {
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+	return f.hedging
+}
+
+type hedgedResult struct {
+	providerResult
+	primary bool
+}
+
+func (f *Feed) runHedgedCycle(now time.Time, tracked []CoinRef, policy HedgingPolicy, priority requestPriority) {
+	ctx, cancel := context.WithCancel(f.runCtx)
+	defer cancel()
+
+	providers := f.marketProviders()
+	results := make(chan hedgedResult, len(providers))
+	failures := make([]attemptFailure, 0, len(providers))
+	next, inFlight, attempted := 0, 0, 0
+	launch := func() bool {
+		for next < len(providers) && !f.isStopping() {
+			provider := providers[next]
+			primary := next == 0
+			next++
+			if !f.acquireProvider(provider.Name(), now) {
+				continue
+			}
+			if !f.takeBudget(provider.Name(), priority, now) {
+				log.Printf("marketfeed: skip provider=%s reason=budget", provider.Name())
+				f.releaseProbe(provider.Name())
+				continue
+			}
+			log.Printf("marketfeed: fetch attempt provider=%s mode=hedged", provider.Name())
+			attempted++
+			inFlight++
+			f.wg.Add(1)
+			go func() {
+				defer f.wg.Done()
+				snapshot, err := f.fetchProviderWithContext(ctx, now, provider, tracked)
+				results <- hedgedResult{providerResult: providerResult{provider: provider.Name(), snapshot: snapshot, err: err}, primary: primary}
+			}()
+			return true
+		}
+		return false
+	}
+
+	launch()
+	hedge := time.NewTimer(policy.Delay)
+	defer hedge.Stop()
+	for inFlight > 0 {
+		select {
+		case <-hedge.C:
+			if launch() {
+				log.Printf("marketfeed: hedge launched after=%s in_flight=%d", policy.Delay, inFlight)
+				hedge.Reset(policy.Delay)
+			}
+		case res := <-results:
+			inFlight--
+			if res.err == nil {
+				cancel()
+				log.Printf("marketfeed: fetch success provider=%s coins=%d mode=hedged", res.provider, len(res.snapshot.Coins))
+				f.acceptMarketSnapshot(res.snapshot, res.provider, res.primary)
+				return
+			}
+			log.Printf("marketfeed: fetch failed provider=%s err=%v", res.provider, res.err)
+			failures = append(failures, attemptFailure{err: res.err})
+			launch()
+		}
+	}
+	if f.isStopping() {
+		return
+	}
+	f.handleMarketFailure(failures, attempted)
+}
@@ -0,0 +1,113 @@
+package marketfeed
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestFeedHedgingRacesSlowPrimary(t *testing.T) {
+	cancelled := make(chan struct{})
+	slow := &fakeMarketProvider{
+		name: "cg",
+		fetchFunc: func(ctx context.Context) (MarketSnapshot, error) {
+			<-ctx.Done()
+			close(cancelled)
+			return MarketSnapshot{}, ctx.Err()
+		},
+	}
+	fast := &fakeMarketProvider{
+		name: "coincap",
+		fetchFunc: func(context.Context) (MarketSnapshot, error) {
+			return snapshotWithBTC("coincap", 100), nil
+		},
+	}
+	var gotStatus StatusEvent
+	feed := New([]MarketProvider{slow, fast}, []FXProvider{&fakeFXProvider{}}, Callbacks{
+		OnStatus: func(event StatusEvent) {
+			if event.Kind != StatusKindCircuit {
+				gotStatus = event
+			}
+		},
+	})
+	feed.SetHedging(HedgingPolicy{Enabled: true, Delay: 20 * time.Millisecond})
+
+	started := time.Now()
+	feed.runMarketCycle()
+	if elapsed := time.Since(started); elapsed > 2*time.Second {
+		t.Fatalf("expected hedged cycle to finish quickly, took %s", elapsed)
+	}
+	if gotStatus.Code != StatusCodeFallback || gotStatus.Provider != "coincap" {
+		t.Fatalf("expected hedge winner to be reported as fallback, got %+v", gotStatus)
+	}
+	select {
+	case <-cancelled:
+	case <-time.After(time.Second):
+		t.Fatal("expected losing request to be cancelled")
+	}
+	feed.wg.Wait()
+	if state := feed.ProviderStates()[0]; state.ConsecutiveFailures != 0 || state.Circuit != CircuitClosed {
+		t.Fatalf("expected cancelled loser not to be penalised, got %+v", state)
+	}
+}
+
+func TestFeedHedgingSkipsHedgeWhenPrimaryIsFast(t *testing.T) {
+	primary := &fakeMarketProvider{
+		name: "cg",
+		fetchFunc: func(context.Context) (MarketSnapshot, error) {
+			return snapshotWithBTC("cg", 100), nil
+		},
+	}
+	secondary := &fakeMarketProvider{name: "coincap"}
+	var gotStatus StatusEvent
+	feed := New([]MarketProvider{primary, secondary}, []FXProvider{&fakeFXProvider{}}, Callbacks{
+		OnStatus: func(event StatusEvent) { gotStatus = event },
+	})
+	feed.SetHedging(HedgingPolicy{Enabled: true, Delay: time.Second})
+
+	feed.runMarketCycle()
+	if secondary.calls != 0 || gotStatus.Kind != StatusKindOK || gotStatus.Provider != "cg" {
+		t.Fatalf("expected primary to win without hedging, secondary=%d status=%+v", secondary.calls, gotStatus)
+	}
+}
+
+func TestFeedHedgingFallsThroughImmediatelyOnFailure(t *testing.T) {
+	failing := &fakeMarketProvider{
+		name: "cg",
+		fetchFunc: func(context.Context) (MarketSnapshot, error) {
+			return MarketSnapshot{}, &ProviderError{Provider: "cg", Kind: FailureKindNetwork, Err: errors.New("offline")}
+		},
+	}
+	backup := &fakeMarketProvider{
+		name: "coincap",
+		fetchFunc: func(context.Context) (MarketSnapshot, error) {
+			return snapshotWithBTC("coincap", 100), nil
+		},
+	}
+	feed := New([]MarketProvider{failing, backup}, []FXProvider{&fakeFXProvider{}}, Callbacks{})
+	feed.SetHedging(HedgingPolicy{Enabled: true, Delay: time.Hour})
+
+	done := make(chan struct{})
+	go func() {
+		feed.runMarketCycle()
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(2 * time.Second):
+		t.Fatal("expected failed primary to trigger the next provider without waiting for the hedge delay")
+	}
+	if backup.calls != 1 {
+		t.Fatalf("expected backup provider to serve the cycle, got %d calls", backup.calls)
+	}
+	if state := feed.ProviderStates()[0]; state.Circuit != CircuitOpen {
+		t.Fatalf("expected real failure to open the circuit, got %+v", state)
+	}
+}
+
+func TestHedgingPolicyNormalized(t *testing.T) {
+	if got := (HedgingPolicy{Enabled: true}).normalized(); got.Delay != defaultHedgeDelay {
+		t.Fatalf("expected default delay, got %+v", got)
+	}
+}
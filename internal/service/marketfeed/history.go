@@ -0,0 +1,87 @@
+package marketfeed
+
+import "time"
+
+const (
+	defaultHistoryCapacity = 720
+	historyMinSpacing      = 5 * time.Second
+)
+
+type priceHistory struct {
+	samples []CoinQuoteUSD
+	start   int
+	count   int
+}
+
+func newPriceHistory(capacity int) *priceHistory {
+	if capacity <= 0 {
+		capacity = defaultHistoryCapacity
+	}
+	return &priceHistory{samples: make([]CoinQuoteUSD, capacity)}
+}
+
+func (h *priceHistory) last() (CoinQuoteUSD, bool) {
+	if h.count == 0 {
+		return CoinQuoteUSD{}, false
+	}
+	return h.samples[(h.start+h.count-1)%len(h.samples)], true
+}
+
+func (h *priceHistory) push(sample CoinQuoteUSD) {
+	if h.count < len(h.samples) {
+		h.samples[(h.start+h.count)%len(h.samples)] = sample
+		h.count++
+		return
+	}
+	h.samples[h.start] = sample
+	h.start = (h.start + 1) % len(h.samples)
+}
+
+func (h *priceHistory) since(cutoff time.Time) []CoinQuoteUSD {
+	out := make([]CoinQuoteUSD, 0, h.count)
+	for i := 0; i < h.count; i++ {
+		sample := h.samples[(h.start+i)%len(h.samples)]
+		if !cutoff.IsZero() && sample.LastUpdate.Before(cutoff) {
+			continue
+		}
+		out = append(out, sample)
+	}
+	return out
+}
+
+func (f *Feed) History(id string, window time.Duration) []CoinQuoteUSD {
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+	h, ok := f.history[id]
+	if !ok {
+		return nil
+	}
+	var cutoff time.Time
+	if window > 0 {
+		cutoff = time.Now().Add(-window)
+	}
+	return h.since(cutoff)
+}
+
+func (f *Feed) recordHistoryLocked(snapshot *MarketSnapshot) {
+	if snapshot == nil {
+		return
+	}
+	for id, quote := range snapshot.Coins {
+		if quote.PriceUSD <= 0 {
+			continue
+		}
+		if quote.LastUpdate.IsZero() {
+			quote.LastUpdate = snapshot.FetchedAt
+		}
+		h, ok := f.history[id]
+		if !ok {
+			h = newPriceHistory(f.historyCapacity)
+			f.history[id] = h
+		}
+		if prev, ok := h.last(); ok && quote.LastUpdate.Before(prev.LastUpdate.Add(historyMinSpacing)) {
+			continue
+		}
+		h.push(quote)
+	}
+}
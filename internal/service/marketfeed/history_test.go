@@ -0,0 +1,54 @@
+package marketfeed
+
+import (
+	"testing"
+	"time"
+)
+
+func TestPriceHistoryRingBufferKeepsNewestSamples(t *testing.T) {
+	h := newPriceHistory(3)
+	base := time.Unix(1700000000, 0)
+	for i := 0; i < 5; i++ {
+		h.push(CoinQuoteUSD{ID: "bitcoin", PriceUSD: float64(i), LastUpdate: base.Add(time.Duration(i) * time.Minute)})
+	}
+	samples := h.since(time.Time{})
+	if len(samples) != 3 {
+		t.Fatalf("expected capacity-bounded history, got %d samples", len(samples))
+	}
+	for i, want := range []float64{2, 3, 4} {
+		if samples[i].PriceUSD != want {
+			t.Fatalf("expected oldest-first samples 2,3,4, got %+v", samples)
+		}
+	}
+	if got := h.since(base.Add(3 * time.Minute)); len(got) != 2 {
+		t.Fatalf("expected window cutoff to keep 2 samples, got %d", len(got))
+	}
+}
+
+func TestFeedHistoryRecordsSpacedSamples(t *testing.T) {
+	feed := New([]MarketProvider{&fakeMarketProvider{name: "cg"}}, []FXProvider{&fakeFXProvider{}}, Callbacks{})
+	now := time.Now()
+	feed.mu.Lock()
+	for i, offset := range []time.Duration{0, time.Second, historyMinSpacing, 2 * historyMinSpacing} {
+		snapshot := snapshotWithBTC("cg", float64(100+i))
+		quote := snapshot.Coins["bitcoin"]
+		quote.LastUpdate = now.Add(-time.Minute + offset)
+		snapshot.Coins["bitcoin"] = quote
+		feed.recordHistoryLocked(&snapshot)
+	}
+	feed.mu.Unlock()
+
+	samples := feed.History("bitcoin", 0)
+	if len(samples) != 3 {
+		t.Fatalf("expected samples closer than min spacing to be dropped, got %d", len(samples))
+	}
+	if samples[1].PriceUSD != 102 {
+		t.Fatalf("unexpected second sample %+v", samples[1])
+	}
+	if got := feed.History("bitcoin", 30*time.Second); len(got) != 0 {
+		t.Fatalf("expected window to exclude older samples, got %d", len(got))
+	}
+	if got := feed.History("ethereum", 0); got != nil {
+		t.Fatalf("expected nil history for unknown coin, got %+v", got)
+	}
+}
@@ -0,0 +1,135 @@
+package marketfeed
+
+import (
+	"errors"
+	"time"
+
+	"cryptoview/internal/metrics"
+)
+
+type feedMetrics struct {
+	attempts      *metrics.CounterVec
+	successes     *metrics.CounterVec
+	failures      *metrics.CounterVec
+	rateLimited   *metrics.CounterVec
+	fallbacks     *metrics.CounterVec
+	fetchDuration *metrics.HistogramVec
+	cooldowns     *metrics.HistogramVec
+	cycleDuration *metrics.HistogramVec
+	circuits      *metrics.CounterVec
+}
+
+// cooldownBuckets run up to defaultCircuitMaxBackoff so long circuit-open
+// cooldowns land in a real bucket instead of +Inf.
+var cooldownBuckets = []float64{1, 2, 4, 5, 8, 10, 20, 60, 120, 300, 600}
+
+func (f *Feed) SetMetrics(registry *metrics.Registry) {
+	if registry == nil {
+		f.mu.Lock()
+		f.metrics = nil
+		f.mu.Unlock()
+		return
+	}
+	m := &feedMetrics{
+		attempts:      registry.Counter("cryptoview_provider_fetch_attempts_total", "Market and FX fetch attempts per provider.", "provider"),
+		successes:     registry.Counter("cryptoview_provider_fetch_success_total", "Successful market and FX fetches per provider.", "provider"),
+		failures:      registry.Counter("cryptoview_provider_fetch_failures_total", "Failed market and FX fetches per provider and failure kind.", "provider", "kind"),
+		rateLimited:   registry.Counter("cryptoview_provider_rate_limited_total", "HTTP 429 responses per provider.", "provider"),
+		fallbacks:     registry.Counter("cryptoview_fallback_activations_total", "Market cycles served by a fallback provider.", "provider"),
+		fetchDuration: registry.Histogram("cryptoview_provider_fetch_duration_seconds", "Market and FX fetch latency per provider.", metrics.DefaultDurationBuckets, "provider"),
+		cooldowns:     registry.Histogram("cryptoview_provider_cooldown_seconds", "Cooldowns imposed on providers after failures.", cooldownBuckets, "provider"),
+		cycleDuration: registry.Histogram("cryptoview_market_cycle_duration_seconds", "Duration of a full market polling cycle.", metrics.DefaultDurationBuckets),
+		circuits:      registry.Counter("cryptoview_provider_circuit_transitions_total", "Circuit breaker transitions per provider and target state.", "provider", "state"),
+	}
+	registry.GaugeFunc("cryptoview_market_snapshot_age_seconds", "Age of the market snapshot currently served.", nil, func(emit func(float64, ...string)) {
+		f.mu.RLock()
+		age := f.marketAgeLocked(time.Now())
+		hasMarket := f.lastMarket != nil
+		f.mu.RUnlock()
+		if hasMarket {
+			emit(age.Seconds())
+		}
+	})
+	registry.GaugeFunc("cryptoview_market_poll_interval_seconds", "Current adaptive market polling interval.", nil, func(emit func(float64, ...string)) {
+		emit(f.MarketPollInterval().Seconds())
+	})
+	registry.GaugeFunc("cryptoview_provider_consecutive_failures", "Consecutive failures per provider.", []string{"provider"}, func(emit func(float64, ...string)) {
+		for _, state := range f.ProviderStates() {
+			emit(float64(state.ConsecutiveFailures), state.Name)
+		}
+	})
+	registry.GaugeFunc("cryptoview_provider_budget_remaining", "Requests left in the per-minute budget per provider.", []string{"provider"}, func(emit func(float64, ...string)) {
+		for _, budget := range f.RequestBudgets() {
+			emit(float64(budget.Remaining), budget.Provider)
+		}
+	})
+	registry.GaugeFunc("cryptoview_provider_cooldown_remaining_seconds", "Remaining cooldown per provider.", []string{"provider"}, func(emit func(float64, ...string)) {
+		now := time.Now()
+		for _, state := range f.ProviderStates() {
+			remaining := 0.0
+			if state.CooldownUntil.After(now) {
+				remaining = state.CooldownUntil.Sub(now).Seconds()
+			}
+			emit(remaining, state.Name)
+		}
+	})
+
+	f.mu.Lock()
+	f.metrics = m
+	f.mu.Unlock()
+}
+
+func (f *Feed) currentMetrics() *feedMetrics {
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+	return f.metrics
+}
+
+func (m *feedMetrics) observeFetch(provider string, elapsed time.Duration, err error) {
+	if m == nil {
+		return
+	}
+	m.attempts.Inc(provider)
+	m.fetchDuration.Observe(elapsed.Seconds(), provider)
+	if err == nil {
+		m.successes.Inc(provider)
+		return
+	}
+	kind := FailureKindOther
+	var pe *ProviderError
+	if errors.As(err, &pe) {
+		kind = pe.Kind
+		if pe.StatusCode == 429 {
+			m.rateLimited.Inc(provider)
+		}
+	}
+	m.failures.Inc(provider, string(kind))
+}
+
+func (m *feedMetrics) observeCooldown(provider string, cooldown time.Duration) {
+	if m == nil || cooldown <= 0 {
+		return
+	}
+	m.cooldowns.Observe(cooldown.Seconds(), provider)
+}
+
+func (m *feedMetrics) observeFallback(provider string) {
+	if m == nil {
+		return
+	}
+	m.fallbacks.Inc(provider)
+}
+
+func (m *feedMetrics) observeCycle(elapsed time.Duration) {
+	if m == nil {
+		return
+	}
+	m.cycleDuration.Observe(elapsed.Seconds())
+}
+
+func (m *feedMetrics) observeCircuit(provider string, state CircuitState) {
+	if m == nil {
+		return
+	}
+	m.circuits.Inc(provider, string(state))
+}
@@ -0,0 +1,65 @@
+package marketfeed
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"cryptoview/internal/metrics"
+	"cryptoview/internal/ui/i18n"
+)
+
+func TestFeedMetricsTrackFailuresCooldownsAndFallbacks(t *testing.T) {
+	p1 := &fakeMarketProvider{
+		name: "cg",
+		fetchFunc: func(context.Context) (MarketSnapshot, error) {
+			return MarketSnapshot{}, &ProviderError{Provider: "cg", Kind: FailureKindRateLimit, StatusCode: 429}
+		},
+	}
+	p2 := &fakeMarketProvider{
+		name: "coincap",
+		fetchFunc: func(context.Context) (MarketSnapshot, error) {
+			return snapshotWithBTC("coincap", 100), nil
+		},
+	}
+	fx := &fakeFXProvider{fetchFunc: func(context.Context) (FXSnapshot, error) {
+		return FXSnapshot{Base: "USD", Rates: map[i18n.FiatCurrency]float64{i18n.FiatUSD: 1}}, nil
+	}}
+	registry := metrics.NewRegistry()
+	feed := New([]MarketProvider{p1, p2}, []FXProvider{fx}, Callbacks{})
+	feed.SetMetrics(registry)
+
+	feed.runFXCycle()
+	feed.runMarketCycle()
+	feed.runMarketCycle()
+
+	var b strings.Builder
+	if err := registry.WriteText(&b); err != nil {
+		t.Fatal(err)
+	}
+	out := b.String()
+	for _, want := range []string{
+		`cryptoview_provider_fetch_attempts_total{provider="cg"} 1`,
+		`cryptoview_provider_fetch_attempts_total{provider="coincap"} 2`,
+		`cryptoview_provider_fetch_success_total{provider="coincap"} 2`,
+		`cryptoview_provider_fetch_attempts_total{provider="fakefx"} 1`,
+		`cryptoview_provider_fetch_success_total{provider="fakefx"} 1`,
+		`cryptoview_provider_fetch_failures_total{provider="cg",kind="rate_limit"} 1`,
+		`cryptoview_provider_rate_limited_total{provider="cg"} 1`,
+		`cryptoview_provider_cooldown_seconds_count{provider="cg"} 1`,
+		`cryptoview_fallback_activations_total{provider="coincap"} 2`,
+		`cryptoview_market_cycle_duration_seconds_count 2`,
+		`cryptoview_provider_consecutive_failures{provider="cg"} 1`,
+		`cryptoview_market_snapshot_age_seconds `,
+	} {
+		if !strings.Contains(out, want) {
+			t.Fatalf("expected %q in metrics output:\n%s", want, out)
+		}
+	}
+}
+
+func TestCooldownBucketsCoverMaxBackoff(t *testing.T) {
+	if last := cooldownBuckets[len(cooldownBuckets)-1]; last < defaultCircuitMaxBackoff.Seconds() {
+		t.Fatalf("largest cooldown bucket %vs is below the %s backoff ceiling", last, defaultCircuitMaxBackoff)
+	}
+}
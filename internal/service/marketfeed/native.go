@@ -0,0 +1,94 @@
+package marketfeed
+
+import (
+	"context"
+
+	"cryptoview/internal/ui/i18n"
+)
+
+// NativeQuoteProvider is implemented by providers that can quote fiats
+// directly instead of leaving the conversion to FX rates. USD is always
+// fetched; the requested fiats end up in CoinQuoteUSD.Native.
+type NativeQuoteProvider interface {
+	MarketProvider
+	FetchQuotes(ctx context.Context, coins []CoinRef, fiats []i18n.FiatCurrency) (MarketSnapshot, error)
+}
+
+func (f *Feed) fetchMarket(ctx context.Context, provider MarketProvider, coins []CoinRef) (MarketSnapshot, error) {
+	if native, ok := provider.(NativeQuoteProvider); ok {
+		if fiats := f.nativeFiats(); len(fiats) > 0 {
+			return native.FetchQuotes(ctx, coins, fiats)
+		}
+	}
+	return provider.FetchUSD(ctx, coins)
+}
+
+func (f *Feed) nativeFiats() []i18n.FiatCurrency {
+	f.mu.RLock()
+	fiat := f.currentFiat
+	f.mu.RUnlock()
+	if fiat == i18n.FiatUSD {
+		return nil
+	}
+	if info, ok := i18n.LookupCurrency(fiat); !ok || info.IsCrypto() {
+		return nil
+	}
+	return []i18n.FiatCurrency{fiat}
+}
+
+// nativeCoversLocked reports whether every tracked coin in the current
+// snapshot has a native quote in fiat, so FX rates are not needed at all.
+func (f *Feed) nativeCoversLocked(fiat i18n.FiatCurrency) bool {
+	if f.lastMarket == nil {
+		return false
+	}
+	covered := 0
+	for _, ref := range f.tracked {
+		quote, ok := f.lastMarket.Coins[ref.ID]
+		if !ok {
+			continue
+		}
+		if quote.Native[fiat] <= 0 {
+			return false
+		}
+		covered++
+	}
+	return covered > 0
+}
+
+// quoteFiats drops USD, duplicates and fiats the provider cannot quote.
+func quoteFiats(fiats []i18n.FiatCurrency, supported func(i18n.FiatCurrency) bool) []i18n.FiatCurrency {
+	out := make([]i18n.FiatCurrency, 0, len(fiats))
+	seen := make(map[i18n.FiatCurrency]bool, len(fiats))
+	for _, fiat := range fiats {
+		if fiat == i18n.FiatUSD || seen[fiat] || !supported(fiat) {
+			continue
+		}
+		seen[fiat] = true
+		out = append(out, fiat)
+	}
+	return out
+}
+
+func nativePrices(fiats []i18n.FiatCurrency, price func(i18n.FiatCurrency) float64) map[i18n.FiatCurrency]float64 {
+	var native map[i18n.FiatCurrency]float64
+	for _, fiat := range fiats {
+		if v := price(fiat); v > 0 {
+			if native == nil {
+				native = make(map[i18n.FiatCurrency]float64, len(fiats))
+			}
+			native[fiat] = v
+		}
+	}
+	return native
+}
+
+// fiatCodes lists USD followed by fiats, as request parameters.
+func fiatCodes(fiats []i18n.FiatCurrency) []string {
+	codes := make([]string, 0, len(fiats)+1)
+	codes = append(codes, string(i18n.FiatUSD))
+	for _, fiat := range fiats {
+		codes = append(codes, string(fiat))
+	}
+	return codes
+}
@@ -0,0 +1,95 @@
+package marketfeed
+
+import (
+	"context"
+	"testing"
+
+	"cryptoview/internal/ui/i18n"
+)
+
+type fakeNativeProvider struct {
+	fakeMarketProvider
+	fiats  []i18n.FiatCurrency
+	native map[i18n.FiatCurrency]float64
+}
+
+func (p *fakeNativeProvider) FetchQuotes(ctx context.Context, coins []CoinRef, fiats []i18n.FiatCurrency) (MarketSnapshot, error) {
+	p.fiats = fiats
+	snapshot, err := p.FetchUSD(ctx, coins)
+	if err != nil {
+		return snapshot, err
+	}
+	quote := snapshot.Coins["bitcoin"]
+	quote.Native = p.native
+	snapshot.Coins["bitcoin"] = quote
+	return snapshot, nil
+}
+
+func TestFeedPrefersNativeQuotes(t *testing.T) {
+	provider := &fakeNativeProvider{
+		fakeMarketProvider: fakeMarketProvider{name: "cg", fetchFunc: func(context.Context) (MarketSnapshot, error) {
+			return snapshotWithBTC("cg", 100), nil
+		}},
+		native: map[i18n.FiatCurrency]float64{i18n.FiatEUR: 93},
+	}
+	fx := &fakeFXProvider{fetchFunc: func(context.Context) (FXSnapshot, error) {
+		return FXSnapshot{Base: "USD", Rates: map[i18n.FiatCurrency]float64{i18n.FiatEUR: 0.9}}, nil
+	}}
+	var events []StatusEvent
+	feed := New([]MarketProvider{provider}, []FXProvider{fx}, Callbacks{
+		OnStatus: func(event StatusEvent) { events = append(events, event) },
+	})
+	feed.SetTrackedCoins([]CoinRef{{ID: "bitcoin"}})
+	feed.runFXCycle()
+
+	feed.runMarketCycle()
+	if provider.fiats != nil {
+		t.Fatalf("expected plain USD fetch for USD display, got %v", provider.fiats)
+	}
+	eur, ok := feed.Quotes(i18n.FiatEUR)
+	if !ok || eur[0].Price != 90 || !eur[0].Converted {
+		t.Fatalf("expected FX conversion without native quotes, got %+v", eur)
+	}
+
+	feed.SetFiat(i18n.FiatEUR)
+	feed.runMarketCycle()
+	if len(provider.fiats) != 1 || provider.fiats[0] != i18n.FiatEUR {
+		t.Fatalf("expected native EUR request, got %v", provider.fiats)
+	}
+	eur, ok = feed.Quotes(i18n.FiatEUR)
+	if !ok || eur[0].Price != 93 || eur[0].Converted {
+		t.Fatalf("expected native EUR quote, got %+v", eur)
+	}
+	if usd, _ := feed.Quotes(i18n.FiatUSD); usd[0].Price != 100 || usd[0].Converted {
+		t.Fatalf("expected USD quote untouched, got %+v", usd)
+	}
+
+	feed.mu.Lock()
+	delete(feed.lastFX.Rates, i18n.FiatEUR)
+	feed.mu.Unlock()
+	feed.runMarketCycle()
+	if eur, ok := feed.Quotes(i18n.FiatEUR); !ok || eur[0].Price != 93 {
+		t.Fatalf("expected native quotes without an FX rate, got %+v", eur)
+	}
+	if last := events[len(events)-1]; last.Kind != StatusKindOK {
+		t.Fatalf("expected no fx warning when quotes are native, got %+v", last)
+	}
+	if gbp, ok := feed.Quotes(i18n.FiatGBP); ok {
+		t.Fatalf("expected no GBP quotes without native price or FX rate, got %+v", gbp)
+	}
+}
+
+func TestAggregateSnapshotsMergesNativeQuotes(t *testing.T) {
+	a := snapshotWithBTC("a", 100)
+	b := snapshotWithBTC("b", 101)
+	c := snapshotWithBTC("c", 102)
+	for i, snapshot := range []MarketSnapshot{a, b, c} {
+		quote := snapshot.Coins["bitcoin"]
+		quote.Native = map[i18n.FiatCurrency]float64{i18n.FiatEUR: 90 + float64(i)}
+		snapshot.Coins["bitcoin"] = quote
+	}
+	merged, _ := aggregateSnapshots([]MarketSnapshot{a, b, c}, DefaultAggregationPolicy())
+	if got := merged.Coins["bitcoin"].Native[i18n.FiatEUR]; got != 91 {
+		t.Fatalf("expected median native EUR quote, got %v", got)
+	}
+}
@@ -0,0 +1,129 @@
+package marketfeed
+
+import (
+	"errors"
+	"log"
+	"time"
+)
+
+const (
+	backgroundPollFactor = 5
+	maxPollStretch       = 6
+)
+
+type PollPolicy struct {
+	Min time.Duration
+	Max time.Duration
+}
+
+func (p PollPolicy) normalized() PollPolicy {
+	if p.Min < 0 {
+		p.Min = 0
+	}
+	if p.Max > 0 && p.Max < p.Min {
+		p.Max = p.Min
+	}
+	return p
+}
+
+func (p PollPolicy) clamp(interval time.Duration) time.Duration {
+	if p.Min > 0 && interval < p.Min {
+		interval = p.Min
+	}
+	if p.Max > 0 && interval > p.Max {
+		interval = p.Max
+	}
+	return interval
+}
+
+func (f *Feed) SetPollPolicy(provider string, policy PollPolicy) {
+	f.mu.Lock()
+	f.pollPolicies[provider] = policy.normalized()
+	f.mu.Unlock()
+	f.signalPollChanged()
+}
+
+func (f *Feed) PollPolicy(provider string) PollPolicy {
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+	return f.pollPolicies[provider]
+}
+
+func (f *Feed) SetForeground(foreground bool) {
+	f.mu.Lock()
+	changed := f.foreground != foreground
+	f.foreground = foreground
+	f.mu.Unlock()
+	if changed {
+		log.Printf("marketfeed: foreground=%t", foreground)
+		f.signalPollChanged()
+	}
+}
+
+func (f *Feed) MarketPollInterval() time.Duration {
+	return f.nextMarketInterval(time.Now())
+}
+
+func (f *Feed) signalPollChanged() {
+	select {
+	case f.pollChanged <- struct{}{}:
+	default:
+	}
+}
+
+func (f *Feed) nextMarketInterval(now time.Time) time.Duration {
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+	base := f.marketPollInterval << f.pollStretch
+	if !f.foreground {
+		base *= backgroundPollFactor
+	}
+
+	limit := 1
+	if f.aggregation.Enabled {
+		limit = f.aggregation.Providers
+	}
+	var interval time.Duration
+	scheduled := 0
+	for _, provider := range f.providers {
+		if scheduled == limit {
+			break
+		}
+		if st := f.state[provider.Name()]; st != nil && !st.available(now) {
+			continue
+		}
+		scheduled++
+		if d := f.pollPolicies[provider.Name()].clamp(base); d > interval {
+			interval = d
+		}
+	}
+	if interval == 0 {
+		interval = base
+	}
+	return interval
+}
+
+func (f *Feed) runScheduledMarketCycle(priority requestPriority) {
+	f.marketCycle(priority)
+
+	f.mu.Lock()
+	rateLimited := f.sawRateLimit
+	f.sawRateLimit = false
+	previous := f.pollStretch
+	switch {
+	case rateLimited && f.pollStretch < maxPollStretch:
+		f.pollStretch++
+	case !rateLimited && f.pollStretch > 0:
+		f.pollStretch--
+	}
+	stretch := f.pollStretch
+	f.mu.Unlock()
+	if stretch != previous {
+		log.Printf("marketfeed: poll interval adjusted stretch=%d interval=%s", stretch, f.nextMarketInterval(time.Now()))
+	}
+}
+
+func isRateLimit(err error) bool {
+	var pe *ProviderError
+	return errors.As(err, &pe) && pe.Kind == FailureKindRateLimit
+}
@@ -0,0 +1,95 @@
+package marketfeed
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestFeedPollPolicyClampsInterval(t *testing.T) {
+	p1 := &fakeMarketProvider{name: "cg"}
+	p2 := &fakeMarketProvider{name: "coincap"}
+	feed := New([]MarketProvider{p1, p2}, []FXProvider{&fakeFXProvider{}}, Callbacks{})
+	feed.setIntervalsForTest(2*time.Second, 0)
+	now := time.Now()
+
+	if got := feed.nextMarketInterval(now); got != 2*time.Second {
+		t.Fatalf("expected base interval without policies, got %s", got)
+	}
+	feed.SetPollPolicy("cg", PollPolicy{Min: 6 * time.Second, Max: time.Minute})
+	feed.SetPollPolicy("coincap", PollPolicy{Min: 3 * time.Second, Max: 30 * time.Second})
+	if got := feed.nextMarketInterval(now); got != 6*time.Second {
+		t.Fatalf("expected primary provider minimum, got %s", got)
+	}
+
+	feed.state["cg"].circuit = CircuitOpen
+	feed.state["cg"].cooldownUntil = now.Add(10 * time.Second)
+	if got := feed.nextMarketInterval(now); got != 3*time.Second {
+		t.Fatalf("expected fallback provider policy while primary cools down, got %s", got)
+	}
+
+	feed.SetForeground(false)
+	if got := feed.nextMarketInterval(now); got != 10*time.Second {
+		t.Fatalf("expected background interval to stretch, got %s", got)
+	}
+	feed.pollStretch = maxPollStretch
+	if got := feed.nextMarketInterval(now); got != 30*time.Second {
+		t.Fatalf("expected provider maximum to cap the interval, got %s", got)
+	}
+	feed.pollStretch = 0
+	feed.SetForeground(true)
+	if got := feed.nextMarketInterval(now); got != 3*time.Second {
+		t.Fatalf("expected foreground to tighten the interval again, got %s", got)
+	}
+}
+
+func TestFeedPollIntervalBacksOffOnRateLimit(t *testing.T) {
+	limited := true
+	p1 := &fakeMarketProvider{
+		name: "cg",
+		fetchFunc: func(context.Context) (MarketSnapshot, error) {
+			if limited {
+				return MarketSnapshot{}, &ProviderError{Provider: "cg", Kind: FailureKindRateLimit, StatusCode: 429}
+			}
+			return snapshotWithBTC("cg", 100), nil
+		},
+	}
+	feed := New([]MarketProvider{p1}, []FXProvider{&fakeFXProvider{}}, Callbacks{})
+	feed.setIntervalsForTest(2*time.Second, 0)
+	feed.SetPollPolicy("cg", PollPolicy{Max: 10 * time.Second})
+
+	feed.runScheduledMarketCycle(priorityPoll)
+	feed.state["cg"].cooldownUntil = time.Time{}
+	feed.runScheduledMarketCycle(priorityPoll)
+	feed.state["cg"].cooldownUntil = time.Time{}
+	if got := feed.nextMarketInterval(time.Now()); got != 8*time.Second {
+		t.Fatalf("expected two rate-limited cycles to quadruple the interval, got %s", got)
+	}
+	feed.runScheduledMarketCycle(priorityPoll)
+	feed.state["cg"].cooldownUntil = time.Time{}
+	if got := feed.nextMarketInterval(time.Now()); got != 10*time.Second {
+		t.Fatalf("expected backoff capped at policy maximum, got %s", got)
+	}
+
+	limited = false
+	feed.runScheduledMarketCycle(priorityPoll)
+	if got := feed.nextMarketInterval(time.Now()); got != 8*time.Second {
+		t.Fatalf("expected successful cycle to tighten the interval, got %s", got)
+	}
+	for i := 0; i < maxPollStretch; i++ {
+		feed.runScheduledMarketCycle(priorityPoll)
+	}
+	if got := feed.nextMarketInterval(time.Now()); got != 2*time.Second {
+		t.Fatalf("expected interval back at base after recovery, got %s", got)
+	}
+}
+
+func TestPollPolicyNormalized(t *testing.T) {
+	got := PollPolicy{Min: 10 * time.Second, Max: 5 * time.Second}.normalized()
+	if got.Min != 10*time.Second || got.Max != 10*time.Second {
+		t.Fatalf("expected max raised to min, got %+v", got)
+	}
+	if d := (PollPolicy{}).clamp(3 * time.Second); d != 3*time.Second {
+		t.Fatalf("expected zero policy to leave interval unchanged, got %s", d)
+	}
+}
@@ -0,0 +1,254 @@
+package marketfeed
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	defaultRequestTimeout = 12 * time.Second
+	maxRequestTimeout     = time.Minute
+)
+
+var (
+	ErrNoProviderEnabled = errors.New("marketfeed: at least one provider must stay enabled")
+	ErrAPIKeyUnsupported = errors.New("marketfeed: provider does not take an API key")
+)
+
+// ProviderSetting is the user-facing configuration of one provider. Timeout
+// bounds each request; zero in SetMarketProviders/SetFXProviders restores
+// the feed default.
+type ProviderSetting struct {
+	Name           string
+	Enabled        bool
+	Timeout        time.Duration
+	SupportsAPIKey bool
+}
+
+// APIKeyProvider is implemented by providers with a paid tier. An empty key
+// switches back to the free tier.
+type APIKeyProvider interface {
+	SetAPIKey(key string)
+}
+
+type apiKeyBinding struct {
+	keyMu sync.RWMutex
+	key   string
+}
+
+func (b *apiKeyBinding) SetAPIKey(key string) {
+	b.keyMu.Lock()
+	b.key = strings.TrimSpace(key)
+	b.keyMu.Unlock()
+}
+
+func (b *apiKeyBinding) apiKey() string {
+	b.keyMu.RLock()
+	defer b.keyMu.RUnlock()
+	return b.key
+}
+
+type namedProvider interface {
+	Name() string
+}
+
+func (f *Feed) MarketProviderSettings() []ProviderSetting {
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+	return providerSettingsLocked(f, f.allProviders)
+}
+
+func (f *Feed) FXProviderSettings() []ProviderSetting {
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+	return providerSettingsLocked(f, f.allFXProviders)
+}
+
+// SetMarketProviders reorders, enables and disables market providers. The
+// first enabled provider becomes the primary one. Providers missing from
+// settings keep their state and move to the end.
+func (f *Feed) SetMarketProviders(settings []ProviderSetting) error {
+	f.mu.Lock()
+	ordered, active, err := arrangeProviders(f.allProviders, f.providers, settings)
+	if err != nil {
+		f.mu.Unlock()
+		return err
+	}
+	f.allProviders = ordered
+	f.providers = active
+	f.applyProviderSettingsLocked(settings)
+	f.mu.Unlock()
+	f.signalPollChanged()
+	return nil
+}
+
+func (f *Feed) SetFXProviders(settings []ProviderSetting) error {
+	f.mu.Lock()
+	ordered, active, err := arrangeProviders(f.allFXProviders, f.fxProviders, settings)
+	if err != nil {
+		f.mu.Unlock()
+		return err
+	}
+	f.allFXProviders = ordered
+	f.fxProviders = active
+	f.applyProviderSettingsLocked(settings)
+	f.mu.Unlock()
+	return nil
+}
+
+func (f *Feed) SetProviderTimeout(provider string, timeout time.Duration) {
+	f.mu.Lock()
+	f.setProviderTimeoutLocked(provider, timeout)
+	f.mu.Unlock()
+}
+
+func (f *Feed) ProviderTimeout(provider string) time.Duration {
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+	return f.providerTimeoutLocked(provider)
+}
+
+func (f *Feed) providerTimeoutLocked(provider string) time.Duration {
+	if timeout := f.timeouts[provider]; timeout > 0 {
+		return timeout
+	}
+	return defaultRequestTimeout
+}
+
+// SetAPIKey hands key to the named provider, disabled or not, and takes
+// effect from its next request.
+func (f *Feed) SetAPIKey(provider string, key string) error {
+	f.mu.RLock()
+	var target any
+	for _, p := range f.allProviders {
+		if p.Name() == provider {
+			target = p
+		}
+	}
+	for _, p := range f.allFXProviders {
+		if p.Name() == provider {
+			target = p
+		}
+	}
+	f.mu.RUnlock()
+	if target == nil {
+		return fmt.Errorf("marketfeed: unknown provider %q", provider)
+	}
+	keyed, ok := target.(APIKeyProvider)
+	if !ok {
+		return fmt.Errorf("%s: %w", provider, ErrAPIKeyUnsupported)
+	}
+	keyed.SetAPIKey(key)
+	f.mu.Lock()
+	f.stateLocked(provider).keyRejected = false
+	f.mu.Unlock()
+	return nil
+}
+
+func (f *Feed) SetFXPollInterval(interval time.Duration) {
+	if interval <= 0 {
+		return
+	}
+	f.mu.Lock()
+	f.fxPollInterval = interval
+	f.mu.Unlock()
+	f.signalPollChanged()
+}
+
+// PollIntervals returns the configured base intervals, before backoff and
+// per-provider poll policies are applied.
+func (f *Feed) PollIntervals() (market, fx time.Duration) {
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+	return f.marketPollInterval, f.fxPollInterval
+}
+
+func (f *Feed) marketProviders() []MarketProvider {
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+	return f.providers
+}
+
+func (f *Feed) activeFXProviders() []FXProvider {
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+	return f.fxProviders
+}
+
+func (f *Feed) applyProviderSettingsLocked(settings []ProviderSetting) {
+	for _, setting := range settings {
+		f.setProviderTimeoutLocked(setting.Name, setting.Timeout)
+	}
+}
+
+func (f *Feed) setProviderTimeoutLocked(provider string, timeout time.Duration) {
+	if timeout <= 0 {
+		delete(f.timeouts, provider)
+		return
+	}
+	if timeout > maxRequestTimeout {
+		timeout = maxRequestTimeout
+	}
+	f.timeouts[provider] = timeout
+}
+
+func providerSettingsLocked[P namedProvider](f *Feed, all []P) []ProviderSetting {
+	enabled := make(map[string]bool)
+	for _, p := range f.providers {
+		enabled[p.Name()] = true
+	}
+	for _, p := range f.fxProviders {
+		enabled[p.Name()] = true
+	}
+	settings := make([]ProviderSetting, 0, len(all))
+	for _, p := range all {
+		_, keyed := any(p).(APIKeyProvider)
+		settings = append(settings, ProviderSetting{
+			Name:           p.Name(),
+			Enabled:        enabled[p.Name()],
+			Timeout:        f.providerTimeoutLocked(p.Name()),
+			SupportsAPIKey: keyed,
+		})
+	}
+	return settings
+}
+
+func arrangeProviders[P namedProvider](all, current []P, settings []ProviderSetting) (ordered, active []P, err error) {
+	wasActive := make(map[string]bool, len(current))
+	for _, p := range current {
+		wasActive[p.Name()] = true
+	}
+	byName := make(map[string]P, len(all))
+	for _, p := range all {
+		byName[p.Name()] = p
+	}
+	placed := make(map[string]bool, len(all))
+	ordered = make([]P, 0, len(all))
+	for _, setting := range settings {
+		p, ok := byName[setting.Name]
+		if !ok || placed[setting.Name] {
+			continue
+		}
+		placed[setting.Name] = true
+		ordered = append(ordered, p)
+		if setting.Enabled {
+			active = append(active, p)
+		}
+	}
+	for _, p := range all {
+		if placed[p.Name()] {
+			continue
+		}
+		ordered = append(ordered, p)
+		if wasActive[p.Name()] {
+			active = append(active, p)
+		}
+	}
+	if len(active) == 0 {
+		return nil, nil, ErrNoProviderEnabled
+	}
+	return ordered, active, nil
+}
@@ -0,0 +1,124 @@
+package marketfeed
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"cryptoview/internal/ui/i18n"
+)
+
+type keyedFakeProvider struct {
+	fakeMarketProvider
+	apiKeyBinding
+}
+
+func TestFeedSetMarketProvidersReordersAndDisables(t *testing.T) {
+	var deadlines []time.Duration
+	fetch := func(name string) func(context.Context) (MarketSnapshot, error) {
+		return func(ctx context.Context) (MarketSnapshot, error) {
+			deadline, _ := ctx.Deadline()
+			deadlines = append(deadlines, time.Until(deadline).Round(time.Second))
+			return snapshotWithBTC(name, 100), nil
+		}
+	}
+	cg := &fakeMarketProvider{name: "cg", fetchFunc: fetch("cg")}
+	cc := &keyedFakeProvider{fakeMarketProvider: fakeMarketProvider{name: "cc", fetchFunc: fetch("cc")}}
+	lore := &fakeMarketProvider{name: "lore", fetchFunc: fetch("lore")}
+	fx := &fakeFXProvider{fetchFunc: func(context.Context) (FXSnapshot, error) {
+		return FXSnapshot{Base: "USD", Rates: map[i18n.FiatCurrency]float64{i18n.FiatUSD: 1}}, nil
+	}}
+	feed := New([]MarketProvider{cg, cc, lore}, []FXProvider{fx}, Callbacks{})
+
+	err := feed.SetMarketProviders([]ProviderSetting{
+		{Name: "cc", Enabled: true, Timeout: 5 * time.Second},
+		{Name: "cg", Enabled: false},
+	})
+	if err != nil {
+		t.Fatalf("set providers: %v", err)
+	}
+	settings := feed.MarketProviderSettings()
+	want := []ProviderSetting{
+		{Name: "cc", Enabled: true, Timeout: 5 * time.Second, SupportsAPIKey: true},
+		{Name: "cg", Enabled: false, Timeout: defaultRequestTimeout},
+		{Name: "lore", Enabled: true, Timeout: defaultRequestTimeout},
+	}
+	if len(settings) != len(want) {
+		t.Fatalf("expected %d settings, got %+v", len(want), settings)
+	}
+	for i := range want {
+		if settings[i] != want[i] {
+			t.Fatalf("setting %d: expected %+v, got %+v", i, want[i], settings[i])
+		}
+	}
+
+	feed.runFXCycle()
+	feed.runMarketCycle()
+	if cg.calls != 0 || cc.calls != 1 {
+		t.Fatalf("expected disabled provider to be skipped, calls cg=%d cc=%d", cg.calls, cc.calls)
+	}
+	if len(deadlines) != 1 || deadlines[0] != 5*time.Second {
+		t.Fatalf("expected the configured timeout on the request, got %v", deadlines)
+	}
+
+	if err := feed.SetMarketProviders([]ProviderSetting{{Name: "cc"}, {Name: "cg"}, {Name: "lore"}}); !errors.Is(err, ErrNoProviderEnabled) {
+		t.Fatalf("expected ErrNoProviderEnabled, got %v", err)
+	}
+	if got := feed.MarketProviderSettings(); !got[0].Enabled || got[0].Name != "cc" {
+		t.Fatalf("expected rejected settings to leave the feed unchanged, got %+v", got)
+	}
+}
+
+func TestFeedSetAPIKeyReachesDisabledProviders(t *testing.T) {
+	cg := &fakeMarketProvider{name: "cg"}
+	cc := &keyedFakeProvider{fakeMarketProvider: fakeMarketProvider{name: "cc"}}
+	feed := New([]MarketProvider{cg, cc}, []FXProvider{&fakeFXProvider{}}, Callbacks{})
+	if err := feed.SetMarketProviders([]ProviderSetting{{Name: "cg", Enabled: true}, {Name: "cc"}}); err != nil {
+		t.Fatalf("set providers: %v", err)
+	}
+
+	if err := feed.SetAPIKey("cc", " secret "); err != nil {
+		t.Fatalf("set key: %v", err)
+	}
+	if got := cc.apiKey(); got != "secret" {
+		t.Fatalf("expected trimmed key on provider, got %q", got)
+	}
+	if err := feed.SetAPIKey("cg", "secret"); !errors.Is(err, ErrAPIKeyUnsupported) {
+		t.Fatalf("expected ErrAPIKeyUnsupported, got %v", err)
+	}
+	if err := feed.SetAPIKey("missing", "secret"); err == nil {
+		t.Fatal("expected error for unknown provider")
+	}
+}
+
+func TestFeedReportsRejectedAPIKeyOncePerKey(t *testing.T) {
+	cc := &keyedFakeProvider{fakeMarketProvider: fakeMarketProvider{name: "cc"}}
+	var rejected []StatusEvent
+	feed := New([]MarketProvider{cc}, []FXProvider{&fakeFXProvider{}}, Callbacks{
+		OnStatus: func(event StatusEvent) {
+			if event.Code == StatusCodeKeyRejected {
+				rejected = append(rejected, event)
+			}
+		},
+	})
+	authErr := &ProviderError{Provider: "cc", Kind: FailureKindAuth, StatusCode: 401, Err: errors.New("rejected")}
+	now := time.Now()
+
+	feed.recordProviderFailure(now, "cc", authErr, 0)
+	feed.recordProviderFailure(now, "cc", authErr, 0)
+	if len(rejected) != 1 || rejected[0].Provider != "cc" || rejected[0].Kind != StatusKindWarning {
+		t.Fatalf("expected one rejected key warning, got %+v", rejected)
+	}
+	if state := feed.ProviderStates()[0]; state.LastErrorKind != FailureKindAuth {
+		t.Fatalf("expected the auth failure in diagnostics, got %+v", state)
+	}
+
+	if err := feed.SetAPIKey("cc", "new"); err != nil {
+		t.Fatalf("set key: %v", err)
+	}
+	feed.recordProviderFailure(now, "cc", authErr, 0)
+	if len(rejected) != 2 {
+		t.Fatalf("expected a new key to be reported again, got %d warnings", len(rejected))
+	}
+}
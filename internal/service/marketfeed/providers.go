@@ -3,6 +3,7 @@ package marketfeed
 import (
 	"context"
 	"encoding/json"
+	"encoding/xml"
 	"errors"
 	"fmt"
 	"io"
@@ -14,6 +15,8 @@ import (
 	"time"
 
 	"cryptoview/internal/api"
+	"cryptoview/internal/model"
+	"cryptoview/internal/service/coinregistry"
 	"cryptoview/internal/ui/i18n"
 )
 
@@ -23,6 +26,8 @@ const (
 	FailureKindRateLimit FailureKind = "rate_limit"
 	FailureKindNetwork   FailureKind = "network"
 	FailureKindOther     FailureKind = "other"
+	// FailureKindAuth is a provider turning the configured API key away.
+	FailureKindAuth FailureKind = "auth"
 )
 
 type ProviderError struct {
@@ -51,6 +56,7 @@ func (e *ProviderError) Unwrap() error {
 }
 
 type CoinGeckoProvider struct {
+	registryBinding
 	client *api.Client
 }
 
@@ -60,30 +66,21 @@ func NewCoinGeckoProvider(timeout time.Duration) *CoinGeckoProvider {
 
 func (p *CoinGeckoProvider) Name() string { return "coingecko" }
 
-func (p *CoinGeckoProvider) FetchUSD(ctx context.Context) (MarketSnapshot, error) {
-	markets, err := p.client.GetMarkets(ctx, "usd")
+func (p *CoinGeckoProvider) SetAPIKey(key string) {
+	p.client.SetAPIKey(key)
+}
+
+func (p *CoinGeckoProvider) FetchUSD(ctx context.Context, tracked []CoinRef) (MarketSnapshot, error) {
+	index := p.coinIndex(coinregistry.ProviderCoinGecko, tracked)
+	markets, err := p.client.GetMarkets(ctx, "usd", index.requestKeys())
 	if err != nil {
-		var statusErr *api.StatusError
-		if errors.As(err, &statusErr) {
-			kind := FailureKindOther
-			if statusErr.StatusCode == http.StatusTooManyRequests {
-				kind = FailureKindRateLimit
-			}
-			return MarketSnapshot{}, &ProviderError{
-				Provider:   p.Name(),
-				Kind:       kind,
-				StatusCode: statusErr.StatusCode,
-				RetryAfter: statusErr.RetryAfter,
-				Err:        err,
-			}
-		}
-		return MarketSnapshot{}, wrapNetworkError(p.Name(), err)
+		return MarketSnapshot{}, p.wrapError(err)
 	}
 
 	now := time.Now()
 	coins := make(map[string]CoinQuoteUSD, len(markets))
 	for _, m := range markets {
-		id := normalizeCanonicalID(m.ID, strings.ToUpper(m.Symbol))
+		id := index.resolve(m.ID)
 		if id == "" {
 			continue
 		}
@@ -95,18 +92,99 @@ func (p *CoinGeckoProvider) FetchUSD(ctx context.Context) (MarketSnapshot, error
 			lastUpdate = parsed
 		}
 		coins[id] = CoinQuoteUSD{
-			ID:         id,
-			Name:       m.Name,
-			Ticker:     strings.ToUpper(m.Symbol),
-			PriceUSD:   m.CurrentPrice,
-			Change24h:  &change,
-			LastUpdate: lastUpdate,
+			ID:           id,
+			Name:         m.Name,
+			Ticker:       strings.ToUpper(m.Symbol),
+			PriceUSD:     m.CurrentPrice,
+			Change24h:    &change,
+			Volume24hUSD: m.TotalVolume,
+			LastUpdate:   lastUpdate,
 		}
 	}
 	return MarketSnapshot{Provider: p.Name(), FetchedAt: now, Coins: coins}, nil
 }
 
+func (p *CoinGeckoProvider) FetchQuotes(ctx context.Context, tracked []CoinRef, fiats []i18n.FiatCurrency) (MarketSnapshot, error) {
+	fiats = quoteFiats(fiats, func(fiat i18n.FiatCurrency) bool { return api.SupportsFiat(string(fiat)) })
+	index := p.coinIndex(coinregistry.ProviderCoinGecko, tracked)
+	prices, err := p.client.GetSimplePrices(ctx, index.requestKeys(), fiatCodes(fiats))
+	if err != nil {
+		return MarketSnapshot{}, p.wrapError(err)
+	}
+
+	now := time.Now()
+	coins := make(map[string]CoinQuoteUSD, len(prices))
+	for key, fields := range prices {
+		id := index.resolve(key)
+		if id == "" || fields["usd"] <= 0 {
+			continue
+		}
+		change := fields["usd_24h_change"]
+		lastUpdate := now
+		if ts := int64(fields["last_updated_at"]); ts > 0 {
+			lastUpdate = time.Unix(ts, 0)
+		}
+		ref := index.ref(id)
+		coins[id] = CoinQuoteUSD{
+			ID:           id,
+			Name:         ref.Name,
+			Ticker:       ref.Ticker,
+			PriceUSD:     fields["usd"],
+			Change24h:    &change,
+			Volume24hUSD: fields["usd_24h_vol"],
+			LastUpdate:   lastUpdate,
+			Native: nativePrices(fiats, func(fiat i18n.FiatCurrency) float64 {
+				return fields[strings.ToLower(string(fiat))]
+			}),
+		}
+	}
+	return MarketSnapshot{Provider: p.Name(), FetchedAt: now, Coins: coins}, nil
+}
+
+func (p *CoinGeckoProvider) SupportsChartFiat(fiat i18n.FiatCurrency) bool {
+	vs, ok := fiat.APIValue()
+	return ok && api.SupportsFiat(vs)
+}
+
+func (p *CoinGeckoProvider) FetchMarketChart(ctx context.Context, id string, fiat i18n.FiatCurrency, days int) ([]model.PricePoint, error) {
+	vs, ok := fiat.APIValue()
+	if !ok || !api.SupportsFiat(vs) {
+		return nil, &ProviderError{Provider: p.Name(), Kind: FailureKindOther, Err: fmt.Errorf("%w: %s", ErrUnsupportedFiat, fiat)}
+	}
+	keys := p.coinIndex(coinregistry.ProviderCoinGecko, []CoinRef{{ID: id}}).requestKeys()
+	if len(keys) == 0 {
+		return nil, &ProviderError{Provider: p.Name(), Kind: FailureKindOther, Err: fmt.Errorf("unknown coin: %s", id)}
+	}
+	points, err := p.client.GetMarketChart(ctx, keys[0], vs, days)
+	if err != nil {
+		return nil, p.wrapError(err)
+	}
+	return points, nil
+}
+
+func (p *CoinGeckoProvider) wrapError(err error) error {
+	var statusErr *api.StatusError
+	if errors.As(err, &statusErr) {
+		kind := FailureKindOther
+		switch {
+		case statusErr.StatusCode == http.StatusTooManyRequests:
+			kind = FailureKindRateLimit
+		case errors.Is(err, api.ErrAPIKeyRejected):
+			kind = FailureKindAuth
+		}
+		return &ProviderError{
+			Provider:   p.Name(),
+			Kind:       kind,
+			StatusCode: statusErr.StatusCode,
+			RetryAfter: statusErr.RetryAfter,
+			Err:        err,
+		}
+	}
+	return wrapNetworkError(p.Name(), err)
+}
+
 type CoinCapProvider struct {
+	registryBinding
 	httpClient *http.Client
 	baseURL    string
 }
@@ -123,11 +201,10 @@ func NewCoinCapProvider(timeout time.Duration) *CoinCapProvider {
 
 func (p *CoinCapProvider) Name() string { return "coincap" }
 
-func (p *CoinCapProvider) FetchUSD(ctx context.Context) (MarketSnapshot, error) {
+func (p *CoinCapProvider) FetchUSD(ctx context.Context, tracked []CoinRef) (MarketSnapshot, error) {
+	index := p.coinIndex(coinregistry.ProviderCoinCap, tracked)
 	values := url.Values{}
-	values.Set("ids", strings.Join([]string{
-		"bitcoin", "ethereum", "toncoin", "solana", "dogecoin", "xrp", "litecoin",
-	}, ","))
+	values.Set("ids", strings.Join(index.requestKeys(), ","))
 	endpoint := p.baseURL + "/assets?" + values.Encode()
 
 	body, _, err := doJSONRequest(ctx, p.httpClient, p.Name(), endpoint)
@@ -141,6 +218,7 @@ func (p *CoinCapProvider) FetchUSD(ctx context.Context) (MarketSnapshot, error)
 			Name             string `json:"name"`
 			PriceUSD         string `json:"priceUsd"`
 			ChangePercent24h string `json:"changePercent24Hr"`
+			VolumeUSD24h     string `json:"volumeUsd24Hr"`
 		} `json:"data"`
 	}
 	if err := json.Unmarshal(body, &payload); err != nil {
@@ -149,7 +227,7 @@ func (p *CoinCapProvider) FetchUSD(ctx context.Context) (MarketSnapshot, error)
 	now := time.Now()
 	coins := make(map[string]CoinQuoteUSD, len(payload.Data))
 	for _, item := range payload.Data {
-		id := normalizeCanonicalID(item.ID, strings.ToUpper(item.Symbol))
+		id := index.resolve(item.ID)
 		if id == "" {
 			continue
 		}
@@ -164,19 +242,22 @@ func (p *CoinCapProvider) FetchUSD(ctx context.Context) (MarketSnapshot, error)
 				changePtr = &changeCopy
 			}
 		}
+		volume, _ := strconv.ParseFloat(item.VolumeUSD24h, 64)
 		coins[id] = CoinQuoteUSD{
-			ID:         id,
-			Name:       item.Name,
-			Ticker:     strings.ToUpper(item.Symbol),
-			PriceUSD:   price,
-			Change24h:  changePtr,
-			LastUpdate: now,
+			ID:           id,
+			Name:         item.Name,
+			Ticker:       strings.ToUpper(item.Symbol),
+			PriceUSD:     price,
+			Change24h:    changePtr,
+			Volume24hUSD: volume,
+			LastUpdate:   now,
 		}
 	}
 	return MarketSnapshot{Provider: p.Name(), FetchedAt: now, Coins: coins}, nil
 }
 
 type CoinPaprikaProvider struct {
+	registryBinding
 	httpClient *http.Client
 	baseURL    string
 }
@@ -193,8 +274,19 @@ func NewCoinPaprikaProvider(timeout time.Duration) *CoinPaprikaProvider {
 
 func (p *CoinPaprikaProvider) Name() string { return "coinpaprika" }
 
-func (p *CoinPaprikaProvider) FetchUSD(ctx context.Context) (MarketSnapshot, error) {
-	endpoint := p.baseURL + "/tickers?quotes=USD"
+var paprikaQuoteFiats = map[i18n.FiatCurrency]bool{
+	i18n.FiatEUR: true, i18n.FiatRUB: true, i18n.FiatGBP: true, i18n.FiatJPY: true, i18n.FiatCHF: true,
+	i18n.FiatCNY: true, i18n.FiatTRY: true, i18n.FiatUAH: true, i18n.FiatPLN: true, i18n.FiatCAD: true,
+	i18n.FiatAUD: true, i18n.FiatINR: true, i18n.FiatBRL: true, i18n.FiatKRW: true,
+}
+
+func (p *CoinPaprikaProvider) FetchUSD(ctx context.Context, tracked []CoinRef) (MarketSnapshot, error) {
+	return p.FetchQuotes(ctx, tracked, nil)
+}
+
+func (p *CoinPaprikaProvider) FetchQuotes(ctx context.Context, tracked []CoinRef, fiats []i18n.FiatCurrency) (MarketSnapshot, error) {
+	fiats = quoteFiats(fiats, func(fiat i18n.FiatCurrency) bool { return paprikaQuoteFiats[fiat] })
+	endpoint := p.baseURL + "/tickers?quotes=" + strings.Join(fiatCodes(fiats), ",")
 	body, _, err := doJSONRequest(ctx, p.httpClient, p.Name(), endpoint)
 	if err != nil {
 		return MarketSnapshot{}, err
@@ -205,11 +297,10 @@ func (p *CoinPaprikaProvider) FetchUSD(ctx context.Context) (MarketSnapshot, err
 		Symbol      string `json:"symbol"`
 		Name        string `json:"name"`
 		LastUpdated string `json:"last_updated"`
-		Quotes      struct {
-			USD struct {
-				Price           float64 `json:"price"`
-				PercentChange24 float64 `json:"percent_change_24h"`
-			} `json:"USD"`
+		Quotes      map[string]struct {
+			Price           float64 `json:"price"`
+			PercentChange24 float64 `json:"percent_change_24h"`
+			Volume24h       float64 `json:"volume_24h"`
 		} `json:"quotes"`
 	}
 	if err := json.Unmarshal(body, &payload); err != nil {
@@ -217,13 +308,18 @@ func (p *CoinPaprikaProvider) FetchUSD(ctx context.Context) (MarketSnapshot, err
 	}
 
 	now := time.Now()
-	coins := make(map[string]CoinQuoteUSD, len(trackedOrder))
+	index := p.coinIndex(coinregistry.ProviderCoinPaprika, tracked)
+	coins := make(map[string]CoinQuoteUSD, index.size())
 	for _, item := range payload {
-		id := normalizeCanonicalID(item.ID, strings.ToUpper(item.Symbol))
+		id := index.resolve(item.ID)
 		if id == "" {
 			continue
 		}
-		if item.Quotes.USD.Price <= 0 {
+		if _, dup := coins[id]; dup {
+			continue
+		}
+		usd := item.Quotes[string(i18n.FiatUSD)]
+		if usd.Price <= 0 {
 			continue
 		}
 		lastUpdate := now
@@ -232,16 +328,21 @@ func (p *CoinPaprikaProvider) FetchUSD(ctx context.Context) (MarketSnapshot, err
 		} else if ts, err := time.Parse(time.RFC3339, item.LastUpdated); err == nil {
 			lastUpdate = ts
 		}
-		change := item.Quotes.USD.PercentChange24
+		change := usd.PercentChange24
+		quotes := item.Quotes
 		coins[id] = CoinQuoteUSD{
-			ID:         id,
-			Name:       item.Name,
-			Ticker:     strings.ToUpper(item.Symbol),
-			PriceUSD:   item.Quotes.USD.Price,
-			Change24h:  &change,
-			LastUpdate: lastUpdate,
-		}
-		if len(coins) == len(trackedOrder) {
+			ID:           id,
+			Name:         item.Name,
+			Ticker:       strings.ToUpper(item.Symbol),
+			PriceUSD:     usd.Price,
+			Change24h:    &change,
+			Volume24hUSD: usd.Volume24h,
+			LastUpdate:   lastUpdate,
+			Native: nativePrices(fiats, func(fiat i18n.FiatCurrency) float64 {
+				return quotes[string(fiat)].Price
+			}),
+		}
+		if len(coins) == index.size() {
 			break
 		}
 	}
@@ -249,6 +350,8 @@ func (p *CoinPaprikaProvider) FetchUSD(ctx context.Context) (MarketSnapshot, err
 }
 
 type CryptoCompareProvider struct {
+	registryBinding
+	apiKeyBinding
 	httpClient *http.Client
 	baseURL    string
 }
@@ -265,13 +368,23 @@ func NewCryptoCompareProvider(timeout time.Duration) *CryptoCompareProvider {
 
 func (p *CryptoCompareProvider) Name() string { return "cryptocompare" }
 
-func (p *CryptoCompareProvider) FetchUSD(ctx context.Context) (MarketSnapshot, error) {
+func (p *CryptoCompareProvider) FetchUSD(ctx context.Context, tracked []CoinRef) (MarketSnapshot, error) {
+	return p.FetchQuotes(ctx, tracked, nil)
+}
+
+func (p *CryptoCompareProvider) FetchQuotes(ctx context.Context, tracked []CoinRef, fiats []i18n.FiatCurrency) (MarketSnapshot, error) {
+	fiats = quoteFiats(fiats, func(i18n.FiatCurrency) bool { return true })
+	index := p.coinIndex(coinregistry.ProviderCryptoCompare, tracked)
 	values := url.Values{}
-	values.Set("fsyms", "BTC,ETH,TON,SOL,DOGE,XRP,LTC")
-	values.Set("tsyms", "USD")
+	values.Set("fsyms", strings.Join(index.requestKeys(), ","))
+	values.Set("tsyms", strings.Join(fiatCodes(fiats), ","))
 	endpoint := p.baseURL + "?" + values.Encode()
 
-	body, _, err := doJSONRequest(ctx, p.httpClient, p.Name(), endpoint)
+	var header http.Header
+	if key := p.apiKey(); key != "" {
+		header = http.Header{"Authorization": {"Apikey " + key}}
+	}
+	body, _, err := doRequestWithHeader(ctx, p.httpClient, p.Name(), endpoint, "application/json", header)
 	if err != nil {
 		return MarketSnapshot{}, err
 	}
@@ -280,6 +393,7 @@ func (p *CryptoCompareProvider) FetchUSD(ctx context.Context) (MarketSnapshot, e
 		RAW map[string]map[string]struct {
 			Price          float64 `json:"PRICE"`
 			ChangePct24h   float64 `json:"CHANGEPCT24HOUR"`
+			Volume24hTo    float64 `json:"VOLUME24HOURTO"`
 			LastUpdateUnix int64   `json:"LASTUPDATE"`
 		} `json:"RAW"`
 	}
@@ -294,7 +408,7 @@ func (p *CryptoCompareProvider) FetchUSD(ctx context.Context) (MarketSnapshot, e
 		if !ok || usd.Price <= 0 {
 			continue
 		}
-		id := normalizeCanonicalID("", symbol)
+		id := index.resolve(symbol)
 		if id == "" {
 			continue
 		}
@@ -303,13 +417,18 @@ func (p *CryptoCompareProvider) FetchUSD(ctx context.Context) (MarketSnapshot, e
 		if usd.LastUpdateUnix > 0 {
 			lastUpdate = time.Unix(usd.LastUpdateUnix, 0)
 		}
+		quotes := byFiat
 		coins[id] = CoinQuoteUSD{
-			ID:         id,
-			Name:       defaultCoinNames[id],
-			Ticker:     strings.ToUpper(symbol),
-			PriceUSD:   usd.Price,
-			Change24h:  &change,
-			LastUpdate: lastUpdate,
+			ID:           id,
+			Name:         index.ref(id).Name,
+			Ticker:       strings.ToUpper(symbol),
+			PriceUSD:     usd.Price,
+			Change24h:    &change,
+			Volume24hUSD: usd.Volume24hTo,
+			LastUpdate:   lastUpdate,
+			Native: nativePrices(fiats, func(fiat i18n.FiatCurrency) float64 {
+				return quotes[string(fiat)].Price
+			}),
 		}
 	}
 
@@ -317,6 +436,7 @@ func (p *CryptoCompareProvider) FetchUSD(ctx context.Context) (MarketSnapshot, e
 }
 
 type BinanceProvider struct {
+	registryBinding
 	httpClient *http.Client
 	baseURL    string
 }
@@ -333,10 +453,14 @@ func NewBinanceProvider(timeout time.Duration) *BinanceProvider {
 
 func (p *BinanceProvider) Name() string { return "binance" }
 
-func (p *BinanceProvider) FetchUSD(ctx context.Context) (MarketSnapshot, error) {
-	symbols := `["BTCUSDT","ETHUSDT","TONUSDT","SOLUSDT","DOGEUSDT","XRPUSDT","LTCUSDT"]`
+func (p *BinanceProvider) FetchUSD(ctx context.Context, tracked []CoinRef) (MarketSnapshot, error) {
+	index := p.coinIndex(coinregistry.ProviderBinance, tracked)
+	symbols, err := json.Marshal(index.requestKeys())
+	if err != nil {
+		return MarketSnapshot{}, &ProviderError{Provider: p.Name(), Kind: FailureKindOther, Err: err}
+	}
 	values := url.Values{}
-	values.Set("symbols", symbols)
+	values.Set("symbols", string(symbols))
 	endpoint := p.baseURL + "?" + values.Encode()
 
 	body, _, err := doJSONRequest(ctx, p.httpClient, p.Name(), endpoint)
@@ -348,6 +472,7 @@ func (p *BinanceProvider) FetchUSD(ctx context.Context) (MarketSnapshot, error)
 		Symbol             string `json:"symbol"`
 		LastPrice          string `json:"lastPrice"`
 		PriceChangePercent string `json:"priceChangePercent"`
+		QuoteVolume        string `json:"quoteVolume"`
 		CloseTime          int64  `json:"closeTime"`
 	}
 	if err := json.Unmarshal(body, &payload); err != nil {
@@ -357,7 +482,7 @@ func (p *BinanceProvider) FetchUSD(ctx context.Context) (MarketSnapshot, error)
 	now := time.Now()
 	coins := make(map[string]CoinQuoteUSD, len(payload))
 	for _, item := range payload {
-		id := normalizeCanonicalID("", strings.TrimSuffix(strings.ToUpper(item.Symbol), "USDT"))
+		id := index.resolve(item.Symbol)
 		if id == "" {
 			continue
 		}
@@ -376,13 +501,15 @@ func (p *BinanceProvider) FetchUSD(ctx context.Context) (MarketSnapshot, error)
 		if item.CloseTime > 0 {
 			lastUpdate = time.UnixMilli(item.CloseTime)
 		}
+		volume, _ := strconv.ParseFloat(item.QuoteVolume, 64)
 		coins[id] = CoinQuoteUSD{
-			ID:         id,
-			Name:       defaultCoinNames[id],
-			Ticker:     defaultTickers[id],
-			PriceUSD:   price,
-			Change24h:  changePtr,
-			LastUpdate: lastUpdate,
+			ID:           id,
+			Name:         index.ref(id).Name,
+			Ticker:       index.ref(id).Ticker,
+			PriceUSD:     price,
+			Change24h:    changePtr,
+			Volume24hUSD: volume,
+			LastUpdate:   lastUpdate,
 		}
 	}
 
@@ -390,6 +517,7 @@ func (p *BinanceProvider) FetchUSD(ctx context.Context) (MarketSnapshot, error)
 }
 
 type CoinLoreProvider struct {
+	registryBinding
 	httpClient *http.Client
 	baseURL    string
 }
@@ -400,16 +528,20 @@ func NewCoinLoreProvider(timeout time.Duration) *CoinLoreProvider {
 	}
 	return &CoinLoreProvider{
 		httpClient: &http.Client{Timeout: timeout},
-		baseURL:    "https://api.coinlore.net/api/tickers/",
+		baseURL:    "https://api.coinlore.net/api/ticker/",
 	}
 }
 
 func (p *CoinLoreProvider) Name() string { return "coinlore" }
 
-func (p *CoinLoreProvider) FetchUSD(ctx context.Context) (MarketSnapshot, error) {
+func (p *CoinLoreProvider) FetchUSD(ctx context.Context, tracked []CoinRef) (MarketSnapshot, error) {
+	index := p.coinIndex(coinregistry.ProviderCoinLore, tracked)
+	ids := index.requestKeys()
+	if len(ids) == 0 {
+		return MarketSnapshot{}, &ProviderError{Provider: p.Name(), Kind: FailureKindOther, Err: errors.New("no watchlist coin has a coinlore id")}
+	}
 	values := url.Values{}
-	values.Set("start", "0")
-	values.Set("limit", "100")
+	values.Set("id", strings.Join(ids, ","))
 	endpoint := p.baseURL + "?" + values.Encode()
 
 	body, _, err := doJSONRequest(ctx, p.httpClient, p.Name(), endpoint)
@@ -417,22 +549,22 @@ func (p *CoinLoreProvider) FetchUSD(ctx context.Context) (MarketSnapshot, error)
 		return MarketSnapshot{}, err
 	}
 
-	var payload struct {
-		Data []struct {
-			Symbol          string `json:"symbol"`
-			Name            string `json:"name"`
-			PriceUSD        string `json:"price_usd"`
-			PercentChange24 string `json:"percent_change_24h"`
-		} `json:"data"`
+	var payload []struct {
+		ID              string  `json:"id"`
+		Symbol          string  `json:"symbol"`
+		Name            string  `json:"name"`
+		PriceUSD        string  `json:"price_usd"`
+		PercentChange24 string  `json:"percent_change_24h"`
+		Volume24        float64 `json:"volume24"`
 	}
 	if err := json.Unmarshal(body, &payload); err != nil {
 		return MarketSnapshot{}, &ProviderError{Provider: p.Name(), Kind: FailureKindOther, Err: err}
 	}
 
 	now := time.Now()
-	coins := make(map[string]CoinQuoteUSD, len(payload.Data))
-	for _, item := range payload.Data {
-		id := normalizeCanonicalID("", item.Symbol)
+	coins := make(map[string]CoinQuoteUSD, len(payload))
+	for _, item := range payload {
+		id := index.resolve(item.ID)
 		if id == "" {
 			continue
 		}
@@ -448,15 +580,13 @@ func (p *CoinLoreProvider) FetchUSD(ctx context.Context) (MarketSnapshot, error)
 			}
 		}
 		coins[id] = CoinQuoteUSD{
-			ID:         id,
-			Name:       chooseString(item.Name, defaultCoinNames[id]),
-			Ticker:     chooseString(defaultTickers[id], strings.ToUpper(item.Symbol)),
-			PriceUSD:   price,
-			Change24h:  changePtr,
-			LastUpdate: now,
-		}
-		if len(coins) == len(trackedOrder) {
-			break
+			ID:           id,
+			Name:         chooseString(item.Name, index.ref(id).Name),
+			Ticker:       chooseString(index.ref(id).Ticker, strings.ToUpper(item.Symbol)),
+			PriceUSD:     price,
+			Change24h:    changePtr,
+			Volume24hUSD: item.Volume24,
+			LastUpdate:   now,
 		}
 	}
 
@@ -506,21 +636,100 @@ func (p *OpenExchangeRatesProvider) FetchRates(ctx context.Context) (FXSnapshot,
 	if payload.Time > 0 {
 		snapshot.FetchedAt = time.Unix(payload.Time, 0)
 	}
-	if v := payload.Rates["EUR"]; v > 0 {
-		snapshot.Rates[i18n.FiatEUR] = v
+	for code, v := range payload.Rates {
+		if v > 0 {
+			snapshot.Rates[i18n.FiatCurrency(strings.ToUpper(code))] = v
+		}
+	}
+	return snapshot, nil
+}
+
+type ECBProvider struct {
+	httpClient *http.Client
+	baseURL    string
+}
+
+func NewECBProvider(timeout time.Duration) *ECBProvider {
+	if timeout <= 0 {
+		timeout = 10 * time.Second
+	}
+	return &ECBProvider{
+		httpClient: &http.Client{Timeout: timeout},
+		baseURL:    "https://www.ecb.europa.eu/stats/eurofxref/eurofxref-daily.xml",
+	}
+}
+
+func (p *ECBProvider) Name() string { return "ecb" }
+
+// ECB reference rates are quoted against EUR and published around 16:00 CET;
+// they are rebased onto USD so the snapshot matches the other providers.
+func (p *ECBProvider) FetchRates(ctx context.Context) (FXSnapshot, error) {
+	body, _, err := doRequest(ctx, p.httpClient, p.Name(), p.baseURL, "application/xml")
+	if err != nil {
+		return FXSnapshot{}, err
+	}
+	var payload struct {
+		Days []struct {
+			Time  string `xml:"time,attr"`
+			Rates []struct {
+				Currency string  `xml:"currency,attr"`
+				Rate     float64 `xml:"rate,attr"`
+			} `xml:"Cube"`
+		} `xml:"Cube>Cube"`
+	}
+	if err := xml.Unmarshal(body, &payload); err != nil {
+		return FXSnapshot{}, &ProviderError{Provider: p.Name(), Kind: FailureKindOther, Err: err}
 	}
-	if v := payload.Rates["RUB"]; v > 0 {
-		snapshot.Rates[i18n.FiatRUB] = v
+	if len(payload.Days) == 0 {
+		return FXSnapshot{}, &ProviderError{Provider: p.Name(), Kind: FailureKindOther, Err: fmt.Errorf("empty fx rates")}
 	}
+	day := payload.Days[0]
+	perEUR := make(map[i18n.FiatCurrency]float64, len(day.Rates)+1)
+	perEUR[i18n.FiatEUR] = 1
+	for _, rate := range day.Rates {
+		if rate.Rate > 0 {
+			perEUR[i18n.FiatCurrency(strings.ToUpper(rate.Currency))] = rate.Rate
+		}
+	}
+	usd := perEUR[i18n.FiatUSD]
+	if usd <= 0 {
+		return FXSnapshot{}, &ProviderError{Provider: p.Name(), Kind: FailureKindOther, Err: fmt.Errorf("missing USD reference rate")}
+	}
+
+	snapshot := FXSnapshot{
+		Base:      "USD",
+		FetchedAt: time.Now(),
+		Rates:     make(map[i18n.FiatCurrency]float64, len(perEUR)),
+	}
+	if published, err := time.ParseInLocation("2006-01-02", day.Time, ecbLocation); err == nil {
+		snapshot.FetchedAt = published.Add(16 * time.Hour)
+	}
+	for code, rate := range perEUR {
+		snapshot.Rates[code] = rate / usd
+	}
+	snapshot.Rates[i18n.FiatUSD] = 1
 	return snapshot, nil
 }
 
+var ecbLocation = time.FixedZone("CET", 60*60)
+
 func doJSONRequest(ctx context.Context, client *http.Client, providerName, endpoint string) ([]byte, http.Header, error) {
+	return doRequest(ctx, client, providerName, endpoint, "application/json")
+}
+
+func doRequest(ctx context.Context, client *http.Client, providerName, endpoint, accept string) ([]byte, http.Header, error) {
+	return doRequestWithHeader(ctx, client, providerName, endpoint, accept, nil)
+}
+
+func doRequestWithHeader(ctx context.Context, client *http.Client, providerName, endpoint, accept string, header http.Header) ([]byte, http.Header, error) {
 	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
 	if err != nil {
 		return nil, nil, &ProviderError{Provider: providerName, Kind: FailureKindOther, Err: err}
 	}
-	req.Header.Set("Accept", "application/json")
+	for name, values := range header {
+		req.Header[name] = values
+	}
+	req.Header.Set("Accept", accept)
 	req.Header.Set("User-Agent", "CryptoView/1.0")
 
 	resp, err := client.Do(req)
@@ -573,41 +782,3 @@ func parseRetryAfter(raw string) time.Duration {
 	}
 	return 0
 }
-
-func normalizeCanonicalID(providerID, symbol string) string {
-	switch strings.ToLower(strings.TrimSpace(providerID)) {
-	case "bitcoin", "btc-bitcoin":
-		return "bitcoin"
-	case "ethereum", "eth-ethereum":
-		return "ethereum"
-	case "the-open-network", "toncoin", "ton-toncoin", "toncoin-toncoin":
-		return "the-open-network"
-	case "solana", "sol-solana":
-		return "solana"
-	case "dogecoin", "doge-dogecoin":
-		return "dogecoin"
-	case "ripple", "xrp", "xrp-xrp":
-		return "ripple"
-	case "litecoin", "ltc-litecoin":
-		return "litecoin"
-	}
-
-	switch strings.ToUpper(strings.TrimSpace(symbol)) {
-	case "BTC":
-		return "bitcoin"
-	case "ETH":
-		return "ethereum"
-	case "TON":
-		return "the-open-network"
-	case "SOL":
-		return "solana"
-	case "DOGE":
-		return "dogecoin"
-	case "XRP":
-		return "ripple"
-	case "LTC":
-		return "litecoin"
-	}
-
-	return ""
-}
@@ -1,8 +1,13 @@
 package marketfeed
 
 import (
+	"context"
 	"errors"
+	"net/http"
+	"net/http/httptest"
 	"testing"
+
+	"cryptoview/internal/ui/i18n"
 )
 
 func TestProviderError_Error(t *testing.T) {
@@ -52,3 +57,210 @@ func TestErrorsAs_ProviderError(t *testing.T) {
 		t.Fatalf("expected target to match, got %+v", target)
 	}
 }
+
+func TestCryptoCompareProviderBuildsRequestFromWatchlist(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if got := r.URL.Query().Get("fsyms"); got != "ADA,AVAX" {
+			t.Fatalf("unexpected fsyms: %s", got)
+		}
+		_, _ = w.Write([]byte(`{"RAW":{"ADA":{"USD":{"PRICE":0.5,"CHANGEPCT24HOUR":1.5}},"AVAX":{"USD":{"PRICE":30}},"BTC":{"USD":{"PRICE":1}}}}`))
+	}))
+	defer srv.Close()
+
+	p := &CryptoCompareProvider{httpClient: srv.Client(), baseURL: srv.URL}
+	snapshot, err := p.FetchUSD(context.Background(), []CoinRef{
+		{ID: "cardano", Name: "Cardano", Ticker: "ADA"},
+		{ID: "avalanche-2", Name: "Avalanche", Ticker: "AVAX"},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(snapshot.Coins) != 2 {
+		t.Fatalf("expected only watched coins, got %+v", snapshot.Coins)
+	}
+	if got := snapshot.Coins["avalanche-2"]; got.Name != "Avalanche" || got.PriceUSD != 30 {
+		t.Fatalf("unexpected AVAX quote: %+v", got)
+	}
+}
+
+func TestCryptoCompareProviderFetchesNativeQuotes(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if got := r.URL.Query().Get("tsyms"); got != "USD,EUR" {
+			t.Fatalf("unexpected tsyms: %s", got)
+		}
+		_, _ = w.Write([]byte(`{"RAW":{"ADA":{"USD":{"PRICE":0.5},"EUR":{"PRICE":0.46}}}}`))
+	}))
+	defer srv.Close()
+
+	p := &CryptoCompareProvider{httpClient: srv.Client(), baseURL: srv.URL}
+	snapshot, err := p.FetchQuotes(context.Background(), []CoinRef{{ID: "cardano", Name: "Cardano", Ticker: "ADA"}}, []i18n.FiatCurrency{i18n.FiatUSD, i18n.FiatEUR})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	quote := snapshot.Coins["cardano"]
+	if quote.PriceUSD != 0.5 || quote.Native[i18n.FiatEUR] != 0.46 || len(quote.Native) != 1 {
+		t.Fatalf("unexpected native quote: %+v", quote)
+	}
+}
+
+func TestCryptoCompareProviderSendsAPIKey(t *testing.T) {
+	var auth []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		auth = append(auth, r.Header.Get("Authorization"))
+		_, _ = w.Write([]byte(`{"RAW":{"ADA":{"USD":{"PRICE":0.5}}}}`))
+	}))
+	defer srv.Close()
+
+	p := &CryptoCompareProvider{httpClient: srv.Client(), baseURL: srv.URL}
+	coins := []CoinRef{{ID: "cardano", Name: "Cardano", Ticker: "ADA"}}
+	p.SetAPIKey("cc-key")
+	if _, err := p.FetchUSD(context.Background(), coins); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	p.SetAPIKey("")
+	if _, err := p.FetchUSD(context.Background(), coins); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(auth) != 2 || auth[0] != "Apikey cc-key" || auth[1] != "" {
+		t.Fatalf("unexpected Authorization headers %q", auth)
+	}
+}
+
+func TestCoinLoreProviderRequestsWatchlistIDs(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if got := r.URL.Query().Get("id"); got != "45219,90" {
+			t.Fatalf("unexpected id: %s", got)
+		}
+		_, _ = w.Write([]byte(`[{"id":"45219","symbol":"DOT","name":"Polkadot","price_usd":"7.10","percent_change_24h":"-1.2","volume24":1500},{"id":"90","symbol":"BTC","name":"Bitcoin","price_usd":"65000.5"}]`))
+	}))
+	defer srv.Close()
+
+	p := &CoinLoreProvider{httpClient: srv.Client(), baseURL: srv.URL}
+	snapshot, err := p.FetchUSD(context.Background(), []CoinRef{
+		{ID: "polkadot", Name: "Polkadot", Ticker: "DOT"},
+		{ID: "bitcoin", Name: "Bitcoin", Ticker: "BTC"},
+		{ID: "unlisted", Name: "Unlisted", Ticker: "UNL"},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(snapshot.Coins) != 2 {
+		t.Fatalf("expected both registry coins, got %+v", snapshot.Coins)
+	}
+	if got := snapshot.Coins["polkadot"]; got.PriceUSD != 7.10 || got.Change24h == nil || *got.Change24h != -1.2 {
+		t.Fatalf("unexpected DOT quote: %+v", got)
+	}
+}
+
+func TestCoinPaprikaProviderFetchesNativeQuotes(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if got := r.URL.Query().Get("quotes"); got != "USD,GBP" {
+			t.Fatalf("unexpected quotes: %s", got)
+		}
+		_, _ = w.Write([]byte(`[{"id":"btc-bitcoin","symbol":"BTC","name":"Bitcoin","quotes":{"USD":{"price":100},"GBP":{"price":79}}}]`))
+	}))
+	defer srv.Close()
+
+	p := &CoinPaprikaProvider{httpClient: srv.Client(), baseURL: srv.URL}
+	snapshot, err := p.FetchQuotes(context.Background(), []CoinRef{{ID: "bitcoin"}}, []i18n.FiatCurrency{i18n.FiatGBP, i18n.FiatKZT})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if quote := snapshot.Coins["bitcoin"]; quote.PriceUSD != 100 || quote.Native[i18n.FiatGBP] != 79 {
+		t.Fatalf("unexpected native quote: %+v", snapshot.Coins)
+	}
+}
+
+func TestOpenExchangeRatesProviderKeepsAllRates(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"result":"success","time_last_update_unix":1700000000,"rates":{"USD":1,"EUR":0.9,"GBP":0.8,"KZT":450,"jpy":150,"XXX":0}}`))
+	}))
+	defer srv.Close()
+
+	p := &OpenExchangeRatesProvider{httpClient: srv.Client(), baseURL: srv.URL}
+	snapshot, err := p.FetchRates(context.Background())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := map[i18n.FiatCurrency]float64{i18n.FiatUSD: 1, i18n.FiatEUR: 0.9, i18n.FiatGBP: 0.8, i18n.FiatKZT: 450, i18n.FiatJPY: 150}
+	if len(snapshot.Rates) != len(want) {
+		t.Fatalf("unexpected rates %+v", snapshot.Rates)
+	}
+	for code, rate := range want {
+		if snapshot.Rates[code] != rate {
+			t.Fatalf("rate %s = %v, want %v", code, snapshot.Rates[code], rate)
+		}
+	}
+}
+
+func TestBinanceProviderBuildsPairsFromWatchlist(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if got := r.URL.Query().Get("symbols"); got != `["ADAUSDT","DOTUSDT"]` {
+			t.Fatalf("unexpected symbols: %s", got)
+		}
+		_, _ = w.Write([]byte(`[{"symbol":"DOTUSDT","lastPrice":"7.25","priceChangePercent":"-2.1","closeTime":1700000000000}]`))
+	}))
+	defer srv.Close()
+
+	p := &BinanceProvider{httpClient: srv.Client(), baseURL: srv.URL}
+	snapshot, err := p.FetchUSD(context.Background(), []CoinRef{
+		{ID: "cardano", Name: "Cardano", Ticker: "ADA"},
+		{ID: "polkadot", Name: "Polkadot", Ticker: "DOT"},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	quote, ok := snapshot.Coins["polkadot"]
+	if !ok || quote.PriceUSD != 7.25 || quote.Ticker != "DOT" {
+		t.Fatalf("unexpected DOT quote: %+v", snapshot.Coins)
+	}
+}
+
+func TestCoinPaprikaProviderResolvesThroughRegistry(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`[
+			{"id":"btc-bitcoin","symbol":"BTC","name":"Bitcoin","quotes":{"USD":{"price":100}}},
+			{"id":"ton-toncoin","symbol":"TON","name":"Toncoin","quotes":{"USD":{"price":5}}},
+			{"id":"ada-cardano","symbol":"ADA","name":"Cardano","quotes":{"USD":{"price":0.4}}}
+		]`))
+	}))
+	defer srv.Close()
+
+	p := &CoinPaprikaProvider{httpClient: srv.Client(), baseURL: srv.URL}
+	snapshot, err := p.FetchUSD(context.Background(), []CoinRef{
+		{ID: "the-open-network", Ticker: "TON"},
+		{ID: "cardano", Ticker: "ADA"},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(snapshot.Coins) != 2 {
+		t.Fatalf("expected watched coins only, got %+v", snapshot.Coins)
+	}
+	if snapshot.Coins["the-open-network"].PriceUSD != 5 || snapshot.Coins["cardano"].PriceUSD != 0.4 {
+		t.Fatalf("unexpected quotes: %+v", snapshot.Coins)
+	}
+}
+
+func TestCryptoCompareProviderSkipsAmbiguousTickers(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"RAW":{"TON":{"USD":{"PRICE":5}},"BTC":{"USD":{"PRICE":100}}}}`))
+	}))
+	defer srv.Close()
+
+	p := &CryptoCompareProvider{httpClient: srv.Client(), baseURL: srv.URL}
+	snapshot, err := p.FetchUSD(context.Background(), []CoinRef{
+		{ID: "bitcoin", Ticker: "BTC"},
+		{ID: "the-open-network", Ticker: "TON"},
+		{ID: "tokamak-network", Name: "Tokamak", Ticker: "TON"},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, ok := snapshot.Coins["bitcoin"]; !ok {
+		t.Fatal("expected unambiguous coin to resolve")
+	}
+	if len(snapshot.Coins) != 1 {
+		t.Fatalf("expected colliding TON quote to be skipped, got %+v", snapshot.Coins)
+	}
+}
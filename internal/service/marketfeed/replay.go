@@ -0,0 +1,330 @@
+package marketfeed
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+
+	"cryptoview/internal/model"
+	"cryptoview/internal/service/coinregistry"
+	"cryptoview/internal/ui/i18n"
+)
+
+const (
+	recordKindMarket = "market"
+	recordKindFX     = "fx"
+)
+
+// recordEntry is one line of a recording: the snapshot a provider returned
+// and the wall-clock time it arrived.
+type recordEntry struct {
+	At       time.Time       `json:"at"`
+	Kind     string          `json:"kind"`
+	Provider string          `json:"provider"`
+	Market   *MarketSnapshot `json:"market,omitempty"`
+	FX       *FXSnapshot     `json:"fx,omitempty"`
+}
+
+type Recorder struct {
+	mu   sync.Mutex
+	path string
+	file *os.File
+	enc  *json.Encoder
+}
+
+func NewRecorder(path string) (*Recorder, error) {
+	if dir := filepath.Dir(path); dir != "." {
+		if err := os.MkdirAll(dir, 0o755); err != nil {
+			return nil, fmt.Errorf("recorder: mkdir: %w", err)
+		}
+	}
+	file, err := os.Create(path)
+	if err != nil {
+		return nil, fmt.Errorf("recorder: create: %w", err)
+	}
+	return &Recorder{path: path, file: file, enc: json.NewEncoder(file)}, nil
+}
+
+func (r *Recorder) Path() string {
+	return r.path
+}
+
+func (r *Recorder) Close() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.file == nil {
+		return nil
+	}
+	err := r.file.Close()
+	r.file = nil
+	return err
+}
+
+func (r *Recorder) write(entry recordEntry) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.file == nil {
+		return
+	}
+	if err := r.enc.Encode(entry); err != nil {
+		log.Printf("marketfeed: record failed path=%s kind=%s err=%v", r.path, entry.Kind, err)
+	}
+}
+
+// RecordingProvider passes every call through to the wrapped provider and
+// writes successful snapshots to a Recorder. Name is the wrapped provider's,
+// so budgets and poll policies keep applying.
+type RecordingProvider struct {
+	inner    MarketProvider
+	recorder *Recorder
+}
+
+func NewRecordingProvider(inner MarketProvider, recorder *Recorder) *RecordingProvider {
+	return &RecordingProvider{inner: inner, recorder: recorder}
+}
+
+func (p *RecordingProvider) Name() string {
+	return p.inner.Name()
+}
+
+func (p *RecordingProvider) FetchUSD(ctx context.Context, coins []CoinRef) (MarketSnapshot, error) {
+	snapshot, err := p.inner.FetchUSD(ctx, coins)
+	return p.record(snapshot, err)
+}
+
+func (p *RecordingProvider) FetchQuotes(ctx context.Context, coins []CoinRef, fiats []i18n.FiatCurrency) (MarketSnapshot, error) {
+	native, ok := p.inner.(NativeQuoteProvider)
+	if !ok {
+		return p.FetchUSD(ctx, coins)
+	}
+	snapshot, err := native.FetchQuotes(ctx, coins, fiats)
+	return p.record(snapshot, err)
+}
+
+func (p *RecordingProvider) FetchMarketChart(ctx context.Context, id string, fiat i18n.FiatCurrency, days int) ([]model.PricePoint, error) {
+	charts, ok := p.inner.(ChartProvider)
+	if !ok {
+		return nil, ErrChartUnavailable
+	}
+	return charts.FetchMarketChart(ctx, id, fiat, days)
+}
+
+func (p *RecordingProvider) SupportsChartFiat(fiat i18n.FiatCurrency) bool {
+	if checker, ok := p.inner.(ChartFiatChecker); ok {
+		return checker.SupportsChartFiat(fiat)
+	}
+	return true
+}
+
+func (p *RecordingProvider) SetRegistry(registry *coinregistry.Registry) {
+	if binder, ok := p.inner.(interface {
+		SetRegistry(*coinregistry.Registry)
+	}); ok {
+		binder.SetRegistry(registry)
+	}
+}
+
+func (p *RecordingProvider) record(snapshot MarketSnapshot, err error) (MarketSnapshot, error) {
+	if err == nil {
+		p.recorder.write(recordEntry{At: time.Now(), Kind: recordKindMarket, Provider: p.Name(), Market: &snapshot})
+	}
+	return snapshot, err
+}
+
+type RecordingFXProvider struct {
+	inner    FXProvider
+	recorder *Recorder
+}
+
+func NewRecordingFXProvider(inner FXProvider, recorder *Recorder) *RecordingFXProvider {
+	return &RecordingFXProvider{inner: inner, recorder: recorder}
+}
+
+func (p *RecordingFXProvider) Name() string {
+	return p.inner.Name()
+}
+
+func (p *RecordingFXProvider) FetchRates(ctx context.Context) (FXSnapshot, error) {
+	snapshot, err := p.inner.FetchRates(ctx)
+	if err == nil {
+		p.recorder.write(recordEntry{At: time.Now(), Kind: recordKindFX, Provider: p.Name(), FX: &snapshot})
+	}
+	return snapshot, err
+}
+
+// ReplayProvider serves a recording as both the market and the FX provider.
+// The timeline starts on the first fetch, runs speed times faster than the
+// wall clock and loops once the last record has been played. Timestamps are
+// shifted so replayed snapshots look fresh.
+type ReplayProvider struct {
+	mu     sync.Mutex
+	path   string
+	speed  float64
+	market []recordEntry
+	fx     []recordEntry
+	origin time.Time
+	period time.Duration
+	start  time.Time
+	now    func() time.Time
+}
+
+func NewReplayProvider(path string, speed float64) (*ReplayProvider, error) {
+	if speed <= 0 {
+		return nil, fmt.Errorf("replay: speed must be positive, got %g", speed)
+	}
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("replay: open: %w", err)
+	}
+	defer file.Close()
+
+	p := &ReplayProvider{path: path, speed: speed, now: time.Now}
+	var first, last time.Time
+	count := 0
+	dec := json.NewDecoder(file)
+	for {
+		var entry recordEntry
+		err := dec.Decode(&entry)
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if errors.Is(err, io.ErrUnexpectedEOF) && count > 0 {
+			// A recording interrupted mid-write ends with a partial line.
+			log.Printf("marketfeed: replay ignoring truncated record path=%s", path)
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("replay: decode record %d: %w", count+1, err)
+		}
+		count++
+		switch {
+		case entry.Kind == recordKindMarket && entry.Market != nil:
+			p.market = append(p.market, entry)
+		case entry.Kind == recordKindFX && entry.FX != nil:
+			p.fx = append(p.fx, entry)
+		default:
+			continue
+		}
+		if first.IsZero() || entry.At.Before(first) {
+			first = entry.At
+		}
+		if entry.At.After(last) {
+			last = entry.At
+		}
+	}
+	if len(p.market) == 0 {
+		return nil, fmt.Errorf("replay: no market snapshots in %s", path)
+	}
+	sortEntries(p.market)
+	sortEntries(p.fx)
+	p.origin = first
+	// One extra average gap keeps the last record on screen for as long as
+	// the others before the timeline wraps around.
+	if records := len(p.market) + len(p.fx); records > 1 && last.After(first) {
+		span := last.Sub(first)
+		p.period = span + span/time.Duration(records-1)
+	}
+	log.Printf("marketfeed: replay loaded path=%s market=%d fx=%d period=%s speed=%g", path, len(p.market), len(p.fx), p.period, speed)
+	return p, nil
+}
+
+func (p *ReplayProvider) Name() string {
+	return "replay"
+}
+
+func (p *ReplayProvider) FetchUSD(ctx context.Context, coins []CoinRef) (MarketSnapshot, error) {
+	if err := ctx.Err(); err != nil {
+		return MarketSnapshot{}, err
+	}
+	entry, now := p.current(p.market)
+	shift := now.Sub(entry.At)
+	recorded := entry.Market
+
+	wanted := make(map[string]bool, len(coins))
+	for _, ref := range coins {
+		wanted[ref.ID] = true
+	}
+	snapshot := MarketSnapshot{
+		Provider:  recorded.Provider,
+		FetchedAt: shiftTime(recorded.FetchedAt, shift),
+		Coins:     make(map[string]CoinQuoteUSD, len(recorded.Coins)),
+	}
+	for id, quote := range recorded.Coins {
+		if len(wanted) > 0 && !wanted[id] {
+			continue
+		}
+		quote.LastUpdate = shiftTime(quote.LastUpdate, shift)
+		snapshot.Coins[id] = quote
+	}
+	if len(snapshot.Coins) == 0 {
+		return MarketSnapshot{}, errors.New("replay: recording has none of the tracked coins")
+	}
+	return snapshot, nil
+}
+
+func (p *ReplayProvider) FetchRates(ctx context.Context) (FXSnapshot, error) {
+	if err := ctx.Err(); err != nil {
+		return FXSnapshot{}, err
+	}
+	if len(p.fx) == 0 {
+		return FXSnapshot{}, fmt.Errorf("replay: no fx snapshots in %s", p.path)
+	}
+	entry, now := p.current(p.fx)
+	recorded := entry.FX
+	rates := make(map[i18n.FiatCurrency]float64, len(recorded.Rates))
+	for fiat, rate := range recorded.Rates {
+		rates[fiat] = rate
+	}
+	return FXSnapshot{
+		Base:      recorded.Base,
+		FetchedAt: shiftTime(recorded.FetchedAt, now.Sub(entry.At)),
+		Rates:     rates,
+	}, nil
+}
+
+// current returns the last entry recorded at or before the replay position.
+// Before the first entry of a loop, the previous loop's last entry is used.
+func (p *ReplayProvider) current(entries []recordEntry) (recordEntry, time.Time) {
+	p.mu.Lock()
+	now := p.now()
+	if p.start.IsZero() {
+		p.start = now
+	}
+	offset := time.Duration(float64(now.Sub(p.start)) * p.speed)
+	p.mu.Unlock()
+	if p.period > 0 {
+		offset %= p.period
+	}
+	position := p.origin.Add(offset)
+	index := len(entries) - 1
+	for i, entry := range entries {
+		if entry.At.After(position) {
+			if i > 0 {
+				index = i - 1
+			}
+			break
+		}
+	}
+	return entries[index], now
+}
+
+func sortEntries(entries []recordEntry) {
+	sort.SliceStable(entries, func(i, j int) bool {
+		return entries[i].At.Before(entries[j].At)
+	})
+}
+
+func shiftTime(t time.Time, shift time.Duration) time.Time {
+	if t.IsZero() {
+		return t
+	}
+	return t.Add(shift)
+}
@@ -0,0 +1,143 @@
+package marketfeed
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"cryptoview/internal/ui/i18n"
+)
+
+func TestRecordingProvidersWriteReplayableSnapshots(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "session.jsonl")
+	recorder, err := NewRecorder(path)
+	if err != nil {
+		t.Fatalf("new recorder: %v", err)
+	}
+	market := NewRecordingProvider(&fakeMarketProvider{
+		name: "fake",
+		fetchFunc: func(context.Context) (MarketSnapshot, error) {
+			return MarketSnapshot{Provider: "fake", FetchedAt: time.Now(), Coins: map[string]CoinQuoteUSD{
+				"bitcoin": {ID: "bitcoin", Name: "Bitcoin", Ticker: "BTC", PriceUSD: 50000, Native: map[i18n.FiatCurrency]float64{i18n.FiatEUR: 46000}},
+			}}, nil
+		},
+	}, recorder)
+	fx := NewRecordingFXProvider(&fakeFXProvider{
+		fetchFunc: func(context.Context) (FXSnapshot, error) {
+			return FXSnapshot{Base: "USD", FetchedAt: time.Now(), Rates: map[i18n.FiatCurrency]float64{i18n.FiatEUR: 0.92}}, nil
+		},
+	}, recorder)
+
+	if market.Name() != "fake" || fx.Name() != "fakefx" {
+		t.Fatalf("recording providers must keep the wrapped names, got %q %q", market.Name(), fx.Name())
+	}
+	if _, err := market.FetchQuotes(context.Background(), DefaultTrackedCoins(), []i18n.FiatCurrency{i18n.FiatEUR}); err != nil {
+		t.Fatalf("fetch market: %v", err)
+	}
+	if _, err := fx.FetchRates(context.Background()); err != nil {
+		t.Fatalf("fetch fx: %v", err)
+	}
+	if err := recorder.Close(); err != nil {
+		t.Fatalf("close recorder: %v", err)
+	}
+
+	replay, err := NewReplayProvider(path, 1)
+	if err != nil {
+		t.Fatalf("new replay: %v", err)
+	}
+	snapshot, err := replay.FetchUSD(context.Background(), DefaultTrackedCoins())
+	if err != nil {
+		t.Fatalf("replay market: %v", err)
+	}
+	btc := snapshot.Coins["bitcoin"]
+	if snapshot.Provider != "fake" || btc.PriceUSD != 50000 || btc.Native[i18n.FiatEUR] != 46000 {
+		t.Fatalf("unexpected replayed snapshot %+v", snapshot)
+	}
+	rates, err := replay.FetchRates(context.Background())
+	if err != nil || rates.Rates[i18n.FiatEUR] != 0.92 {
+		t.Fatalf("unexpected replayed rates %+v err=%v", rates, err)
+	}
+}
+
+func TestReplayFollowsTimelineAtSpeedAndLoops(t *testing.T) {
+	origin := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	entry := func(offset time.Duration, price float64) recordEntry {
+		at := origin.Add(offset)
+		return recordEntry{At: at, Kind: recordKindMarket, Provider: "coingecko", Market: &MarketSnapshot{
+			Provider:  "coingecko",
+			FetchedAt: at,
+			Coins: map[string]CoinQuoteUSD{
+				"bitcoin":  {ID: "bitcoin", PriceUSD: price, LastUpdate: at},
+				"ethereum": {ID: "ethereum", PriceUSD: price / 20, LastUpdate: at},
+			},
+		}}
+	}
+	path := filepath.Join(t.TempDir(), "session.jsonl")
+	file, err := os.Create(path)
+	if err != nil {
+		t.Fatalf("create: %v", err)
+	}
+	enc := json.NewEncoder(file)
+	for _, e := range []recordEntry{entry(0, 100), entry(10*time.Second, 200), entry(20*time.Second, 300)} {
+		if err := enc.Encode(e); err != nil {
+			t.Fatalf("encode: %v", err)
+		}
+	}
+	file.WriteString(`{"at":"2026-03-01T12:00:30Z","kind":"mar`)
+	file.Close()
+
+	replay, err := NewReplayProvider(path, 10)
+	if err != nil {
+		t.Fatalf("new replay: %v", err)
+	}
+	start := time.Date(2026, 10, 18, 9, 0, 0, 0, time.UTC)
+	now := start
+	replay.now = func() time.Time { return now }
+	btc := []CoinRef{{ID: "bitcoin"}}
+
+	cases := []struct {
+		elapsed time.Duration
+		price   float64
+	}{
+		{0, 100},
+		{900 * time.Millisecond, 100},
+		{time.Second, 200},
+		{2500 * time.Millisecond, 300},
+		// The timeline is 20s long plus one 10s gap, so at 10x it loops after 3s.
+		{3 * time.Second, 100},
+		{4 * time.Second, 200},
+	}
+	for _, tc := range cases {
+		now = start.Add(tc.elapsed)
+		snapshot, err := replay.FetchUSD(context.Background(), btc)
+		if err != nil {
+			t.Fatalf("elapsed %s: %v", tc.elapsed, err)
+		}
+		quote := snapshot.Coins["bitcoin"]
+		if quote.PriceUSD != tc.price {
+			t.Fatalf("elapsed %s: expected price %g, got %g", tc.elapsed, tc.price, quote.PriceUSD)
+		}
+		if !snapshot.FetchedAt.Equal(now) || !quote.LastUpdate.Equal(now) {
+			t.Fatalf("elapsed %s: expected timestamps rebased to now, got %s/%s", tc.elapsed, snapshot.FetchedAt, quote.LastUpdate)
+		}
+		if _, ok := snapshot.Coins["ethereum"]; ok {
+			t.Fatalf("expected untracked coins to be filtered out")
+		}
+	}
+	if _, err := replay.FetchRates(context.Background()); err == nil {
+		t.Fatalf("expected an error for a recording without fx snapshots")
+	}
+}
+
+func TestNewDefaultWithSourceRejectsRecordAndReplay(t *testing.T) {
+	if _, err := NewDefaultWithSource(Callbacks{}, SourceOptions{RecordPath: "a", ReplayPath: "b"}); !errors.Is(err, ErrRecordAndReplay) {
+		t.Fatalf("expected ErrRecordAndReplay, got %v", err)
+	}
+	if _, err := NewDefaultWithSource(Callbacks{}, SourceOptions{ReplayPath: filepath.Join(t.TempDir(), "missing.jsonl")}); err == nil {
+		t.Fatalf("expected an error for a missing recording")
+	}
+}
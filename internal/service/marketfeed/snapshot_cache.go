@@ -0,0 +1,94 @@
+package marketfeed
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"cryptoview/internal/atomicfile"
+)
+
+const (
+	snapshotCacheVersion = 1
+	// snapshotSaveInterval limits cache writes, each an fsync and rename,
+	// while the stream or a fast poll keeps moving the snapshot.
+	snapshotSaveInterval = 30 * time.Second
+)
+
+type SnapshotCache struct {
+	mu   sync.Mutex
+	path string
+}
+
+type cachedSnapshots struct {
+	Version int             `json:"version"`
+	SavedAt time.Time       `json:"saved_at"`
+	Market  *MarketSnapshot `json:"market,omitempty"`
+	FX      *FXSnapshot     `json:"fx,omitempty"`
+}
+
+func NewSnapshotCache(path string) *SnapshotCache {
+	return &SnapshotCache{path: path}
+}
+
+func DefaultSnapshotCachePath() (string, error) {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "CryptoView", "snapshot.json"), nil
+}
+
+func (c *SnapshotCache) Path() string {
+	return c.path
+}
+
+func (c *SnapshotCache) Load() (*MarketSnapshot, *FXSnapshot, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	data, err := os.ReadFile(c.path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil, nil
+	}
+	if err != nil {
+		return nil, nil, fmt.Errorf("snapshot cache: read: %w", err)
+	}
+	var payload cachedSnapshots
+	if err := json.Unmarshal(data, &payload); err != nil {
+		return nil, nil, fmt.Errorf("snapshot cache: decode: %w", err)
+	}
+	if payload.Version != snapshotCacheVersion {
+		return nil, nil, fmt.Errorf("snapshot cache: unsupported version %d", payload.Version)
+	}
+	if payload.Market != nil && len(payload.Market.Coins) == 0 {
+		payload.Market = nil
+	}
+	if payload.FX != nil && len(payload.FX.Rates) == 0 {
+		payload.FX = nil
+	}
+	return payload.Market, payload.FX, nil
+}
+
+func (c *SnapshotCache) Save(market *MarketSnapshot, fx *FXSnapshot) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	data, err := json.Marshal(cachedSnapshots{
+		Version: snapshotCacheVersion,
+		SavedAt: time.Now(),
+		Market:  market,
+		FX:      fx,
+	})
+	if err != nil {
+		return fmt.Errorf("snapshot cache: encode: %w", err)
+	}
+	if err := atomicfile.Write(c.path, data, 0o600); err != nil {
+		return fmt.Errorf("snapshot cache: save: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,355 @@
+package marketfeed
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"cryptoview/internal/model"
+	"cryptoview/internal/service/coinregistry"
+	"cryptoview/internal/ui/i18n"
+	"github.com/gorilla/websocket"
+)
+
+const (
+	defaultStreamIdleTimeout = 30 * time.Second
+	streamStaleAfter         = 10 * time.Second
+	streamEmitInterval       = 250 * time.Millisecond
+	streamMinBackoff         = 1 * time.Second
+	streamMaxBackoff         = 30 * time.Second
+	streamMaxMessageBytes    = 1 << 20
+)
+
+type StreamingMarketProvider interface {
+	Name() string
+	Stream(ctx context.Context, coins []CoinRef, onTick func(CoinQuoteUSD)) error
+}
+
+type BinanceStreamProvider struct {
+	registryBinding
+	baseURL     string
+	idleTimeout time.Duration
+}
+
+func NewBinanceStreamProvider() *BinanceStreamProvider {
+	return &BinanceStreamProvider{
+		baseURL:     "wss://stream.binance.com:9443/stream",
+		idleTimeout: defaultStreamIdleTimeout,
+	}
+}
+
+func (p *BinanceStreamProvider) Name() string { return "binance-stream" }
+
+func (p *BinanceStreamProvider) Stream(ctx context.Context, tracked []CoinRef, onTick func(CoinQuoteUSD)) error {
+	index := p.coinIndex(coinregistry.ProviderBinance, tracked)
+	pairs := index.requestKeys()
+	if len(pairs) == 0 {
+		return &ProviderError{Provider: p.Name(), Kind: FailureKindOther, Err: errors.New("no streamable coins")}
+	}
+	streams := make([]string, 0, len(pairs))
+	for _, pair := range pairs {
+		streams = append(streams, strings.ToLower(pair)+"@miniTicker")
+	}
+	endpoint := p.baseURL + "?streams=" + strings.Join(streams, "/")
+
+	dialer := websocket.Dialer{
+		Proxy:            http.ProxyFromEnvironment,
+		HandshakeTimeout: p.idleTimeout,
+	}
+	conn, resp, err := dialer.DialContext(ctx, endpoint, http.Header{"User-Agent": {"CryptoView/1.0"}})
+	if err != nil {
+		if resp != nil {
+			err = fmt.Errorf("%w (HTTP %d)", err, resp.StatusCode)
+		}
+		return wrapNetworkError(p.Name(), err)
+	}
+	defer func() {
+		_ = conn.WriteControl(websocket.CloseMessage, websocket.FormatCloseMessage(websocket.CloseNormalClosure, ""), time.Now().Add(time.Second))
+		conn.Close()
+	}()
+	conn.SetReadLimit(streamMaxMessageBytes)
+	// Binance pings every few minutes; a ping proves the link is alive even
+	// when the watched pairs are quiet.
+	conn.SetPingHandler(func(data string) error {
+		p.extendReadDeadline(conn)
+		return conn.WriteControl(websocket.PongMessage, []byte(data), time.Now().Add(5*time.Second))
+	})
+
+	stop := context.AfterFunc(ctx, func() { conn.NetConn().Close() })
+	defer stop()
+
+	for {
+		p.extendReadDeadline(conn)
+		_, message, err := conn.ReadMessage()
+		if err != nil {
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+			var closeErr *websocket.CloseError
+			if errors.As(err, &closeErr) || errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF) {
+				return &ProviderError{Provider: p.Name(), Kind: FailureKindNetwork, Err: err}
+			}
+			return wrapNetworkError(p.Name(), err)
+		}
+		quote, ok, err := parseBinanceMiniTicker(message, index)
+		if err != nil {
+			return &ProviderError{Provider: p.Name(), Kind: FailureKindOther, Err: err}
+		}
+		if ok {
+			onTick(quote)
+		}
+	}
+}
+
+func (p *BinanceStreamProvider) extendReadDeadline(conn *websocket.Conn) {
+	if p.idleTimeout > 0 {
+		_ = conn.SetReadDeadline(time.Now().Add(p.idleTimeout))
+	}
+}
+
+type binanceMiniTicker struct {
+	EventType string `json:"e"`
+	EventTime int64  `json:"E"`
+	Symbol    string `json:"s"`
+	Close     string `json:"c"`
+	Open      string `json:"o"`
+}
+
+func parseBinanceMiniTicker(message []byte, index coinIndex) (CoinQuoteUSD, bool, error) {
+	var envelope struct {
+		Stream string          `json:"stream"`
+		Data   json.RawMessage `json:"data"`
+	}
+	if err := json.Unmarshal(message, &envelope); err != nil {
+		return CoinQuoteUSD{}, false, fmt.Errorf("decode stream envelope: %w", err)
+	}
+	raw := envelope.Data
+	if len(raw) == 0 {
+		raw = message
+	}
+	var tick binanceMiniTicker
+	if err := json.Unmarshal(raw, &tick); err != nil {
+		return CoinQuoteUSD{}, false, fmt.Errorf("decode mini ticker: %w", err)
+	}
+	if tick.EventType != "24hrMiniTicker" {
+		return CoinQuoteUSD{}, false, nil
+	}
+	id := index.resolve(tick.Symbol)
+	if id == "" {
+		return CoinQuoteUSD{}, false, nil
+	}
+	price, err := strconv.ParseFloat(tick.Close, 64)
+	if err != nil || price <= 0 {
+		return CoinQuoteUSD{}, false, nil
+	}
+	var changePtr *float64
+	if open, err := strconv.ParseFloat(tick.Open, 64); err == nil && open > 0 {
+		change := (price - open) / open * 100
+		changePtr = &change
+	}
+	lastUpdate := time.Now()
+	if tick.EventTime > 0 {
+		lastUpdate = time.UnixMilli(tick.EventTime)
+	}
+	ref := index.ref(id)
+	return CoinQuoteUSD{
+		ID:         id,
+		Name:       ref.Name,
+		Ticker:     ref.Ticker,
+		PriceUSD:   price,
+		Change24h:  changePtr,
+		LastUpdate: lastUpdate,
+	}, true, nil
+}
+
+func (f *Feed) SetStreamingProvider(provider StreamingMarketProvider) {
+	f.mu.Lock()
+	f.stream = provider
+	registry := f.registry
+	f.mu.Unlock()
+	if binder, ok := provider.(interface {
+		SetRegistry(*coinregistry.Registry)
+	}); ok && registry != nil {
+		binder.SetRegistry(registry)
+	}
+}
+
+// StreamSetting describes the streaming provider for the settings dialog;
+// ok is false when the feed has none.
+func (f *Feed) StreamSetting() (setting ProviderSetting, ok bool) {
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+	if f.stream == nil {
+		return ProviderSetting{}, false
+	}
+	return ProviderSetting{Name: f.stream.Name(), Enabled: !f.streamDisabled}, true
+}
+
+// SetStreamEnabled turns the streaming provider on or off while running.
+// Turning it off closes the connection and hands quotes back to polling.
+func (f *Feed) SetStreamEnabled(enabled bool) {
+	f.mu.Lock()
+	if f.streamDisabled == !enabled {
+		f.mu.Unlock()
+		return
+	}
+	f.streamDisabled = !enabled
+	cancel := f.streamCancel
+	f.mu.Unlock()
+	log.Printf("marketfeed: stream enabled=%t", enabled)
+	if !enabled && cancel != nil {
+		cancel()
+	}
+	select {
+	case f.streamChanged <- struct{}{}:
+	default:
+	}
+}
+
+func (f *Feed) runStream() {
+	backoff := streamMinBackoff
+	for !f.isStopping() {
+		f.mu.Lock()
+		if f.streamDisabled {
+			f.mu.Unlock()
+			select {
+			case <-f.streamChanged:
+				backoff = streamMinBackoff
+				continue
+			case <-f.stopCh:
+				return
+			}
+		}
+		provider := f.stream
+		ctx, cancel := context.WithCancel(f.runCtx)
+		f.streamCancel = cancel
+		f.mu.Unlock()
+
+		delivered := false
+		err := provider.Stream(ctx, f.FetchCoins(), func(quote CoinQuoteUSD) {
+			delivered = true
+			f.applyStreamTick(provider.Name(), quote)
+		})
+		cancel()
+
+		f.mu.Lock()
+		f.streamCancel = nil
+		wasConnected := f.streamConnected
+		f.streamConnected = false
+		f.mu.Unlock()
+
+		if f.isStopping() {
+			return
+		}
+		if wasConnected {
+			log.Printf("marketfeed: stream disconnected provider=%s err=%v; falling back to polling", provider.Name(), err)
+		} else if err != nil {
+			log.Printf("marketfeed: stream connect failed provider=%s err=%v", provider.Name(), err)
+		}
+		if delivered {
+			backoff = streamMinBackoff
+		}
+
+		select {
+		case <-time.After(backoff):
+		case <-f.streamChanged:
+		case <-f.stopCh:
+			return
+		}
+		backoff *= 2
+		if backoff > streamMaxBackoff {
+			backoff = streamMaxBackoff
+		}
+	}
+}
+
+func (f *Feed) applyStreamTick(provider string, quote CoinQuoteUSD) {
+	now := time.Now()
+
+	f.mu.Lock()
+	if f.streamDisabled {
+		f.mu.Unlock()
+		return
+	}
+	coins := make(map[string]CoinQuoteUSD, len(f.tracked))
+	if f.lastMarket != nil {
+		for id, existing := range f.lastMarket.Coins {
+			coins[id] = existing
+		}
+	}
+	if prev, ok := coins[quote.ID]; ok {
+		if quote.Change24h == nil && prev.Change24h != nil {
+			change := *prev.Change24h
+			quote.Change24h = &change
+		}
+		if quote.Native == nil {
+			quote.Native = rescaleNative(prev.Native, prev.PriceUSD, quote.PriceUSD)
+		}
+	}
+	coins[quote.ID] = quote
+	f.lastMarket = &MarketSnapshot{Provider: provider, FetchedAt: now, Coins: coins}
+	f.recordHistoryLocked(f.lastMarket)
+	firstTick := !f.streamConnected
+	f.streamConnected = true
+	f.lastTickAt = now
+	emit := firstTick || now.Sub(f.lastTickEmit) >= streamEmitInterval
+	var display []model.Coin
+	ok := false
+	if emit {
+		f.lastTickEmit = now
+		display, ok = f.buildDisplayCoinsLocked()
+	}
+	f.mu.Unlock()
+
+	if ok {
+		f.emitMarketUpdate(display)
+	}
+	if firstTick {
+		log.Printf("marketfeed: stream connected provider=%s", provider)
+		f.emitStatus(StatusEvent{Kind: StatusKindOK, Provider: provider})
+	}
+}
+
+func (f *Feed) streamHealthy(now time.Time) bool {
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+	return f.streamConnected && now.Sub(f.lastTickAt) < streamStaleAfter
+}
+
+// streamOwnsQuotes reports whether polling can pause for the stream. The
+// stream only carries USD, so while a native fiat is displayed and a market
+// provider can quote it, polling keeps fetching the native prices.
+func (f *Feed) streamOwnsQuotes(now time.Time) bool {
+	if !f.streamHealthy(now) {
+		return false
+	}
+	if len(f.nativeFiats()) == 0 {
+		return true
+	}
+	for _, provider := range f.marketProviders() {
+		if _, ok := provider.(NativeQuoteProvider); ok {
+			return false
+		}
+	}
+	return true
+}
+
+// rescaleNative moves the last polled native prices by the same ratio as the
+// USD price, keeping them current between polls.
+func rescaleNative(native map[i18n.FiatCurrency]float64, fromUSD, toUSD float64) map[i18n.FiatCurrency]float64 {
+	if len(native) == 0 || fromUSD <= 0 || toUSD <= 0 {
+		return nil
+	}
+	scaled := make(map[i18n.FiatCurrency]float64, len(native))
+	for fiat, price := range native {
+		scaled[fiat] = price * toUSD / fromUSD
+	}
+	return scaled
+}
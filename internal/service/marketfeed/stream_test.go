@@ -0,0 +1,302 @@
+package marketfeed
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"cryptoview/internal/model"
+	"cryptoview/internal/ui/i18n"
+	"github.com/gorilla/websocket"
+)
+
+func newWebSocketStandIn(t *testing.T, onRequest func(*http.Request), messages ...string) *httptest.Server {
+	t.Helper()
+	upgrader := websocket.Upgrader{}
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if onRequest != nil {
+			onRequest(r)
+		}
+		conn, err := upgrader.Upgrade(w, r, nil)
+		if err != nil {
+			t.Errorf("upgrade failed: %v", err)
+			return
+		}
+		defer conn.Close()
+		for _, msg := range messages {
+			if err := conn.WriteMessage(websocket.TextMessage, []byte(msg)); err != nil {
+				t.Errorf("write failed: %v", err)
+				return
+			}
+		}
+		_ = conn.WriteControl(websocket.CloseMessage, websocket.FormatCloseMessage(websocket.CloseGoingAway, ""), time.Now().Add(time.Second))
+		time.Sleep(20 * time.Millisecond)
+	}))
+}
+
+func TestBinanceStreamProviderParsesMiniTickers(t *testing.T) {
+	var gotStreams string
+	srv := newWebSocketStandIn(t,
+		func(r *http.Request) { gotStreams = r.URL.Query().Get("streams") },
+		`{"stream":"btcusdt@miniTicker","data":{"e":"24hrMiniTicker","E":1700000000000,"s":"BTCUSDT","c":"110.0","o":"100.0"}}`,
+		`{"stream":"adausdt@miniTicker","data":{"e":"24hrMiniTicker","E":1700000001000,"s":"ADAUSDT","c":"0.5","o":"0"}}`,
+		`{"stream":"ethusdt@miniTicker","data":{"e":"24hrMiniTicker","E":1700000002000,"s":"ETHUSDT","c":"1","o":"1"}}`,
+	)
+	defer srv.Close()
+
+	p := NewBinanceStreamProvider()
+	p.baseURL = "ws" + strings.TrimPrefix(srv.URL, "http") + "/stream"
+
+	var ticks []CoinQuoteUSD
+	err := p.Stream(context.Background(), []CoinRef{{ID: "bitcoin"}, {ID: "cardano"}}, func(q CoinQuoteUSD) {
+		ticks = append(ticks, q)
+	})
+
+	if gotStreams != "btcusdt@miniTicker/adausdt@miniTicker" {
+		t.Fatalf("unexpected stream subscription: %q", gotStreams)
+	}
+	var pe *ProviderError
+	if !errors.As(err, &pe) || pe.Kind != FailureKindNetwork {
+		t.Fatalf("expected network error after server close, got %v", err)
+	}
+	if len(ticks) != 2 {
+		t.Fatalf("expected ticks for watched coins only, got %+v", ticks)
+	}
+	if ticks[0].ID != "bitcoin" || ticks[0].PriceUSD != 110 || ticks[0].Change24h == nil || *ticks[0].Change24h != 10 {
+		t.Fatalf("unexpected BTC tick: %+v", ticks[0])
+	}
+	if ticks[1].ID != "cardano" || ticks[1].Change24h != nil {
+		t.Fatalf("expected ADA tick without change when open is zero, got %+v", ticks[1])
+	}
+}
+
+func TestBinanceStreamProviderDropsIdleConnection(t *testing.T) {
+	upgrader := websocket.Upgrader{}
+	release := make(chan struct{})
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		conn, err := upgrader.Upgrade(w, r, nil)
+		if err != nil {
+			t.Errorf("upgrade failed: %v", err)
+			return
+		}
+		defer conn.Close()
+		<-release
+	}))
+	defer srv.Close()
+	defer close(release)
+
+	p := NewBinanceStreamProvider()
+	p.baseURL = "ws" + strings.TrimPrefix(srv.URL, "http") + "/stream"
+	p.idleTimeout = 50 * time.Millisecond
+
+	start := time.Now()
+	err := p.Stream(context.Background(), []CoinRef{{ID: "bitcoin"}}, func(CoinQuoteUSD) {})
+	var pe *ProviderError
+	if !errors.As(err, &pe) || pe.Kind != FailureKindNetwork {
+		t.Fatalf("expected a network error from a silent stream, got %v", err)
+	}
+	if elapsed := time.Since(start); elapsed > 2*time.Second {
+		t.Fatalf("expected the idle timeout to end the session quickly, took %s", elapsed)
+	}
+}
+
+type fakeStreamProvider struct {
+	mu      sync.Mutex
+	calls   int
+	session func(ctx context.Context, call int, onTick func(CoinQuoteUSD)) error
+}
+
+func (p *fakeStreamProvider) Name() string { return "fake-stream" }
+
+func (p *fakeStreamProvider) Stream(ctx context.Context, _ []CoinRef, onTick func(CoinQuoteUSD)) error {
+	p.mu.Lock()
+	p.calls++
+	call := p.calls
+	p.mu.Unlock()
+	return p.session(ctx, call, onTick)
+}
+
+func TestFeedStreamTicksUpdateMarketAndPausePolling(t *testing.T) {
+	poll := &fakeMarketProvider{
+		name: "cg",
+		fetchFunc: func(context.Context) (MarketSnapshot, error) {
+			return snapshotWithBTC("cg", 100), nil
+		},
+	}
+	var mu sync.Mutex
+	var updates [][]model.Coin
+	var statuses []StatusEvent
+	feed := New([]MarketProvider{poll}, []FXProvider{&fakeFXProvider{}}, Callbacks{
+		OnMarketUpdate: func(coins []model.Coin) {
+			mu.Lock()
+			updates = append(updates, coins)
+			mu.Unlock()
+		},
+		OnStatus: func(event StatusEvent) {
+			mu.Lock()
+			statuses = append(statuses, event)
+			mu.Unlock()
+		},
+	})
+
+	change := 3.0
+	feed.applyStreamTick("fake-stream", CoinQuoteUSD{ID: "bitcoin", PriceUSD: 120, Change24h: &change})
+
+	if !feed.streamHealthy(time.Now()) {
+		t.Fatal("expected stream to be healthy right after a tick")
+	}
+	feed.runMarketCycle()
+	if poll.calls != 0 {
+		t.Fatalf("expected polling to pause while stream is live, got %d calls", poll.calls)
+	}
+	mu.Lock()
+	if len(updates) != 1 || firstBTCPrice(t, updates[0]) != 120 {
+		t.Fatalf("expected tick to be pushed as market update, got %+v", updates)
+	}
+	if len(statuses) != 1 || statuses[0].Kind != StatusKindOK || statuses[0].Provider != "fake-stream" {
+		t.Fatalf("expected OK status for stream, got %+v", statuses)
+	}
+	mu.Unlock()
+
+	feed.mu.Lock()
+	feed.streamConnected = false
+	feed.mu.Unlock()
+	feed.runMarketCycle()
+	if poll.calls != 1 {
+		t.Fatalf("expected polling fallback after disconnect, got %d calls", poll.calls)
+	}
+}
+
+func TestFeedStreamKeepsNativeQuotesForNonUSDFiat(t *testing.T) {
+	poll := &fakeNativeProvider{
+		fakeMarketProvider: fakeMarketProvider{name: "cg", fetchFunc: func(context.Context) (MarketSnapshot, error) {
+			return snapshotWithBTC("cg", 100), nil
+		}},
+		native: map[i18n.FiatCurrency]float64{i18n.FiatEUR: 93},
+	}
+	fx := &fakeFXProvider{fetchFunc: func(context.Context) (FXSnapshot, error) {
+		return FXSnapshot{Base: "USD", Rates: map[i18n.FiatCurrency]float64{i18n.FiatEUR: 0.9}}, nil
+	}}
+	feed := New([]MarketProvider{poll}, []FXProvider{fx}, Callbacks{})
+	feed.SetTrackedCoins([]CoinRef{{ID: "bitcoin"}})
+	feed.runFXCycle()
+	feed.SetFiat(i18n.FiatEUR)
+
+	feed.applyStreamTick("fake-stream", CoinQuoteUSD{ID: "bitcoin", PriceUSD: 120})
+	feed.runMarketCycle()
+	if poll.calls != 1 || len(poll.fiats) != 1 || poll.fiats[0] != i18n.FiatEUR {
+		t.Fatalf("expected the native EUR poll to run next to the stream, calls=%d fiats=%v", poll.calls, poll.fiats)
+	}
+	eur, ok := feed.Quotes(i18n.FiatEUR)
+	if !ok || eur[0].Price != 93 || eur[0].Converted {
+		t.Fatalf("expected the native EUR quote, got %+v", eur)
+	}
+
+	feed.applyStreamTick("fake-stream", CoinQuoteUSD{ID: "bitcoin", PriceUSD: 200})
+	eur, ok = feed.Quotes(i18n.FiatEUR)
+	if !ok || eur[0].Price != 186 || eur[0].Converted {
+		t.Fatalf("expected the native quote to follow the USD tick, got %+v", eur)
+	}
+
+	feed.SetFiat(i18n.FiatUSD)
+	feed.runMarketCycle()
+	if poll.calls != 1 {
+		t.Fatalf("expected the stream to own USD quotes, got %d polls", poll.calls)
+	}
+}
+
+func TestFeedStreamReconnectsAfterDisconnect(t *testing.T) {
+	reconnected := make(chan struct{})
+	stream := &fakeStreamProvider{
+		session: func(ctx context.Context, call int, onTick func(CoinQuoteUSD)) error {
+			if call == 1 {
+				onTick(CoinQuoteUSD{ID: "bitcoin", PriceUSD: 100})
+				return errors.New("connection reset")
+			}
+			if call == 2 {
+				close(reconnected)
+			}
+			<-ctx.Done()
+			return ctx.Err()
+		},
+	}
+	blocking := &fakeMarketProvider{
+		name: "cg",
+		fetchFunc: func(ctx context.Context) (MarketSnapshot, error) {
+			<-ctx.Done()
+			return MarketSnapshot{}, ctx.Err()
+		},
+	}
+	blockingFX := &fakeFXProvider{fetchFunc: func(ctx context.Context) (FXSnapshot, error) {
+		<-ctx.Done()
+		return FXSnapshot{}, ctx.Err()
+	}}
+	feed := New([]MarketProvider{blocking}, []FXProvider{blockingFX}, Callbacks{})
+	feed.SetStreamingProvider(stream)
+	feed.Start()
+	defer feed.Stop()
+
+	select {
+	case <-reconnected:
+	case <-time.After(3 * time.Second):
+		t.Fatal("expected stream to reconnect after disconnect")
+	}
+}
+
+func TestFeedStreamToggleClosesAndReopensSession(t *testing.T) {
+	sessions := make(chan int, 4)
+	closed := make(chan int, 4)
+	stream := &fakeStreamProvider{
+		session: func(ctx context.Context, call int, onTick func(CoinQuoteUSD)) error {
+			sessions <- call
+			<-ctx.Done()
+			closed <- call
+			return ctx.Err()
+		},
+	}
+	blocking := &fakeMarketProvider{name: "cg", fetchFunc: func(ctx context.Context) (MarketSnapshot, error) {
+		<-ctx.Done()
+		return MarketSnapshot{}, ctx.Err()
+	}}
+	blockingFX := &fakeFXProvider{fetchFunc: func(ctx context.Context) (FXSnapshot, error) {
+		<-ctx.Done()
+		return FXSnapshot{}, ctx.Err()
+	}}
+	feed := New([]MarketProvider{blocking}, []FXProvider{blockingFX}, Callbacks{})
+	feed.SetStreamingProvider(stream)
+	feed.Start()
+	defer feed.Stop()
+
+	wait := func(ch chan int, what string) int {
+		t.Helper()
+		select {
+		case call := <-ch:
+			return call
+		case <-time.After(3 * time.Second):
+			t.Fatalf("timed out waiting for %s", what)
+			return 0
+		}
+	}
+	wait(sessions, "the first session")
+
+	feed.SetStreamEnabled(false)
+	wait(closed, "the session to close")
+	if setting, ok := feed.StreamSetting(); !ok || setting.Name != "fake-stream" || setting.Enabled {
+		t.Fatalf("expected a disabled stream setting, got %+v ok=%v", setting, ok)
+	}
+	select {
+	case call := <-sessions:
+		t.Fatalf("expected no session while disabled, got call %d", call)
+	case <-time.After(100 * time.Millisecond):
+	}
+
+	feed.SetStreamEnabled(true)
+	if call := wait(sessions, "the stream to reopen"); call != 2 {
+		t.Fatalf("expected the second session, got %d", call)
+	}
+}
@@ -0,0 +1,221 @@
+package portfolio
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+
+	"cryptoview/internal/atomicfile"
+	"cryptoview/internal/model"
+	"cryptoview/internal/ui/i18n"
+)
+
+const fileVersion = 1
+
+type Holding struct {
+	CoinID       string  `json:"coin_id"`
+	Quantity     float64 `json:"quantity"`
+	CostBasisUSD float64 `json:"cost_basis_usd"`
+}
+
+type Position struct {
+	CoinID     string
+	Name       string
+	Ticker     string
+	Quantity   float64
+	Price      float64
+	Value      float64
+	Cost       float64
+	PnL        float64
+	PnLPercent float64
+	Allocation float64
+	Priced     bool
+}
+
+type Valuation struct {
+	Fiat       i18n.FiatCurrency
+	TotalValue float64
+	TotalCost  float64
+	PnL        float64
+	PnLPercent float64
+	Positions  []Position
+}
+
+type Portfolio struct {
+	mu       sync.RWMutex
+	path     string
+	holdings map[string]Holding
+}
+
+type portfolioFile struct {
+	Version  int       `json:"version"`
+	Holdings []Holding `json:"holdings"`
+}
+
+func DefaultPath() (string, error) {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "CryptoView", "portfolio.json"), nil
+}
+
+func New(path string) *Portfolio {
+	return &Portfolio{path: path, holdings: make(map[string]Holding)}
+}
+
+func (p *Portfolio) Path() string {
+	return p.path
+}
+
+func (p *Portfolio) Load() error {
+	if p.path == "" {
+		return nil
+	}
+	data, err := os.ReadFile(p.path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("portfolio: read: %w", err)
+	}
+	var payload portfolioFile
+	if err := json.Unmarshal(data, &payload); err != nil {
+		return fmt.Errorf("portfolio: decode: %w", err)
+	}
+	if payload.Version != fileVersion {
+		return fmt.Errorf("portfolio: unsupported version %d", payload.Version)
+	}
+	holdings := make(map[string]Holding, len(payload.Holdings))
+	for _, h := range payload.Holdings {
+		h, ok := normalizeHolding(h)
+		if !ok {
+			continue
+		}
+		holdings[h.CoinID] = h
+	}
+	p.mu.Lock()
+	p.holdings = holdings
+	p.mu.Unlock()
+	return nil
+}
+
+func (p *Portfolio) Holdings() []Holding {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	out := make([]Holding, 0, len(p.holdings))
+	for _, h := range p.holdings {
+		out = append(out, h)
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].CoinID < out[j].CoinID })
+	return out
+}
+
+func (p *Portfolio) Upsert(h Holding) error {
+	h, ok := normalizeHolding(h)
+	if !ok {
+		return fmt.Errorf("portfolio: invalid holding for %q", h.CoinID)
+	}
+	p.mu.Lock()
+	p.holdings[h.CoinID] = h
+	p.mu.Unlock()
+	return p.save()
+}
+
+func (p *Portfolio) Remove(coinID string) error {
+	coinID = strings.ToLower(strings.TrimSpace(coinID))
+	p.mu.Lock()
+	_, ok := p.holdings[coinID]
+	delete(p.holdings, coinID)
+	p.mu.Unlock()
+	if !ok {
+		return nil
+	}
+	return p.save()
+}
+
+func (p *Portfolio) Value(coins []model.Coin, fiat i18n.FiatCurrency) Valuation {
+	return Value(p.Holdings(), coins, fiat)
+}
+
+func (p *Portfolio) save() error {
+	if p.path == "" {
+		return nil
+	}
+	data, err := json.MarshalIndent(portfolioFile{Version: fileVersion, Holdings: p.Holdings()}, "", "  ")
+	if err != nil {
+		return fmt.Errorf("portfolio: encode: %w", err)
+	}
+	if err := atomicfile.Write(p.path, data, 0o600); err != nil {
+		return fmt.Errorf("portfolio: save: %w", err)
+	}
+	return nil
+}
+
+func Value(holdings []Holding, coins []model.Coin, fiat i18n.FiatCurrency) Valuation {
+	byID := make(map[string]model.Coin, len(coins))
+	for _, c := range coins {
+		byID[c.ID] = c
+	}
+
+	v := Valuation{Fiat: fiat, Positions: make([]Position, 0, len(holdings))}
+	for _, h := range holdings {
+		pos := Position{CoinID: h.CoinID, Quantity: h.Quantity, Ticker: strings.ToUpper(h.CoinID), Name: h.CoinID}
+		coin, ok := byID[h.CoinID]
+		if ok && coin.Price > 0 {
+			rate, rateOK := FiatRate(coin, fiat)
+			if rateOK {
+				pos.Name = coin.Name
+				pos.Ticker = coin.Ticker
+				pos.Price = coin.Price
+				pos.Value = h.Quantity * coin.Price
+				pos.Cost = h.CostBasisUSD * rate
+				pos.PnL = pos.Value - pos.Cost
+				if pos.Cost > 0 {
+					pos.PnLPercent = pos.PnL / pos.Cost * 100
+				}
+				pos.Priced = true
+				v.TotalValue += pos.Value
+				v.TotalCost += pos.Cost
+			}
+		} else if ok {
+			pos.Name = coin.Name
+			pos.Ticker = coin.Ticker
+		}
+		v.Positions = append(v.Positions, pos)
+	}
+	v.PnL = v.TotalValue - v.TotalCost
+	if v.TotalCost > 0 {
+		v.PnLPercent = v.PnL / v.TotalCost * 100
+	}
+	for i := range v.Positions {
+		if v.TotalValue > 0 && v.Positions[i].Priced {
+			v.Positions[i].Allocation = v.Positions[i].Value / v.TotalValue * 100
+		}
+	}
+	sort.SliceStable(v.Positions, func(i, j int) bool { return v.Positions[i].Value > v.Positions[j].Value })
+	return v
+}
+
+func FiatRate(coin model.Coin, fiat i18n.FiatCurrency) (float64, bool) {
+	if coin.PriceUSD > 0 && coin.Price > 0 {
+		return coin.Price / coin.PriceUSD, true
+	}
+	if fiat == i18n.FiatUSD {
+		return 1, true
+	}
+	return 0, false
+}
+
+func normalizeHolding(h Holding) (Holding, bool) {
+	h.CoinID = strings.ToLower(strings.TrimSpace(h.CoinID))
+	if h.CoinID == "" || h.Quantity <= 0 || h.CostBasisUSD < 0 {
+		return h, false
+	}
+	return h, true
+}
@@ -0,0 +1,93 @@
+package portfolio
+
+import (
+	"math"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"cryptoview/internal/model"
+	"cryptoview/internal/ui/i18n"
+)
+
+func approx(a, b float64) bool {
+	return math.Abs(a-b) < 1e-9
+}
+
+func TestValueComputesAllocationAndPnLInFiat(t *testing.T) {
+	holdings := []Holding{
+		{CoinID: "bitcoin", Quantity: 0.5, CostBasisUSD: 20000},
+		{CoinID: "ethereum", Quantity: 10, CostBasisUSD: 10000},
+		{CoinID: "dogecoin", Quantity: 1000, CostBasisUSD: 50},
+	}
+	coins := []model.Coin{
+		{ID: "bitcoin", Name: "Bitcoin", Ticker: "BTC", Price: 45000, PriceUSD: 50000},
+		{ID: "ethereum", Name: "Ethereum", Ticker: "ETH", Price: 900, PriceUSD: 1000},
+	}
+
+	v := Value(holdings, coins, i18n.FiatEUR)
+
+	if !approx(v.TotalValue, 22500+9000) {
+		t.Fatalf("unexpected total value %v", v.TotalValue)
+	}
+	if !approx(v.TotalCost, (20000+10000)*0.9) {
+		t.Fatalf("expected cost basis converted to EUR, got %v", v.TotalCost)
+	}
+	if !approx(v.PnL, 31500-27000) || !approx(v.PnLPercent, 4500.0/27000*100) {
+		t.Fatalf("unexpected total pnl %v (%v%%)", v.PnL, v.PnLPercent)
+	}
+	if len(v.Positions) != 3 || v.Positions[0].CoinID != "bitcoin" {
+		t.Fatalf("expected positions sorted by value, got %+v", v.Positions)
+	}
+	btc := v.Positions[0]
+	if !approx(btc.Allocation, 22500/31500.0*100) || !approx(btc.PnLPercent, 25) {
+		t.Fatalf("unexpected bitcoin position %+v", btc)
+	}
+	if doge := v.Positions[2]; doge.Priced || doge.Allocation != 0 {
+		t.Fatalf("expected unpriced coin to be excluded from totals, got %+v", doge)
+	}
+}
+
+func TestPortfolioPersistsHoldings(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "portfolio.json")
+	p := New(path)
+	if err := p.Load(); err != nil {
+		t.Fatalf("expected missing file to be ignored, got %v", err)
+	}
+	if err := p.Upsert(Holding{CoinID: " Bitcoin ", Quantity: 1.5, CostBasisUSD: 30000}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := p.Upsert(Holding{CoinID: "solana", Quantity: 0}); err == nil {
+		t.Fatal("expected zero quantity to be rejected")
+	}
+
+	reloaded := New(path)
+	if err := reloaded.Load(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	holdings := reloaded.Holdings()
+	if len(holdings) != 1 || holdings[0].CoinID != "bitcoin" || holdings[0].Quantity != 1.5 {
+		t.Fatalf("unexpected reloaded holdings %+v", holdings)
+	}
+
+	if err := reloaded.Remove("bitcoin"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(data) == "" || len(reloaded.Holdings()) != 0 {
+		t.Fatalf("expected holding removal to be persisted, got %s", data)
+	}
+}
+
+func TestPortfolioLoadRejectsUnknownVersion(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "portfolio.json")
+	if err := os.WriteFile(path, []byte(`{"version":99,"holdings":[]}`), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if err := New(path).Load(); err == nil {
+		t.Fatal("expected error for unsupported version")
+	}
+}
@@ -0,0 +1,312 @@
+package ui
+
+import (
+	"fmt"
+	"log"
+	"sort"
+	"strings"
+	"time"
+
+	"cryptoview/internal/model"
+	"cryptoview/internal/service/alerts"
+	"cryptoview/internal/ui/i18n"
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/theme"
+	"fyne.io/fyne/v2/widget"
+)
+
+type alertWindowOption struct {
+	key    string
+	window time.Duration
+}
+
+var alertKinds = []alerts.Kind{alerts.KindAbove, alerts.KindBelow, alerts.KindPercentMove, alerts.KindCrossOpen}
+
+var alertWindows = []alertWindowOption{
+	{key: "alerts.window.15m", window: 15 * time.Minute},
+	{key: "alerts.window.1h", window: time.Hour},
+	{key: "alerts.window.4h", window: 4 * time.Hour},
+	{key: "alerts.window.24h", window: 24 * time.Hour},
+}
+
+type alertsWindow struct {
+	window     fyne.Window
+	engine     *alerts.Engine
+	translator *i18n.Translator
+	coins      []model.Coin
+	fiat       i18n.FiatCurrency
+
+	rules        *fyne.Container
+	history      *fyne.Container
+	coinSelect   *widget.Select
+	kindSelect   *widget.Select
+	windowSelect *widget.Select
+	value        *widget.Entry
+	message      *widget.Label
+	coinIDs      map[string]string
+}
+
+func loadAlerts() *alerts.Engine {
+	path, err := alerts.DefaultPath()
+	if err != nil {
+		log.Printf("alerts: persistence disabled: %v", err)
+	}
+	engine := alerts.NewEngine(path)
+	if err := engine.Load(); err != nil {
+		log.Printf("alerts: load failed path=%s err=%v", path, err)
+	}
+	return engine
+}
+
+func showAlertsWindow(a fyne.App, engine *alerts.Engine, translator *i18n.Translator, coins []model.Coin, fiat i18n.FiatCurrency, onClosed func()) *alertsWindow {
+	aw := &alertsWindow{
+		window:     a.NewWindow(translator.T("alerts.title")),
+		engine:     engine,
+		translator: translator,
+		rules:      container.NewVBox(),
+		history:    container.NewVBox(),
+		value:      widget.NewEntry(),
+		message:    widget.NewLabel(""),
+	}
+	aw.coinSelect = widget.NewSelect(nil, nil)
+	aw.kindSelect = widget.NewSelect(nil, func(string) { aw.syncForm() })
+	aw.windowSelect = widget.NewSelect(nil, nil)
+	aw.message.Importance = widget.DangerImportance
+	aw.message.Hide()
+
+	aw.build()
+	aw.update(coins, fiat)
+	aw.window.Resize(fyne.NewSize(560, 460))
+	aw.window.SetOnClosed(func() {
+		if onClosed != nil {
+			onClosed()
+		}
+	})
+	aw.window.Show()
+	return aw
+}
+
+func (aw *alertsWindow) build() {
+	t := aw.translator
+	aw.window.SetTitle(t.T("alerts.title"))
+
+	kindOptions := make([]string, len(alertKinds))
+	for i, kind := range alertKinds {
+		kindOptions[i] = t.T("alerts.kind." + string(kind))
+	}
+	aw.kindSelect.Options = kindOptions
+	aw.kindSelect.PlaceHolder = t.T("alerts.kind")
+	aw.kindSelect.ClearSelected()
+	windowOptions := make([]string, len(alertWindows))
+	for i, w := range alertWindows {
+		windowOptions[i] = t.T(w.key)
+	}
+	aw.windowSelect.Options = windowOptions
+	aw.windowSelect.SetSelected(windowOptions[1])
+
+	add := widget.NewButtonWithIcon(t.T("alerts.add"), theme.ContentAddIcon(), aw.add)
+	add.Importance = widget.HighImportance
+
+	form := container.NewVBox(
+		widget.NewSeparator(),
+		container.NewGridWithColumns(2, aw.coinSelect, aw.kindSelect),
+		container.NewBorder(nil, nil, nil, add, container.NewGridWithColumns(2, aw.value, aw.windowSelect)),
+		aw.message,
+	)
+	tabs := container.NewAppTabs(
+		container.NewTabItem(t.T("alerts.rules"), container.NewVScroll(aw.rules)),
+		container.NewTabItem(t.T("alerts.history"), container.NewVScroll(aw.history)),
+	)
+	aw.window.SetContent(container.NewBorder(nil, form, nil, nil, tabs))
+	aw.syncForm()
+}
+
+func (aw *alertsWindow) setLanguage() {
+	aw.build()
+	aw.render()
+}
+
+func (aw *alertsWindow) update(coins []model.Coin, fiat i18n.FiatCurrency) {
+	fiatChanged := aw.fiat != fiat
+	aw.coins = coins
+	aw.fiat = fiat
+	if fiatChanged {
+		aw.syncForm()
+	}
+	aw.render()
+}
+
+func (aw *alertsWindow) render() {
+	t := aw.translator
+
+	aw.rules.RemoveAll()
+	rules := aw.engine.Rules()
+	if len(rules) == 0 {
+		aw.rules.Add(widget.NewLabel(t.T("alerts.empty")))
+	}
+	for _, rule := range rules {
+		ruleID := rule.ID
+		remove := widget.NewButtonWithIcon("", theme.DeleteIcon(), func() {
+			if err := aw.engine.RemoveRule(ruleID); err != nil {
+				log.Printf("alerts: remove failed rule=%s err=%v", ruleID, err)
+				aw.showMessage(t.T("alerts.error.save"))
+			}
+			aw.render()
+		})
+		remove.Importance = widget.LowImportance
+		text := describeRule(t, rule, aw.tickerFor(rule.CoinID))
+		if aw.engine.WaitingForRate(rule) {
+			text = fmt.Sprintf(t.T("alerts.rule.waiting"), text, rule.Fiat)
+		}
+		aw.rules.Add(container.NewBorder(nil, nil, nil, remove, widget.NewLabel(text)))
+	}
+
+	aw.history.RemoveAll()
+	history := aw.engine.History()
+	if len(history) == 0 {
+		aw.history.Add(widget.NewLabel(t.T("alerts.history.empty")))
+	}
+	for _, alert := range history {
+		aw.history.Add(widget.NewLabel(fmt.Sprintf("%s  %s", i18n.FormatDateTime(alert.At, t.Language()), describeAlert(t, alert))))
+	}
+	aw.refreshCoinOptions()
+}
+
+func (aw *alertsWindow) refreshCoinOptions() {
+	options := make([]string, 0, len(aw.coins))
+	ids := make(map[string]string, len(aw.coins))
+	for _, coin := range aw.coins {
+		label := fmt.Sprintf("%s | %s", coin.Ticker, coin.Name)
+		options = append(options, label)
+		ids[label] = coin.ID
+	}
+	sort.Strings(options)
+	aw.coinIDs = ids
+	aw.coinSelect.PlaceHolder = aw.translator.T("portfolio.coin")
+	aw.coinSelect.Options = options
+	aw.coinSelect.Refresh()
+}
+
+func (aw *alertsWindow) selectedKind() (alerts.Kind, bool) {
+	index := aw.kindSelect.SelectedIndex()
+	if index < 0 || index >= len(alertKinds) {
+		return "", false
+	}
+	return alertKinds[index], true
+}
+
+func (aw *alertsWindow) syncForm() {
+	t := aw.translator
+	kind, _ := aw.selectedKind()
+	switch kind {
+	case alerts.KindPercentMove:
+		aw.value.SetPlaceHolder(t.T("alerts.value.percent"))
+		aw.value.Enable()
+		aw.windowSelect.Enable()
+	case alerts.KindCrossOpen:
+		aw.value.SetText("")
+		aw.value.SetPlaceHolder("")
+		aw.value.Disable()
+		aw.windowSelect.Disable()
+	default:
+		aw.value.SetPlaceHolder(fmt.Sprintf(t.T("alerts.value.price"), aw.fiat))
+		aw.value.Enable()
+		aw.windowSelect.Disable()
+	}
+}
+
+func (aw *alertsWindow) add() {
+	t := aw.translator
+	id, coinOK := aw.coinIDs[aw.coinSelect.Selected]
+	kind, kindOK := aw.selectedKind()
+	if !coinOK || !kindOK {
+		aw.showMessage(t.T("alerts.error.input"))
+		return
+	}
+	rule := alerts.Rule{CoinID: id, Kind: kind}
+	switch kind {
+	case alerts.KindAbove, alerts.KindBelow:
+		value, ok := parseAmount(aw.value.Text)
+		if !ok || value <= 0 {
+			aw.showMessage(t.T("alerts.error.input"))
+			return
+		}
+		rule.Threshold = value
+		rule.Fiat = aw.fiat
+	case alerts.KindPercentMove:
+		value, ok := parseAmount(strings.TrimSuffix(strings.TrimSpace(aw.value.Text), "%"))
+		if !ok || value == 0 {
+			aw.showMessage(t.T("alerts.error.input"))
+			return
+		}
+		rule.Percent = value
+		if index := aw.windowSelect.SelectedIndex(); index >= 0 && index < len(alertWindows) {
+			rule.Window = alertWindows[index].window
+		}
+	}
+	if _, err := aw.engine.AddRule(rule); err != nil {
+		log.Printf("alerts: add failed coin=%s kind=%s err=%v", id, kind, err)
+		aw.showMessage(t.T("alerts.error.save"))
+		return
+	}
+	aw.message.Hide()
+	aw.value.SetText("")
+	aw.render()
+}
+
+func (aw *alertsWindow) tickerFor(coinID string) string {
+	for _, coin := range aw.coins {
+		if coin.ID == coinID {
+			return coin.Ticker
+		}
+	}
+	return strings.ToUpper(coinID)
+}
+
+func (aw *alertsWindow) showMessage(text string) {
+	aw.message.SetText(text)
+	aw.message.Show()
+}
+
+func describeRule(t *i18n.Translator, rule alerts.Rule, ticker string) string {
+	switch rule.Kind {
+	case alerts.KindAbove, alerts.KindBelow:
+		return fmt.Sprintf(t.T("alerts.rule."+string(rule.Kind)), ticker, i18n.FormatPrice(rule.Threshold, rule.Fiat, t.Language()))
+	case alerts.KindPercentMove:
+		return fmt.Sprintf(t.T("alerts.rule.percent_move"), ticker, rule.Percent, formatAlertWindow(t, rule.Window))
+	default:
+		return fmt.Sprintf(t.T("alerts.rule.cross_open"), ticker)
+	}
+}
+
+func describeAlert(t *i18n.Translator, alert alerts.Alert) string {
+	lang := t.Language()
+	price := i18n.FormatPrice(alert.Price, alert.Fiat, lang)
+	switch alert.Kind {
+	case alerts.KindAbove, alerts.KindBelow:
+		threshold := i18n.FormatPrice(alert.Rule.Threshold, alert.Rule.Fiat, lang)
+		return fmt.Sprintf(t.T("alerts.fired."+string(alert.Kind)), alert.Ticker, threshold, price)
+	case alerts.KindPercentMove:
+		return fmt.Sprintf(t.T("alerts.fired.percent_move"), alert.Ticker, alert.Change, formatAlertWindow(t, alert.Rule.Window), price)
+	default:
+		key := "alerts.fired.cross_down"
+		if alert.Up {
+			key = "alerts.fired.cross_up"
+		}
+		return fmt.Sprintf(t.T(key), alert.Ticker, price, alert.Change)
+	}
+}
+
+func alertNotification(t *i18n.Translator, alert alerts.Alert) *fyne.Notification {
+	return fyne.NewNotification(fmt.Sprintf(t.T("alerts.notification.title"), alert.Ticker), describeAlert(t, alert))
+}
+
+func formatAlertWindow(t *i18n.Translator, window time.Duration) string {
+	for _, w := range alertWindows {
+		if w.window == window {
+			return t.T(w.key)
+		}
+	}
+	return window.String()
+}
@@ -0,0 +1,100 @@
+package ui
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"cryptoview/internal/model"
+	"cryptoview/internal/service/alerts"
+	"cryptoview/internal/ui/i18n"
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/test"
+	"fyne.io/fyne/v2/widget"
+)
+
+func TestAlertsWindowAddsThresholdRuleInCurrentFiat(t *testing.T) {
+	a := test.NewApp()
+	defer a.Quit()
+
+	engine := alerts.NewEngine(filepath.Join(t.TempDir(), "alerts.json"))
+	coins := []model.Coin{{ID: "bitcoin", Name: "Bitcoin", Ticker: "BTC", Price: 90, PriceUSD: 100}}
+	aw := showAlertsWindow(a, engine, i18n.NewTranslator(i18n.LangEN), coins, i18n.FiatEUR, nil)
+	defer aw.window.Close()
+
+	aw.coinSelect.SetSelected("BTC | Bitcoin")
+	aw.kindSelect.SetSelected("Price above")
+	aw.value.SetText("100 000")
+	aw.add()
+
+	rules := engine.Rules()
+	if len(rules) != 1 || rules[0].Kind != alerts.KindAbove || rules[0].Threshold != 100000 || rules[0].Fiat != i18n.FiatEUR {
+		t.Fatalf("unexpected rules %+v", rules)
+	}
+	if aw.message.Visible() {
+		t.Fatalf("unexpected error message %q", aw.message.Text)
+	}
+
+	aw.kindSelect.SetSelected("Crosses 24h open")
+	if !aw.value.Disabled() {
+		t.Fatal("expected value entry to be disabled for cross rules")
+	}
+	aw.coinSelect.ClearSelected()
+	aw.add()
+	if !aw.message.Visible() || len(engine.Rules()) != 1 {
+		t.Fatal("expected missing coin to be rejected")
+	}
+}
+
+func TestAlertsWindowMarksRulesWaitingForRate(t *testing.T) {
+	a := test.NewApp()
+	defer a.Quit()
+
+	engine := alerts.NewEngine("")
+	if _, err := engine.AddRule(alerts.Rule{CoinID: "bitcoin", Kind: alerts.KindBelow, Threshold: 5000000, Fiat: i18n.FiatRUB}); err != nil {
+		t.Fatal(err)
+	}
+	coins := []model.Coin{{ID: "bitcoin", Name: "Bitcoin", Ticker: "BTC", Price: 100, PriceUSD: 100}}
+	aw := showAlertsWindow(a, engine, i18n.NewTranslator(i18n.LangEN), coins, i18n.FiatUSD, nil)
+	defer aw.window.Close()
+
+	label := func() string {
+		row := aw.rules.Objects[0].(*fyne.Container)
+		return row.Objects[0].(*widget.Label).Text
+	}
+	if got := label(); !strings.Contains(got, "waiting for the RUB rate") {
+		t.Fatalf("expected waiting marker, got %q", got)
+	}
+	engine.SetRates(map[i18n.FiatCurrency]float64{i18n.FiatRUB: 90})
+	aw.update(coins, i18n.FiatUSD)
+	if got := label(); strings.Contains(got, "waiting") {
+		t.Fatalf("expected marker to clear once the rate is known, got %q", got)
+	}
+}
+
+func TestDescribeAlertFormatsNotificationText(t *testing.T) {
+	translator := i18n.NewTranslator(i18n.LangEN)
+	alert := alerts.Alert{
+		Ticker: "ETH",
+		Kind:   alerts.KindPercentMove,
+		Rule:   alerts.Rule{Kind: alerts.KindPercentMove, Percent: -5, Window: time.Hour},
+		Price:  1900,
+		Fiat:   i18n.FiatUSD,
+		Change: -5.25,
+	}
+	n := alertNotification(translator, alert)
+	if n.Title != "CryptoView alert: ETH" {
+		t.Fatalf("unexpected title %q", n.Title)
+	}
+	if n.Content != "ETH moved -5.25% within 1h (now $1,900.00)" {
+		t.Fatalf("unexpected content %q", n.Content)
+	}
+
+	alert.Kind = alerts.KindCrossOpen
+	alert.Up = true
+	alert.Change = 1.5
+	if got := describeAlert(translator, alert); got != "ETH crossed above its 24h open (now $1,900.00, +1.50%)" {
+		t.Fatalf("unexpected cross description %q", got)
+	}
+}
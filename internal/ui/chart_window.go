@@ -0,0 +1,158 @@
+package ui
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"cryptoview/internal/model"
+	"cryptoview/internal/ui/components"
+	"cryptoview/internal/ui/i18n"
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/widget"
+)
+
+type chartRange struct {
+	key    string
+	days   int
+	window time.Duration
+	candle time.Duration
+}
+
+var chartRanges = []chartRange{
+	{key: "chart.range.1h", days: 1, window: time.Hour, candle: 5 * time.Minute},
+	{key: "chart.range.24h", days: 1, candle: time.Hour},
+	{key: "chart.range.7d", days: 7, candle: 6 * time.Hour},
+	{key: "chart.range.30d", days: 30, candle: 24 * time.Hour},
+	{key: "chart.range.1y", days: 365, candle: 7 * 24 * time.Hour},
+}
+
+const defaultChartRange = 1
+
+type chartWindow struct {
+	window     fyne.Window
+	feed       marketFeed
+	translator *i18n.Translator
+	coin       model.Coin
+	fiat       i18n.FiatCurrency
+
+	chart   *components.PriceChart
+	status  *widget.Label
+	ranges  *widget.RadioGroup
+	modes   *widget.RadioGroup
+	current chartRange
+
+	ctx     context.Context
+	cancel  context.CancelFunc
+	request int
+}
+
+func showChartWindow(a fyne.App, feed marketFeed, translator *i18n.Translator, coin model.Coin, fiat i18n.FiatCurrency) *chartWindow {
+	ctx, cancel := context.WithCancel(context.Background())
+	cw := &chartWindow{
+		window:     a.NewWindow(fmt.Sprintf("%s (%s)", coin.Name, coin.Ticker)),
+		feed:       feed,
+		translator: translator,
+		coin:       coin,
+		fiat:       fiat,
+		chart:      components.NewPriceChart(),
+		status:     widget.NewLabel(""),
+		ctx:        ctx,
+		cancel:     cancel,
+	}
+	lang := translator.Language()
+	cw.chart.SetFormatters(
+		func(v float64) string { return i18n.FormatPrice(v, fiat, lang) },
+		func(t time.Time) string { return i18n.FormatDateTime(t, lang) },
+	)
+
+	rangeLabels := make([]string, len(chartRanges))
+	for i, r := range chartRanges {
+		rangeLabels[i] = translator.T(r.key)
+	}
+	cw.ranges = widget.NewRadioGroup(rangeLabels, func(selected string) {
+		for i, label := range rangeLabels {
+			if label == selected {
+				cw.load(chartRanges[i])
+				return
+			}
+		}
+	})
+	cw.ranges.Horizontal = true
+	cw.ranges.Required = true
+
+	lineLabel, candleLabel := translator.T("chart.mode.line"), translator.T("chart.mode.candles")
+	cw.modes = widget.NewRadioGroup([]string{lineLabel, candleLabel}, func(selected string) {
+		if selected == candleLabel {
+			cw.chart.SetMode(components.ChartModeCandles)
+		} else {
+			cw.chart.SetMode(components.ChartModeLine)
+		}
+	})
+	cw.modes.Horizontal = true
+	cw.modes.Required = true
+	cw.modes.SetSelected(lineLabel)
+
+	title := widget.NewLabel(fmt.Sprintf("%s | %s", coin.Name, coin.Ticker))
+	title.TextStyle = fyne.TextStyle{Bold: true}
+	controls := container.NewVBox(title, cw.ranges, cw.modes)
+	cw.window.SetContent(container.NewBorder(controls, cw.status, nil, nil, cw.chart))
+	cw.window.Resize(fyne.NewSize(560, 380))
+	cw.window.SetOnClosed(cw.cancel)
+
+	cw.ranges.SetSelected(rangeLabels[defaultChartRange])
+	cw.window.Show()
+	return cw
+}
+
+func (cw *chartWindow) load(r chartRange) {
+	cw.current = r
+	cw.request++
+	request := cw.request
+	cw.status.SetText(cw.translator.T("chart.loading"))
+	cw.status.Show()
+
+	go func() {
+		points, err := cw.feed.MarketChart(cw.ctx, cw.coin.ID, cw.fiat, r.days)
+		if cw.ctx.Err() != nil {
+			return
+		}
+		points = trimChartWindow(points, r.window)
+		fyne.Do(func() {
+			if request != cw.request {
+				return
+			}
+			cw.apply(points, err)
+		})
+	}()
+}
+
+func (cw *chartWindow) apply(points []model.PricePoint, err error) {
+	switch {
+	case err != nil:
+		cw.status.SetText(cw.translator.T("chart.error"))
+		cw.chart.SetData(nil, cw.current.candle)
+		return
+	case len(points) == 0:
+		cw.status.SetText(cw.translator.T("chart.empty"))
+		cw.chart.SetData(nil, cw.current.candle)
+		return
+	}
+	cw.status.SetText("")
+	cw.status.Hide()
+	cw.chart.SetData(points, cw.current.candle)
+}
+
+func trimChartWindow(points []model.PricePoint, window time.Duration) []model.PricePoint {
+	if window <= 0 || len(points) == 0 {
+		return points
+	}
+	cutoff := points[len(points)-1].Time.Add(-window)
+	for i, p := range points {
+		if !p.Time.Before(cutoff) {
+			return points[i:]
+		}
+	}
+	return nil
+}
@@ -0,0 +1,72 @@
+package ui
+
+import (
+	"testing"
+	"time"
+
+	"cryptoview/internal/model"
+	"cryptoview/internal/service/marketfeed"
+	"cryptoview/internal/ui/i18n"
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/test"
+)
+
+func TestTrimChartWindowKeepsTrailingRange(t *testing.T) {
+	base := time.Unix(1700000000, 0)
+	points := []model.PricePoint{
+		{Time: base, Price: 1},
+		{Time: base.Add(30 * time.Minute), Price: 2},
+		{Time: base.Add(90 * time.Minute), Price: 3},
+	}
+	got := trimChartWindow(points, time.Hour)
+	if len(got) != 2 || got[0].Price != 2 {
+		t.Fatalf("expected last hour of points, got %+v", got)
+	}
+	if got := trimChartWindow(points, 0); len(got) != 3 {
+		t.Fatalf("expected unbounded window to keep all points, got %d", len(got))
+	}
+}
+
+func TestChartWindowLoadsSelectedRangeInFiat(t *testing.T) {
+	a := test.NewApp()
+	defer a.Quit()
+
+	feed := newFakeFeed(marketfeed.Callbacks{})
+	feed.chartPoints = []model.PricePoint{
+		{Time: time.Unix(1700000000, 0), Price: 90},
+		{Time: time.Unix(1700003600, 0), Price: 95},
+	}
+	coin := model.Coin{ID: "bitcoin", Name: "Bitcoin", Ticker: "BTC"}
+	cw := showChartWindow(a, feed, i18n.NewTranslator(i18n.LangEN), coin, i18n.FiatEUR)
+	defer cw.window.Close()
+
+	waitForChartRequests(t, feed, 1)
+	cw.ranges.SetSelected("7d")
+	waitForChartRequests(t, feed, 2)
+
+	feed.chartMu.Lock()
+	requests := append([]string(nil), feed.chartRequests...)
+	feed.chartMu.Unlock()
+	if requests[0] != "bitcoin/EUR/1" || requests[1] != "bitcoin/EUR/7" {
+		t.Fatalf("unexpected chart requests %v", requests)
+	}
+	if cw.window.Title() != "Bitcoin (BTC)" {
+		t.Fatalf("unexpected chart window title %q", cw.window.Title())
+	}
+}
+
+func waitForChartRequests(t *testing.T, feed *fakeFeed, want int) {
+	t.Helper()
+	deadline := time.Now().Add(2 * time.Second)
+	for time.Now().Before(deadline) {
+		feed.chartMu.Lock()
+		got := len(feed.chartRequests)
+		feed.chartMu.Unlock()
+		if got >= want {
+			fyne.DoAndWait(func() {})
+			return
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+	t.Fatalf("expected %d chart requests", want)
+}
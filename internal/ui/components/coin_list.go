@@ -3,6 +3,8 @@ package components
 import (
 	"fmt"
 	"image/color"
+	"sort"
+	"strings"
 	"sync"
 
 	"cryptoview/internal/model"
@@ -17,15 +19,44 @@ import (
 	"fyne.io/fyne/v2/widget"
 )
 
+// SortOrder is how the coin list orders rows. SortDefault keeps the
+// watchlist order the feed reports.
+type SortOrder string
+
+const (
+	SortDefault SortOrder = "default"
+	SortName    SortOrder = "name"
+	SortPrice   SortOrder = "price"
+	SortChange  SortOrder = "change"
+)
+
+func SortOrders() []SortOrder {
+	return []SortOrder{SortDefault, SortName, SortPrice, SortChange}
+}
+
+func ParseSortOrder(raw string) (SortOrder, bool) {
+	order := SortOrder(strings.ToLower(strings.TrimSpace(raw)))
+	for _, known := range SortOrders() {
+		if order == known {
+			return order, true
+		}
+	}
+	return "", false
+}
+
 type CoinListController struct {
 	list       *widget.List
+	source     []model.Coin
 	data       []model.Coin
+	sortOrder  SortOrder
 	currency   i18n.FiatCurrency
 	language   i18n.AppLanguage
 	translator *i18n.Translator
 	mu         sync.RWMutex
 	icons      map[string]fyne.Resource
 	tickerW    float32
+	history    func(id string) []float64
+	onChart    func(model.Coin)
 }
 
 func NewCoinList(data []model.Coin, translator *i18n.Translator) *CoinListController {
@@ -33,7 +64,9 @@ func NewCoinList(data []model.Coin, translator *i18n.Translator) *CoinListContro
 		translator = i18n.NewTranslator(i18n.LangEN)
 	}
 	controller := &CoinListController{
+		source:     data,
 		data:       data,
+		sortOrder:  SortDefault,
 		currency:   i18n.FiatUSD,
 		language:   translator.Language(),
 		translator: translator,
@@ -61,15 +94,25 @@ func NewCoinList(data []model.Coin, translator *i18n.Translator) *CoinListContro
 			language := controller.language
 			tickerW := controller.tickerW
 			isLast := int(id) == len(controller.data)-1
+			history := controller.history
+			onChart := controller.onChart
 			controller.mu.RUnlock()
 
+			var points []float64
+			if history != nil {
+				points = history(coin.ID)
+			}
+
 			row := item.(*coinListItem)
+			row.coin = coin
+			row.onChart = onChart
 			row.applyCoin(
 				coin,
-				i18n.FormatPrice(coin.Price, currency, language),
+				i18n.FormatQuote(coin.Price, currency, language, coin.Converted),
 				i18n.FormatTime(coin.LastUpdateTime, language),
 				changeColor(coin.Change24h),
 				controller.iconForCoin(coin),
+				points,
 				tickerW,
 				isLast,
 			)
@@ -103,9 +146,44 @@ func (c *CoinListController) SetLanguage(language i18n.AppLanguage) {
 	})
 }
 
+func (c *CoinListController) SetHistoryProvider(history func(id string) []float64) {
+	c.mu.Lock()
+	c.history = history
+	c.mu.Unlock()
+	fyne.Do(func() {
+		c.list.Refresh()
+	})
+}
+
+func (c *CoinListController) SetOnChartRequested(onChart func(model.Coin)) {
+	c.mu.Lock()
+	c.onChart = onChart
+	c.mu.Unlock()
+}
+
+func (c *CoinListController) SetSortOrder(order SortOrder) {
+	if _, ok := ParseSortOrder(string(order)); !ok {
+		return
+	}
+	c.mu.Lock()
+	c.sortOrder = order
+	c.data = sortCoins(c.source, order)
+	c.mu.Unlock()
+	fyne.Do(func() {
+		c.list.Refresh()
+	})
+}
+
+func (c *CoinListController) SortOrder() SortOrder {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.sortOrder
+}
+
 func (c *CoinListController) ReplaceData(coins []model.Coin) {
 	c.mu.Lock()
-	c.data = coins
+	c.source = coins
+	c.data = sortCoins(coins, c.sortOrder)
 	c.tickerW = maxTickerWidth(coins)
 	c.mu.Unlock()
 	fyne.Do(func() {
@@ -113,6 +191,25 @@ func (c *CoinListController) ReplaceData(coins []model.Coin) {
 	})
 }
 
+func sortCoins(coins []model.Coin, order SortOrder) []model.Coin {
+	if order == SortDefault || len(coins) < 2 {
+		return coins
+	}
+	sorted := make([]model.Coin, len(coins))
+	copy(sorted, coins)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		switch order {
+		case SortName:
+			return strings.ToLower(sorted[i].Name) < strings.ToLower(sorted[j].Name)
+		case SortPrice:
+			return sorted[i].PriceUSD > sorted[j].PriceUSD
+		default:
+			return sorted[i].Change24h > sorted[j].Change24h
+		}
+	})
+	return sorted
+}
+
 func (c *CoinListController) iconForCoin(coin model.Coin) fyne.Resource {
 	if coin.IconPath == "" {
 		return nil
@@ -175,8 +272,11 @@ type coinListItem struct {
 	name      *widget.Label
 	price     *widget.Label
 	change    *canvas.Text
-	chartIcon *widget.Icon
+	sparkline *Sparkline
 	separator *widget.Separator
+
+	coin    model.Coin
+	onChart func(model.Coin)
 }
 
 func newCoinListItem() *coinListItem {
@@ -202,7 +302,7 @@ func newCoinListItem() *coinListItem {
 	change.TextStyle = fyne.TextStyle{Bold: true}
 	change.TextSize = theme.TextSize()
 
-	chartIcon := widget.NewIcon(theme.NewThemedResource(theme.ListIcon()))
+	sparkline := NewSparkline()
 
 	mainInfo := container.NewHBox(
 		ticker,
@@ -221,7 +321,7 @@ func newCoinListItem() *coinListItem {
 		spacerX(8),
 		container.NewCenter(change),
 		spacerX(8),
-		container.NewCenter(chartIcon),
+		container.NewCenter(sparkline),
 	)
 	separator := widget.NewSeparator()
 	content := container.NewVBox(container.NewPadded(container.NewPadded(row)), separator)
@@ -235,9 +335,14 @@ func newCoinListItem() *coinListItem {
 		name:      name,
 		price:     price,
 		change:    change,
-		chartIcon: chartIcon,
+		sparkline: sparkline,
 		separator: separator,
 	}
+	sparkline.OnTapped = func() {
+		if item.onChart != nil {
+			item.onChart(item.coin)
+		}
+	}
 	item.ExtendBaseWidget(item)
 	return item
 }
@@ -252,6 +357,7 @@ func (i *coinListItem) applyCoin(
 	formattedTime string,
 	changeColor color.Color,
 	iconResource fyne.Resource,
+	history []float64,
 	tickerW float32,
 	isLast bool,
 ) {
@@ -274,6 +380,7 @@ func (i *coinListItem) applyCoin(
 	i.change.Text = fmt.Sprintf("%+.2f%%", coin.Change24h)
 	i.change.Color = changeColor
 	i.change.Refresh()
+	i.sparkline.SetData(history, changeColor)
 
 	if iconResource != nil {
 		i.icon.Resource = iconResource
@@ -7,6 +7,7 @@ import (
 
 	"cryptoview/internal/model"
 	"cryptoview/internal/ui/i18n"
+	"fyne.io/fyne/v2"
 	"fyne.io/fyne/v2/test"
 )
 
@@ -116,7 +117,85 @@ func TestReplaceData(t *testing.T) {
 	}
 }
 
+func TestCoinListSortOrderSurvivesReplaceData(t *testing.T) {
+	a := test.NewApp()
+	defer a.Quit()
+
+	controller := NewCoinList(nil, i18n.NewTranslator(i18n.LangEN))
+	controller.SetSortOrder(SortChange)
+	controller.ReplaceData([]model.Coin{
+		{ID: "bitcoin", Name: "Bitcoin", PriceUSD: 50000, Change24h: 1},
+		{ID: "ethereum", Name: "Ethereum", PriceUSD: 2000, Change24h: 4},
+		{ID: "cardano", Name: "Cardano", PriceUSD: 0.5, Change24h: -2},
+	})
+
+	ids := func() string {
+		controller.mu.RLock()
+		defer controller.mu.RUnlock()
+		out := make([]string, 0, len(controller.data))
+		for _, coin := range controller.data {
+			out = append(out, coin.ID)
+		}
+		return strings.Join(out, ",")
+	}
+	if got := ids(); got != "ethereum,bitcoin,cardano" {
+		t.Fatalf("expected rows by 24h change, got %s", got)
+	}
+	controller.SetSortOrder(SortName)
+	if got := ids(); got != "bitcoin,cardano,ethereum" {
+		t.Fatalf("expected rows by name, got %s", got)
+	}
+	controller.SetSortOrder(SortDefault)
+	if got := ids(); got != "bitcoin,ethereum,cardano" {
+		t.Fatalf("expected watchlist order, got %s", got)
+	}
+	if _, ok := ParseSortOrder("volume"); ok {
+		t.Fatalf("expected unknown sort order to be rejected")
+	}
+}
+
 func asNRGBA(c color.Color) color.NRGBA {
 	r, g, b, a := c.RGBA()
 	return color.NRGBA{R: uint8(r >> 8), G: uint8(g >> 8), B: uint8(b >> 8), A: uint8(a >> 8)}
 }
+
+func TestCoinListRendersHistorySparkline(t *testing.T) {
+	a := test.NewApp()
+	defer a.Quit()
+
+	controller := NewCoinList(model.GetMockCoins(), i18n.NewTranslator(i18n.LangEN))
+	var requested string
+	controller.SetHistoryProvider(func(id string) []float64 {
+		requested = id
+		return []float64{1, 3, 2}
+	})
+	item := controller.Widget().CreateItem()
+	row := item.(*coinListItem)
+	controller.Widget().UpdateItem(0, item)
+
+	if requested != model.GetMockCoins()[0].ID {
+		t.Fatalf("expected history lookup for first coin, got %q", requested)
+	}
+	if len(row.sparkline.values) != 3 {
+		t.Fatalf("expected sparkline to receive history, got %v", row.sparkline.values)
+	}
+	if row.sparkline.color != changeColor(model.GetMockCoins()[0].Change24h) {
+		t.Fatalf("expected sparkline colour to follow change, got %v", row.sparkline.color)
+	}
+}
+
+func TestSparklinePointsScaleToBounds(t *testing.T) {
+	points := sparklinePoints([]float64{10, 20, 15}, fyne.NewSize(40, 20))
+	if len(points) != 3 {
+		t.Fatalf("expected one point per value, got %d", len(points))
+	}
+	if points[0].X != 0 || points[2].X != 40 {
+		t.Fatalf("expected points to span width, got %+v", points)
+	}
+	if points[1].Y >= points[0].Y {
+		t.Fatalf("expected higher price to be drawn higher, got %+v", points)
+	}
+	if got := downsample(make([]float64, 200), sparklineMaxPoints); len(got) != sparklineMaxPoints {
+		t.Fatalf("expected downsample to %d points, got %d", sparklineMaxPoints, len(got))
+	}
+}
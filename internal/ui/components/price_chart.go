@@ -0,0 +1,374 @@
+package components
+
+import (
+	"fmt"
+	"image/color"
+	"math"
+	"time"
+
+	"cryptoview/internal/model"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/canvas"
+	"fyne.io/fyne/v2/driver/desktop"
+	"fyne.io/fyne/v2/theme"
+	"fyne.io/fyne/v2/widget"
+)
+
+type ChartMode int
+
+const (
+	ChartModeLine ChartMode = iota
+	ChartModeCandles
+)
+
+const (
+	chartPadding     = 8
+	chartLabelHeight = 16
+	chartMinWidth    = 320
+	chartMinHeight   = 200
+)
+
+type PriceChart struct {
+	widget.BaseWidget
+
+	points      []model.PricePoint
+	candles     []model.Candle
+	mode        ChartMode
+	lineColor   color.Color
+	formatPrice func(float64) string
+	formatTime  func(time.Time) string
+
+	hoverIndex int
+	version    int
+}
+
+func NewPriceChart() *PriceChart {
+	c := &PriceChart{
+		lineColor:  theme.Color(theme.ColorNamePrimary),
+		hoverIndex: -1,
+		formatPrice: func(v float64) string {
+			return fmt.Sprintf("%.2f", v)
+		},
+		formatTime: func(t time.Time) string {
+			return t.Local().Format("2006-01-02 15:04")
+		},
+	}
+	c.ExtendBaseWidget(c)
+	return c
+}
+
+func (c *PriceChart) SetData(points []model.PricePoint, candleInterval time.Duration) {
+	c.points = points
+	c.candles = model.BuildCandles(points, candleInterval)
+	if len(points) > 1 {
+		c.lineColor = changeColor(points[len(points)-1].Price - points[0].Price)
+	}
+	c.hoverIndex = -1
+	c.version++
+	c.Refresh()
+}
+
+func (c *PriceChart) SetMode(mode ChartMode) {
+	if c.mode == mode {
+		return
+	}
+	c.mode = mode
+	c.hoverIndex = -1
+	c.version++
+	c.Refresh()
+}
+
+func (c *PriceChart) Mode() ChartMode {
+	return c.mode
+}
+
+func (c *PriceChart) SetFormatters(price func(float64) string, when func(time.Time) string) {
+	if price != nil {
+		c.formatPrice = price
+	}
+	if when != nil {
+		c.formatTime = when
+	}
+	c.version++
+	c.Refresh()
+}
+
+func (c *PriceChart) MinSize() fyne.Size {
+	return fyne.NewSize(chartMinWidth, chartMinHeight)
+}
+
+func (c *PriceChart) MouseIn(event *desktop.MouseEvent) {
+	c.MouseMoved(event)
+}
+
+func (c *PriceChart) MouseMoved(event *desktop.MouseEvent) {
+	idx := c.indexAt(event.Position.X, c.Size())
+	if idx == c.hoverIndex {
+		return
+	}
+	c.hoverIndex = idx
+	c.Refresh()
+}
+
+func (c *PriceChart) MouseOut() {
+	if c.hoverIndex == -1 {
+		return
+	}
+	c.hoverIndex = -1
+	c.Refresh()
+}
+
+func (c *PriceChart) CreateRenderer() fyne.WidgetRenderer {
+	r := &priceChartRenderer{
+		chart:     c,
+		maxLabel:  canvas.NewText("", theme.Color(theme.ColorNamePlaceHolder)),
+		minLabel:  canvas.NewText("", theme.Color(theme.ColorNamePlaceHolder)),
+		crossV:    canvas.NewLine(theme.Color(theme.ColorNamePlaceHolder)),
+		crossH:    canvas.NewLine(theme.Color(theme.ColorNamePlaceHolder)),
+		marker:    canvas.NewCircle(theme.Color(theme.ColorNamePrimary)),
+		tipBg:     canvas.NewRectangle(theme.Color(theme.ColorNameOverlayBackground)),
+		tipText:   canvas.NewText("", theme.Color(theme.ColorNameForeground)),
+		builtWith: -1,
+	}
+	r.maxLabel.TextSize = theme.CaptionTextSize()
+	r.minLabel.TextSize = theme.CaptionTextSize()
+	r.tipText.TextSize = theme.CaptionTextSize()
+	r.tipBg.CornerRadius = 4
+	r.tipBg.StrokeColor = theme.Color(theme.ColorNameSeparator)
+	r.tipBg.StrokeWidth = 1
+	r.Refresh()
+	return r
+}
+
+type chartBar struct {
+	open, high, low, close float64
+	at                     time.Time
+}
+
+func (c *PriceChart) series() []chartBar {
+	if c.mode == ChartModeCandles && len(c.candles) > 0 {
+		bars := make([]chartBar, len(c.candles))
+		for i, candle := range c.candles {
+			bars[i] = chartBar{open: candle.Open, high: candle.High, low: candle.Low, close: candle.Close, at: candle.Time}
+		}
+		return bars
+	}
+	bars := make([]chartBar, len(c.points))
+	for i, p := range c.points {
+		bars[i] = chartBar{open: p.Price, high: p.Price, low: p.Price, close: p.Price, at: p.Time}
+	}
+	return bars
+}
+
+func (c *PriceChart) plotArea(size fyne.Size) (fyne.Position, fyne.Size) {
+	origin := fyne.NewPos(chartPadding, chartPadding+chartLabelHeight)
+	plot := fyne.NewSize(size.Width-2*chartPadding, size.Height-2*chartPadding-2*chartLabelHeight)
+	if plot.Width < 1 {
+		plot.Width = 1
+	}
+	if plot.Height < 1 {
+		plot.Height = 1
+	}
+	return origin, plot
+}
+
+func (c *PriceChart) barX(i, n int, plotWidth float32) float32 {
+	if c.mode == ChartModeCandles {
+		slot := plotWidth / float32(n)
+		return slot*float32(i) + slot/2
+	}
+	if n == 1 {
+		return plotWidth / 2
+	}
+	return plotWidth * float32(i) / float32(n-1)
+}
+
+func (c *PriceChart) indexAt(x float32, size fyne.Size) int {
+	n := len(c.series())
+	if n == 0 {
+		return -1
+	}
+	origin, plot := c.plotArea(size)
+	rel := (x - origin.X) / plot.Width
+	if rel < 0 {
+		rel = 0
+	}
+	if rel > 1 {
+		rel = 1
+	}
+	var idx int
+	if c.mode == ChartModeCandles {
+		idx = int(rel * float32(n))
+	} else {
+		idx = int(math.Round(float64(rel * float32(n-1))))
+	}
+	if idx >= n {
+		idx = n - 1
+	}
+	return idx
+}
+
+func priceBounds(bars []chartBar) (float64, float64) {
+	lo, hi := math.Inf(1), math.Inf(-1)
+	for _, b := range bars {
+		lo = math.Min(lo, b.low)
+		hi = math.Max(hi, b.high)
+	}
+	if hi <= lo {
+		pad := math.Max(math.Abs(hi)*0.01, 1e-9)
+		lo, hi = lo-pad, hi+pad
+	}
+	return lo, hi
+}
+
+type priceChartRenderer struct {
+	chart *PriceChart
+
+	series   []fyne.CanvasObject
+	maxLabel *canvas.Text
+	minLabel *canvas.Text
+	crossV   *canvas.Line
+	crossH   *canvas.Line
+	marker   *canvas.Circle
+	tipBg    *canvas.Rectangle
+	tipText  *canvas.Text
+
+	builtWith int
+	builtSize fyne.Size
+	objects   []fyne.CanvasObject
+}
+
+func (r *priceChartRenderer) Layout(size fyne.Size) {
+	r.rebuild(size)
+}
+
+func (r *priceChartRenderer) MinSize() fyne.Size {
+	return r.chart.MinSize()
+}
+
+func (r *priceChartRenderer) Refresh() {
+	r.rebuild(r.chart.Size())
+	canvas.Refresh(r.chart)
+}
+
+func (r *priceChartRenderer) Objects() []fyne.CanvasObject {
+	return r.objects
+}
+
+func (r *priceChartRenderer) Destroy() {}
+
+func (r *priceChartRenderer) rebuild(size fyne.Size) {
+	if size.Width <= 0 || size.Height <= 0 {
+		size = r.chart.MinSize()
+	}
+	bars := r.chart.series()
+	if r.builtWith != r.chart.version || r.builtSize != size {
+		r.buildSeries(bars, size)
+		r.builtWith = r.chart.version
+		r.builtSize = size
+	}
+	r.layoutHover(bars, size)
+
+	r.objects = r.objects[:0]
+	r.objects = append(r.objects, r.series...)
+	r.objects = append(r.objects, r.maxLabel, r.minLabel, r.crossV, r.crossH, r.marker, r.tipBg, r.tipText)
+}
+
+func (r *priceChartRenderer) buildSeries(bars []chartBar, size fyne.Size) {
+	r.series = r.series[:0]
+	r.maxLabel.Text, r.minLabel.Text = "", ""
+	if len(bars) == 0 {
+		r.maxLabel.Refresh()
+		r.minLabel.Refresh()
+		return
+	}
+	c := r.chart
+	origin, plot := c.plotArea(size)
+	lo, hi := priceBounds(bars)
+	yOf := func(v float64) float32 {
+		return origin.Y + plot.Height*float32((hi-v)/(hi-lo))
+	}
+
+	if c.mode == ChartModeCandles {
+		slot := plot.Width / float32(len(bars))
+		bodyW := float32(math.Max(1, float64(slot*0.7)))
+		for i, b := range bars {
+			x := origin.X + c.barX(i, len(bars), plot.Width)
+			col := changeColor(b.close - b.open)
+			wick := canvas.NewLine(col)
+			wick.StrokeWidth = 1
+			wick.Position1 = fyne.NewPos(x, yOf(b.high))
+			wick.Position2 = fyne.NewPos(x, yOf(b.low))
+			top, bottom := yOf(math.Max(b.open, b.close)), yOf(math.Min(b.open, b.close))
+			body := canvas.NewRectangle(col)
+			body.Move(fyne.NewPos(x-bodyW/2, top))
+			body.Resize(fyne.NewSize(bodyW, float32(math.Max(1, float64(bottom-top)))))
+			r.series = append(r.series, wick, body)
+		}
+	} else {
+		for i := 1; i < len(bars); i++ {
+			line := canvas.NewLine(c.lineColor)
+			line.StrokeWidth = 1.5
+			line.Position1 = fyne.NewPos(origin.X+c.barX(i-1, len(bars), plot.Width), yOf(bars[i-1].close))
+			line.Position2 = fyne.NewPos(origin.X+c.barX(i, len(bars), plot.Width), yOf(bars[i].close))
+			r.series = append(r.series, line)
+		}
+	}
+
+	r.maxLabel.Text = c.formatPrice(hi)
+	r.maxLabel.Move(fyne.NewPos(chartPadding, chartPadding))
+	r.minLabel.Text = c.formatPrice(lo)
+	r.minLabel.Move(fyne.NewPos(chartPadding, origin.Y+plot.Height+2))
+	r.maxLabel.Refresh()
+	r.minLabel.Refresh()
+}
+
+func (r *priceChartRenderer) layoutHover(bars []chartBar, size fyne.Size) {
+	c := r.chart
+	idx := c.hoverIndex
+	if idx < 0 || idx >= len(bars) {
+		r.crossV.Hide()
+		r.crossH.Hide()
+		r.marker.Hide()
+		r.tipBg.Hide()
+		r.tipText.Hide()
+		return
+	}
+	origin, plot := c.plotArea(size)
+	lo, hi := priceBounds(bars)
+	bar := bars[idx]
+	x := origin.X + c.barX(idx, len(bars), plot.Width)
+	y := origin.Y + plot.Height*float32((hi-bar.close)/(hi-lo))
+
+	r.crossV.Position1 = fyne.NewPos(x, origin.Y)
+	r.crossV.Position2 = fyne.NewPos(x, origin.Y+plot.Height)
+	r.crossH.Position1 = fyne.NewPos(origin.X, y)
+	r.crossH.Position2 = fyne.NewPos(origin.X+plot.Width, y)
+	r.marker.FillColor = c.lineColor
+	r.marker.Move(fyne.NewPos(x-3, y-3))
+	r.marker.Resize(fyne.NewSize(6, 6))
+
+	r.tipText.Text = c.formatPrice(bar.close) + " • " + c.formatTime(bar.at)
+	textSize := r.tipText.MinSize()
+	tipSize := fyne.NewSize(textSize.Width+8, textSize.Height+4)
+	tipX := x + 8
+	if tipX+tipSize.Width > size.Width-chartPadding {
+		tipX = x - 8 - tipSize.Width
+	}
+	if tipX < 0 {
+		tipX = 0
+	}
+	tipY := y - tipSize.Height - 6
+	if tipY < 0 {
+		tipY = y + 6
+	}
+	r.tipBg.Move(fyne.NewPos(tipX, tipY))
+	r.tipBg.Resize(tipSize)
+	r.tipText.Move(fyne.NewPos(tipX+4, tipY+2))
+
+	for _, obj := range []fyne.CanvasObject{r.crossV, r.crossH, r.marker, r.tipBg, r.tipText} {
+		obj.Show()
+		obj.Refresh()
+	}
+}
@@ -0,0 +1,67 @@
+package components
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"cryptoview/internal/model"
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/driver/desktop"
+	"fyne.io/fyne/v2/test"
+)
+
+func chartTestPoints() []model.PricePoint {
+	base := time.Unix(1700000000, 0).Truncate(time.Hour)
+	prices := []float64{100, 104, 98, 102, 110}
+	points := make([]model.PricePoint, len(prices))
+	for i, p := range prices {
+		points[i] = model.PricePoint{Time: base.Add(time.Duration(i) * 30 * time.Minute), Price: p}
+	}
+	return points
+}
+
+func TestPriceChartHoverShowsTooltip(t *testing.T) {
+	a := test.NewApp()
+	defer a.Quit()
+
+	chart := NewPriceChart()
+	chart.SetFormatters(func(v float64) string { return fmt.Sprintf("P%.0f", v) }, func(time.Time) string { return "T" })
+	chart.SetData(chartTestPoints(), time.Hour)
+	chart.Resize(fyne.NewSize(400, 240))
+	renderer := test.TempWidgetRenderer(t, chart).(*priceChartRenderer)
+
+	if renderer.tipText.Visible() {
+		t.Fatal("expected tooltip hidden before hover")
+	}
+	chart.MouseMoved(&desktop.MouseEvent{PointEvent: fyne.PointEvent{Position: fyne.NewPos(392, 100)}})
+	if chart.hoverIndex != 4 {
+		t.Fatalf("expected hover on last point, got %d", chart.hoverIndex)
+	}
+	if !renderer.tipText.Visible() || renderer.tipText.Text != "P110 • T" {
+		t.Fatalf("expected tooltip with price and time, got %q", renderer.tipText.Text)
+	}
+	chart.MouseOut()
+	if renderer.tipText.Visible() {
+		t.Fatal("expected tooltip hidden after mouse out")
+	}
+}
+
+func TestPriceChartCandleModeUsesBuckets(t *testing.T) {
+	a := test.NewApp()
+	defer a.Quit()
+
+	chart := NewPriceChart()
+	chart.SetData(chartTestPoints(), time.Hour)
+	chart.SetMode(ChartModeCandles)
+	if got := len(chart.series()); got != 3 {
+		t.Fatalf("expected 3 hourly candles, got %d", got)
+	}
+	if got := chart.indexAt(0, fyne.NewSize(300, 200)); got != 0 {
+		t.Fatalf("expected left edge to hit first candle, got %d", got)
+	}
+	chart.SetMode(ChartModeLine)
+	if got := len(chart.series()); got != 5 {
+		t.Fatalf("expected raw points in line mode, got %d", got)
+	}
+}
@@ -0,0 +1,153 @@
+package components
+
+import (
+	"image/color"
+	"math"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/canvas"
+	"fyne.io/fyne/v2/driver/desktop"
+	"fyne.io/fyne/v2/widget"
+)
+
+const (
+	sparklineWidth     = 56
+	sparklineHeight    = 22
+	sparklineMaxPoints = 48
+	sparklineStroke    = 1.5
+)
+
+type Sparkline struct {
+	widget.BaseWidget
+
+	values []float64
+	color  color.Color
+
+	OnTapped func()
+}
+
+func NewSparkline() *Sparkline {
+	s := &Sparkline{color: color.NRGBA{R: 128, G: 128, B: 128, A: 255}}
+	s.ExtendBaseWidget(s)
+	return s
+}
+
+func (s *Sparkline) SetData(values []float64, lineColor color.Color) {
+	s.values = downsample(values, sparklineMaxPoints)
+	if lineColor != nil {
+		s.color = lineColor
+	}
+	s.Refresh()
+}
+
+func (s *Sparkline) Tapped(*fyne.PointEvent) {
+	if s.OnTapped != nil {
+		s.OnTapped()
+	}
+}
+
+func (s *Sparkline) Cursor() desktop.Cursor {
+	if s.OnTapped != nil {
+		return desktop.PointerCursor
+	}
+	return desktop.DefaultCursor
+}
+
+func (s *Sparkline) MinSize() fyne.Size {
+	return fyne.NewSize(sparklineWidth, sparklineHeight)
+}
+
+func (s *Sparkline) CreateRenderer() fyne.WidgetRenderer {
+	r := &sparklineRenderer{spark: s}
+	r.rebuild(s.Size())
+	return r
+}
+
+type sparklineRenderer struct {
+	spark   *Sparkline
+	lines   []*canvas.Line
+	objects []fyne.CanvasObject
+}
+
+func (r *sparklineRenderer) Layout(size fyne.Size) {
+	r.rebuild(size)
+}
+
+func (r *sparklineRenderer) MinSize() fyne.Size {
+	return r.spark.MinSize()
+}
+
+func (r *sparklineRenderer) Refresh() {
+	r.rebuild(r.spark.Size())
+	canvas.Refresh(r.spark)
+}
+
+func (r *sparklineRenderer) Objects() []fyne.CanvasObject {
+	return r.objects
+}
+
+func (r *sparklineRenderer) Destroy() {}
+
+func (r *sparklineRenderer) rebuild(size fyne.Size) {
+	points := sparklinePoints(r.spark.values, size)
+	segments := len(points) - 1
+	if segments < 0 {
+		segments = 0
+	}
+	for len(r.lines) < segments {
+		line := canvas.NewLine(r.spark.color)
+		line.StrokeWidth = sparklineStroke
+		r.lines = append(r.lines, line)
+	}
+	r.objects = r.objects[:0]
+	for i := 0; i < segments; i++ {
+		line := r.lines[i]
+		line.StrokeColor = r.spark.color
+		line.Position1 = points[i]
+		line.Position2 = points[i+1]
+		r.objects = append(r.objects, line)
+	}
+}
+
+func sparklinePoints(values []float64, size fyne.Size) []fyne.Position {
+	if size.Width <= 0 || size.Height <= 0 {
+		size = fyne.NewSize(sparklineWidth, sparklineHeight)
+	}
+	if len(values) == 0 {
+		return nil
+	}
+	if len(values) == 1 {
+		values = []float64{values[0], values[0]}
+	}
+	lo, hi := math.Inf(1), math.Inf(-1)
+	for _, v := range values {
+		lo = math.Min(lo, v)
+		hi = math.Max(hi, v)
+	}
+	pad := float32(sparklineStroke)
+	height := size.Height - 2*pad
+	step := size.Width / float32(len(values)-1)
+	points := make([]fyne.Position, len(values))
+	for i, v := range values {
+		y := size.Height / 2
+		if hi > lo {
+			y = pad + height*float32((hi-v)/(hi-lo))
+		}
+		points[i] = fyne.NewPos(step*float32(i), y)
+	}
+	return points
+}
+
+func downsample(values []float64, max int) []float64 {
+	if len(values) <= max || max < 2 {
+		out := make([]float64, len(values))
+		copy(out, values)
+		return out
+	}
+	out := make([]float64, max)
+	last := len(values) - 1
+	for i := range out {
+		out[i] = values[i*last/(max-1)]
+	}
+	return out
+}
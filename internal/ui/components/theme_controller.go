@@ -27,14 +27,14 @@ func (c *ThemeController) Toggle() {
 	switch c.mode {
 	case uitheme.ModeSystem:
 		if c.currentVariant() == theme.VariantDark {
-			c.setMode(uitheme.ModeLight)
+			c.SetMode(uitheme.ModeLight)
 		} else {
-			c.setMode(uitheme.ModeDark)
+			c.SetMode(uitheme.ModeDark)
 		}
 	case uitheme.ModeDark:
-		c.setMode(uitheme.ModeLight)
+		c.SetMode(uitheme.ModeLight)
 	default:
-		c.setMode(uitheme.ModeDark)
+		c.SetMode(uitheme.ModeDark)
 	}
 }
 
@@ -52,7 +52,7 @@ func (c *ThemeController) ActionIconResource() fyne.Resource {
 	return sunIconResource
 }
 
-func (c *ThemeController) setMode(mode uitheme.Mode) {
+func (c *ThemeController) SetMode(mode uitheme.Mode) {
 	c.mode = mode
 	c.app.Settings().SetTheme(uitheme.NewForMode(mode))
 }
@@ -3,6 +3,7 @@ package components
 import (
 	"cryptoview/internal/ui/assets"
 	"cryptoview/internal/ui/i18n"
+	uitheme "cryptoview/internal/ui/theme"
 
 	"fyne.io/fyne/v2"
 	"fyne.io/fyne/v2/canvas"
@@ -18,6 +19,12 @@ type Toolbar struct {
 	themeControl   *ThemeController
 	currencySelect *widget.Select
 	langSelect     *widget.Select
+	menuButton     *widget.Button
+	menuItems      func() []*fyne.MenuItem
+	refreshButton  *widget.Button
+	refreshSpinner *widget.Activity
+	refreshWrap    *fyne.Container
+	onRefresh      func()
 	translator     *i18n.Translator
 }
 
@@ -41,8 +48,13 @@ func NewToolbar(
 	logo := widget.NewIcon(logoResource)
 	logoWrap := container.NewGridWrap(fyne.NewSize(28, 28), logo)
 
+	currencies := i18n.Currencies()
+	currencyOptions := make([]string, 0, len(currencies))
+	for _, info := range currencies {
+		currencyOptions = append(currencyOptions, string(info.Code))
+	}
 	currencySelect := widget.NewSelect(
-		[]string{string(i18n.FiatUSD), string(i18n.FiatEUR), string(i18n.FiatRUB)},
+		currencyOptions,
 		func(selected string) {
 			if onCurrencyChanged == nil {
 				return
@@ -79,12 +91,7 @@ func NewToolbar(
 	themeButton.SetIcon(themeControl.ActionIconResource())
 	themeButtonWrap := container.NewGridWrap(fyne.NewSize(56, 40), themeButton)
 
-	left := container.NewHBox(logoWrap, title)
-	right := container.NewHBox(currencySelect, langSelect, themeButtonWrap)
-	header := container.NewBorder(nil, canvas.NewLine(theme.Color(theme.ColorNameSeparator)), left, right)
-
-	return &Toolbar{
-		root:           header,
+	toolbar := &Toolbar{
 		title:          title,
 		themeButton:    themeButton,
 		themeControl:   themeControl,
@@ -92,6 +99,82 @@ func NewToolbar(
 		langSelect:     langSelect,
 		translator:     translator,
 	}
+	toolbar.menuButton = widget.NewButtonWithIcon("", theme.MenuIcon(), toolbar.showMenu)
+	toolbar.menuButton.Importance = widget.LowImportance
+	toolbar.menuButton.Hide()
+	toolbar.refreshButton = widget.NewButtonWithIcon("", theme.ViewRefreshIcon(), func() {
+		if toolbar.onRefresh != nil {
+			toolbar.onRefresh()
+		}
+	})
+	toolbar.refreshButton.Importance = widget.LowImportance
+	toolbar.refreshSpinner = widget.NewActivity()
+	toolbar.refreshSpinner.Hide()
+	toolbar.refreshWrap = container.NewGridWrap(fyne.NewSize(40, 40), container.NewStack(toolbar.refreshButton, container.NewCenter(toolbar.refreshSpinner)))
+	toolbar.refreshWrap.Hide()
+
+	left := container.NewHBox(logoWrap, title)
+	right := container.NewHBox(currencySelect, langSelect, toolbar.refreshWrap, themeButtonWrap, toolbar.menuButton)
+	toolbar.root = container.NewBorder(nil, canvas.NewLine(theme.Color(theme.ColorNameSeparator)), left, right)
+	return toolbar
+}
+
+func (t *Toolbar) SetMenu(items func() []*fyne.MenuItem) {
+	t.menuItems = items
+	if items == nil {
+		t.menuButton.Hide()
+		return
+	}
+	t.menuButton.Show()
+}
+
+func (t *Toolbar) MenuButton() *widget.Button {
+	return t.menuButton
+}
+
+func (t *Toolbar) SetOnRefresh(fn func()) {
+	t.onRefresh = fn
+	if fn == nil {
+		t.refreshWrap.Hide()
+		return
+	}
+	t.refreshWrap.Show()
+}
+
+func (t *Toolbar) SetRefreshing(refreshing bool) {
+	if refreshing {
+		t.refreshButton.Hide()
+		t.refreshSpinner.Show()
+		t.refreshSpinner.Start()
+		return
+	}
+	t.refreshSpinner.Stop()
+	t.refreshSpinner.Hide()
+	t.refreshButton.Show()
+}
+
+func (t *Toolbar) Refreshing() bool {
+	return t.refreshSpinner.Visible()
+}
+
+func (t *Toolbar) RefreshButton() *widget.Button {
+	return t.refreshButton
+}
+
+func (t *Toolbar) showMenu() {
+	if t.menuItems == nil {
+		return
+	}
+	items := t.menuItems()
+	if len(items) == 0 {
+		return
+	}
+	canvasObj := fyne.CurrentApp().Driver().CanvasForObject(t.menuButton)
+	if canvasObj == nil {
+		return
+	}
+	pos := fyne.NewPos(0, t.menuButton.Size().Height)
+	widget.ShowPopUpMenuAtRelativePosition(fyne.NewMenu("", items...), canvasObj, pos, t.menuButton)
 }
 
 func (t *Toolbar) CanvasObject() fyne.CanvasObject {
@@ -106,6 +189,11 @@ func (t *Toolbar) ThemeMode() string {
 	return string(t.themeControl.Mode())
 }
 
+func (t *Toolbar) SetThemeMode(mode uitheme.Mode) {
+	t.themeControl.SetMode(mode)
+	t.themeButton.SetIcon(t.themeControl.ActionIconResource())
+}
+
 func (t *Toolbar) CurrencySelect() *widget.Select {
 	return t.currencySelect
 }
@@ -4,6 +4,7 @@ import (
 	"testing"
 
 	"cryptoview/internal/ui/i18n"
+	"fyne.io/fyne/v2"
 	"fyne.io/fyne/v2/test"
 )
 
@@ -56,3 +57,42 @@ func TestToolbarThemeButtonTogglesTheme(t *testing.T) {
 		t.Fatal("expected theme mode to leave system after first toggle")
 	}
 }
+
+func TestToolbarMenuButtonVisibleOnlyWithMenu(t *testing.T) {
+	a := test.NewApp()
+	defer a.Quit()
+
+	toolbar := NewToolbar(a, i18n.NewTranslator(i18n.LangEN), nil, nil, nil)
+	if toolbar.MenuButton().Visible() {
+		t.Fatal("expected menu button hidden without menu items")
+	}
+	toolbar.SetMenu(func() []*fyne.MenuItem {
+		return []*fyne.MenuItem{fyne.NewMenuItem("Portfolio", nil)}
+	})
+	if !toolbar.MenuButton().Visible() {
+		t.Fatal("expected menu button visible once a menu is set")
+	}
+}
+
+func TestToolbarRefreshButtonShowsSpinnerWhileRefreshing(t *testing.T) {
+	a := test.NewApp()
+	defer a.Quit()
+
+	toolbar := NewToolbar(a, i18n.NewTranslator(i18n.LangEN), nil, nil, nil)
+	if toolbar.refreshWrap.Visible() {
+		t.Fatal("expected refresh control hidden without a handler")
+	}
+	refreshes := 0
+	toolbar.SetOnRefresh(func() {
+		refreshes++
+		toolbar.SetRefreshing(true)
+	})
+	test.Tap(toolbar.RefreshButton())
+	if refreshes != 1 || !toolbar.Refreshing() || toolbar.RefreshButton().Visible() {
+		t.Fatalf("expected spinner to replace the button while refreshing, refreshes=%d", refreshes)
+	}
+	toolbar.SetRefreshing(false)
+	if toolbar.Refreshing() || !toolbar.RefreshButton().Visible() {
+		t.Fatal("expected refresh button back once the cycle completes")
+	}
+}
@@ -0,0 +1,141 @@
+package ui
+
+import (
+	"fmt"
+	"time"
+
+	"cryptoview/internal/service/marketfeed"
+	"cryptoview/internal/ui/i18n"
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/widget"
+)
+
+const diagnosticsRefreshInterval = time.Second
+
+type diagnosticsSource interface {
+	ProviderStates() []marketfeed.ProviderState
+	RequestBudgets() []marketfeed.RequestBudget
+}
+
+type diagnosticsDialog struct {
+	dialog     dialog.Dialog
+	translator *i18n.Translator
+	source     diagnosticsSource
+	rows       *fyne.Container
+	done       chan struct{}
+}
+
+func showDiagnosticsDialog(parent fyne.Window, translator *i18n.Translator, source diagnosticsSource) *diagnosticsDialog {
+	d := &diagnosticsDialog{
+		translator: translator,
+		source:     source,
+		rows:       container.NewVBox(),
+		done:       make(chan struct{}),
+	}
+	d.render(time.Now())
+
+	d.dialog = dialog.NewCustom(translator.T("diagnostics.title"), translator.T("diagnostics.close"), container.NewVScroll(d.rows), parent)
+	d.dialog.SetOnClosed(func() { close(d.done) })
+	d.dialog.Resize(fyne.NewSize(420, 420))
+	d.dialog.Show()
+
+	go func() {
+		ticker := time.NewTicker(diagnosticsRefreshInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-d.done:
+				return
+			case now := <-ticker.C:
+				fyne.Do(func() { d.render(now) })
+			}
+		}
+	}()
+	return d
+}
+
+func (d *diagnosticsDialog) render(now time.Time) {
+	t := d.translator
+	d.rows.RemoveAll()
+	states := d.source.ProviderStates()
+	if len(states) == 0 {
+		d.rows.Add(widget.NewLabel(t.T("diagnostics.empty")))
+	}
+	shown := make(map[string]bool, len(states))
+	for _, state := range states {
+		shown[state.Name] = true
+		grid := container.NewGridWithColumns(2,
+			widget.NewLabel(t.T("diagnostics.circuit")), widget.NewLabel(formatCircuit(t, state.Circuit)),
+			widget.NewLabel(t.T("diagnostics.last_success")), widget.NewLabel(formatSince(t, state.LastSuccess, now)),
+			widget.NewLabel(t.T("diagnostics.last_error")), widget.NewLabel(formatProviderError(t, state, now)),
+			widget.NewLabel(t.T("diagnostics.cooldown")), widget.NewLabel(formatCooldown(t, state, now)),
+			widget.NewLabel(t.T("diagnostics.failures")), widget.NewLabel(fmt.Sprintf("%d", state.ConsecutiveFailures)),
+			widget.NewLabel(t.T("diagnostics.latency")), widget.NewLabel(formatLatency(t, state.AverageLatency)),
+			widget.NewLabel(t.T("diagnostics.budget")), widget.NewLabel(formatBudget(t, state.BudgetPerMinute, state.BudgetRemaining)),
+		)
+		subtitle := ""
+		if state.FX {
+			subtitle = t.T("diagnostics.fx")
+		}
+		d.rows.Add(widget.NewCard(providerDisplayName(state.Name), subtitle, grid))
+	}
+	for _, budget := range d.source.RequestBudgets() {
+		if shown[budget.Provider] {
+			continue
+		}
+		grid := container.NewGridWithColumns(2,
+			widget.NewLabel(t.T("diagnostics.budget")), widget.NewLabel(formatBudget(t, budget.PerMinute, budget.Remaining)),
+		)
+		d.rows.Add(widget.NewCard(providerDisplayName(budget.Provider), "", grid))
+	}
+	d.rows.Refresh()
+}
+
+func formatCircuit(t *i18n.Translator, state marketfeed.CircuitState) string {
+	if state == "" {
+		state = marketfeed.CircuitClosed
+	}
+	return t.T("circuit." + string(state))
+}
+
+func formatSince(t *i18n.Translator, at time.Time, now time.Time) string {
+	if at.IsZero() {
+		return t.T("diagnostics.never")
+	}
+	return fmt.Sprintf(t.T("diagnostics.ago"), i18n.FormatAge(now.Sub(at), t.Language()))
+}
+
+func formatProviderError(t *i18n.Translator, state marketfeed.ProviderState, now time.Time) string {
+	if state.LastError == nil {
+		return "--"
+	}
+	text := t.T("diagnostics.kind." + string(state.LastErrorKind))
+	if state.LastStatusCode > 0 {
+		text = fmt.Sprintf("%s (HTTP %d)", text, state.LastStatusCode)
+	}
+	return fmt.Sprintf("%s, %s", text, formatSince(t, state.LastFailure, now))
+}
+
+func formatCooldown(t *i18n.Translator, state marketfeed.ProviderState, now time.Time) string {
+	remaining := state.CooldownRemaining(now)
+	if remaining <= 0 {
+		return "--"
+	}
+	return i18n.FormatAge(remaining.Round(time.Second), t.Language())
+}
+
+func formatLatency(t *i18n.Translator, latency time.Duration) string {
+	if latency <= 0 {
+		return "--"
+	}
+	return fmt.Sprintf(t.T("diagnostics.latency_ms"), latency.Milliseconds())
+}
+
+func formatBudget(t *i18n.Translator, perMinute, remaining int) string {
+	if perMinute <= 0 {
+		return t.T("diagnostics.budget.unlimited")
+	}
+	return fmt.Sprintf(t.T("diagnostics.budget.remaining"), remaining, perMinute)
+}
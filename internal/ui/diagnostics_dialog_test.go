@@ -0,0 +1,93 @@
+package ui
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"cryptoview/internal/service/marketfeed"
+	"cryptoview/internal/ui/i18n"
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/test"
+	"fyne.io/fyne/v2/widget"
+)
+
+func TestDiagnosticsDialogRendersProviderStates(t *testing.T) {
+	a := test.NewApp()
+	defer a.Quit()
+	w := a.NewWindow("main")
+	defer w.Close()
+
+	now := time.Now()
+	states := []marketfeed.ProviderState{
+		{
+			Name:                "coingecko",
+			Circuit:             marketfeed.CircuitOpen,
+			ConsecutiveFailures: 2,
+			CooldownUntil:       now.Add(9500 * time.Millisecond),
+			LastSuccess:         now.Add(-5 * time.Minute),
+			LastFailure:         now.Add(-3 * time.Second),
+			LastError:           errors.New("429"),
+			LastErrorKind:       marketfeed.FailureKindRateLimit,
+			LastStatusCode:      429,
+			AverageLatency:      240 * time.Millisecond,
+			BudgetPerMinute:     25,
+			BudgetRemaining:     7,
+		},
+		{Name: "coinlore"},
+		{Name: "open-er-api", FX: true, LastSuccess: now.Add(-time.Hour)},
+	}
+	feed := &fakeFeed{providerStates: states, budgets: []marketfeed.RequestBudget{
+		{Provider: "coingecko", PerMinute: 25, Remaining: 7},
+		{Provider: "ecb", PerMinute: 10, Remaining: 4},
+	}}
+	d := showDiagnosticsDialog(w, i18n.NewTranslator(i18n.LangEN), feed)
+	defer d.dialog.Hide()
+	d.render(now)
+
+	if len(d.rows.Objects) != 4 {
+		t.Fatalf("expected one card per provider plus the idle budget, got %d", len(d.rows.Objects))
+	}
+	first := cardText(d.rows.Objects[0].(*widget.Card))
+	for _, want := range []string{"CoinGecko", "5m ago", "Rate limited (HTTP 429), 3s ago", "10s", "2", "240 ms", "7 of 25 per minute left", "Open"} {
+		if !strings.Contains(first, want) {
+			t.Fatalf("expected %q in provider card, got %q", want, first)
+		}
+	}
+	second := cardText(d.rows.Objects[1].(*widget.Card))
+	if !strings.Contains(second, "Never") || strings.Contains(second, "ms") || !strings.Contains(second, "Unlimited") || !strings.Contains(second, "Closed") {
+		t.Fatalf("unexpected idle provider card %q", second)
+	}
+	fx := d.rows.Objects[2].(*widget.Card)
+	if fx.Subtitle != "Exchange rates" || !strings.Contains(cardText(fx), "1h ago") {
+		t.Fatalf("expected a tagged fx provider card, got %q / %q", fx.Subtitle, cardText(fx))
+	}
+	budget := cardText(d.rows.Objects[3].(*widget.Card))
+	if !strings.Contains(budget, "4 of 10 per minute left") {
+		t.Fatalf("expected budget card for a provider without state, got %q", budget)
+	}
+}
+
+func TestFooterTapInvokesHandler(t *testing.T) {
+	a := test.NewApp()
+	defer a.Quit()
+
+	footer := NewFooterController(i18n.NewTranslator(i18n.LangEN))
+	tapped := 0
+	footer.SetOnTapped(func() { tapped++ })
+	test.Tap(footer.tapTarget)
+	if tapped != 1 {
+		t.Fatalf("expected footer tap to invoke handler once, got %d", tapped)
+	}
+}
+
+func cardText(card *widget.Card) string {
+	parts := []string{card.Title}
+	for _, obj := range card.Content.(*fyne.Container).Objects {
+		if label, ok := obj.(*widget.Label); ok {
+			parts = append(parts, label.Text)
+		}
+	}
+	return strings.Join(parts, " | ")
+}
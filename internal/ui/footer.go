@@ -7,6 +7,7 @@ import (
 	"fyne.io/fyne/v2"
 	"fyne.io/fyne/v2/canvas"
 	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/driver/desktop"
 	"fyne.io/fyne/v2/widget"
 )
 
@@ -31,6 +32,7 @@ type FooterController struct {
 
 	state      FooterState
 	customText string
+	tapTarget  *tapTarget
 }
 
 func NewFooterController(translator *i18n.Translator) *FooterController {
@@ -55,7 +57,8 @@ func NewFooterController(translator *i18n.Translator) *FooterController {
 		container.NewCenter(statusLabel),
 		container.NewCenter(statusValue),
 	)
-	root := container.NewVBox(container.NewPadded(row), progressWrap)
+	target := newTapTarget()
+	root := container.NewVBox(container.NewStack(container.NewPadded(row), target), progressWrap)
 
 	controller := &FooterController{
 		root:         root,
@@ -65,6 +68,7 @@ func NewFooterController(translator *i18n.Translator) *FooterController {
 		statusValue:  statusValue,
 		progress:     progress,
 		progressWrap: progressWrap,
+		tapTarget:    target,
 	}
 	controller.SetOK()
 	return controller
@@ -74,6 +78,10 @@ func (f *FooterController) CanvasObject() fyne.CanvasObject {
 	return f.root
 }
 
+func (f *FooterController) SetOnTapped(fn func()) {
+	f.tapTarget.onTapped = fn
+}
+
 func (f *FooterController) SetLanguage(language i18n.AppLanguage) {
 	f.translator.SetLanguage(language)
 	f.applyState()
@@ -163,3 +171,31 @@ func (f *FooterController) applyState() {
 		f.progressWrap.Hide()
 	}
 }
+
+type tapTarget struct {
+	widget.BaseWidget
+	onTapped func()
+}
+
+func newTapTarget() *tapTarget {
+	t := &tapTarget{}
+	t.ExtendBaseWidget(t)
+	return t
+}
+
+func (t *tapTarget) CreateRenderer() fyne.WidgetRenderer {
+	return widget.NewSimpleRenderer(canvas.NewRectangle(color.Transparent))
+}
+
+func (t *tapTarget) Tapped(*fyne.PointEvent) {
+	if t.onTapped != nil {
+		t.onTapped()
+	}
+}
+
+func (t *tapTarget) Cursor() desktop.Cursor {
+	if t.onTapped != nil {
+		return desktop.PointerCursor
+	}
+	return desktop.DefaultCursor
+}
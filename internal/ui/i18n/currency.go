@@ -0,0 +1,63 @@
+package i18n
+
+type SymbolPlacement int
+
+const (
+	SymbolPrefix SymbolPlacement = iota
+	SymbolSuffix
+)
+
+// CurrencyInfo describes a display currency. Crypto quote currencies set
+// CoinID to the reference coin and Units to how many display units make up
+// one coin (1e8 for sats); prices are then divided by that coin's USD price.
+type CurrencyInfo struct {
+	Code      FiatCurrency
+	Symbol    string
+	Decimals  int
+	Placement SymbolPlacement
+	CoinID    string
+	Units     float64
+}
+
+func (c CurrencyInfo) IsCrypto() bool {
+	return c.CoinID != ""
+}
+
+// currencyTable drives the fiat selector, parsing and price formatting. The
+// order here is the order shown in the toolbar.
+var currencyTable = []CurrencyInfo{
+	{Code: FiatUSD, Symbol: "$", Decimals: 2, Placement: SymbolPrefix},
+	{Code: FiatEUR, Symbol: "€", Decimals: 2, Placement: SymbolPrefix},
+	{Code: FiatRUB, Symbol: "₽", Decimals: 2, Placement: SymbolPrefix},
+	{Code: FiatGBP, Symbol: "£", Decimals: 2, Placement: SymbolPrefix},
+	{Code: FiatJPY, Symbol: "¥", Decimals: 0, Placement: SymbolPrefix},
+	{Code: FiatCHF, Symbol: "CHF", Decimals: 2, Placement: SymbolPrefix},
+	{Code: FiatCNY, Symbol: "CN¥", Decimals: 2, Placement: SymbolPrefix},
+	{Code: FiatTRY, Symbol: "₺", Decimals: 2, Placement: SymbolPrefix},
+	{Code: FiatKZT, Symbol: "₸", Decimals: 2, Placement: SymbolSuffix},
+	{Code: FiatUAH, Symbol: "₴", Decimals: 2, Placement: SymbolSuffix},
+	{Code: FiatPLN, Symbol: "zł", Decimals: 2, Placement: SymbolSuffix},
+	{Code: FiatCAD, Symbol: "CA$", Decimals: 2, Placement: SymbolPrefix},
+	{Code: FiatAUD, Symbol: "A$", Decimals: 2, Placement: SymbolPrefix},
+	{Code: FiatINR, Symbol: "₹", Decimals: 2, Placement: SymbolPrefix},
+	{Code: FiatBRL, Symbol: "R$", Decimals: 2, Placement: SymbolPrefix},
+	{Code: FiatKRW, Symbol: "₩", Decimals: 0, Placement: SymbolPrefix},
+	{Code: QuoteBTC, Symbol: "₿", Decimals: 8, Placement: SymbolPrefix, CoinID: "bitcoin", Units: 1},
+	{Code: QuoteETH, Symbol: "Ξ", Decimals: 6, Placement: SymbolPrefix, CoinID: "ethereum", Units: 1},
+	{Code: QuoteSATS, Symbol: "sats", Decimals: 2, Placement: SymbolSuffix, CoinID: "bitcoin", Units: 1e8},
+}
+
+func Currencies() []CurrencyInfo {
+	out := make([]CurrencyInfo, len(currencyTable))
+	copy(out, currencyTable)
+	return out
+}
+
+func LookupCurrency(code FiatCurrency) (CurrencyInfo, bool) {
+	for _, info := range currencyTable {
+		if info.Code == code {
+			return info, true
+		}
+	}
+	return CurrencyInfo{}, false
+}
@@ -2,24 +2,44 @@ package i18n
 
 import (
 	"fmt"
+	"strconv"
 	"strings"
 	"time"
+	"unicode"
+	"unicode/utf8"
 )
 
 func FormatPrice(value float64, fiat FiatCurrency, lang AppLanguage) string {
-	symbol := currencySymbol(fiat)
-	if symbol == "" {
-		symbol = "$"
+	info, ok := LookupCurrency(fiat)
+	if !ok {
+		info = currencyTable[0]
 	}
 
+	// Russian notation always puts the symbol after the amount; the table
+	// placement applies to the English layout.
 	switch lang {
 	case LangRU:
-		return fmt.Sprintf("%s %s", formatDecimal(value, ' ', ','), symbol)
+		return fmt.Sprintf("%s %s", formatDecimal(value, info.Decimals, ' ', ','), info.Symbol)
 	default:
-		return fmt.Sprintf("%s%s", symbol, formatDecimal(value, ',', '.'))
+		amount := formatDecimal(value, info.Decimals, ',', '.')
+		if info.Placement == SymbolSuffix {
+			return fmt.Sprintf("%s %s", amount, info.Symbol)
+		}
+		if isAlphabetic(info.Symbol) {
+			return fmt.Sprintf("%s %s", info.Symbol, amount)
+		}
+		return info.Symbol + amount
 	}
 }
 
+// FormatQuote marks prices converted through FX rates as approximate.
+func FormatQuote(value float64, fiat FiatCurrency, lang AppLanguage, converted bool) string {
+	if converted {
+		return "≈" + FormatPrice(value, fiat, lang)
+	}
+	return FormatPrice(value, fiat, lang)
+}
+
 func FormatTime(hhmmss string, _ AppLanguage) string {
 	if _, err := time.Parse("15:04:05", hhmmss); err != nil {
 		return "--:--:--"
@@ -27,21 +47,63 @@ func FormatTime(hhmmss string, _ AppLanguage) string {
 	return hhmmss
 }
 
-func currencySymbol(fiat FiatCurrency) string {
-	switch fiat {
-	case FiatUSD:
-		return "$"
-	case FiatEUR:
-		return "\u20ac"
-	case FiatRUB:
-		return "\u20bd"
-	default:
-		return ""
+func FormatAge(age time.Duration, lang AppLanguage) string {
+	if age < 0 {
+		age = 0
+	}
+	type unit struct {
+		size time.Duration
+		en   string
+		ru   string
 	}
+	units := []unit{
+		{24 * time.Hour, "d", "д"},
+		{time.Hour, "h", "ч"},
+		{time.Minute, "m", "мин"},
+	}
+	for _, u := range units {
+		if age >= u.size {
+			n := int(age / u.size)
+			if lang == LangRU {
+				return fmt.Sprintf("%d %s", n, u.ru)
+			}
+			return fmt.Sprintf("%d%s", n, u.en)
+		}
+	}
+	secs := int(age / time.Second)
+	if lang == LangRU {
+		return fmt.Sprintf("%d с", secs)
+	}
+	return fmt.Sprintf("%ds", secs)
 }
 
-func formatDecimal(value float64, thousandSep rune, decimalSep rune) string {
-	raw := fmt.Sprintf("%.2f", value)
+func FormatDateTime(t time.Time, lang AppLanguage) string {
+	if t.IsZero() {
+		return "--"
+	}
+	local := t.Local()
+	if lang == LangRU {
+		return local.Format("02.01.2006 15:04")
+	}
+	return local.Format("Jan 2, 2006 15:04")
+}
+
+// Letter-only symbols such as "CHF" read as words and need a separating
+// space; single-letter signs like "Ξ" stay attached to the amount.
+func isAlphabetic(symbol string) bool {
+	if utf8.RuneCountInString(symbol) < 2 {
+		return false
+	}
+	for _, r := range symbol {
+		if !unicode.IsLetter(r) {
+			return false
+		}
+	}
+	return true
+}
+
+func formatDecimal(value float64, decimals int, thousandSep rune, decimalSep rune) string {
+	raw := strconv.FormatFloat(value, 'f', decimals, 64)
 	parts := strings.SplitN(raw, ".", 2)
 	intPart := groupThousands(parts[0], thousandSep)
 	if len(parts) < 2 {
@@ -51,6 +113,9 @@ func formatDecimal(value float64, thousandSep rune, decimalSep rune) string {
 }
 
 func groupThousands(intPart string, sep rune) string {
+	if strings.HasPrefix(intPart, "-") {
+		return "-" + groupThousands(intPart[1:], sep)
+	}
 	if len(intPart) <= 3 {
 		return intPart
 	}
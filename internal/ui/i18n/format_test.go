@@ -1,6 +1,9 @@
 package i18n
 
-import "testing"
+import (
+	"testing"
+	"time"
+)
 
 func TestFormatPriceEN(t *testing.T) {
 	got := FormatPrice(12345.67, FiatUSD, LangEN)
@@ -16,6 +19,66 @@ func TestFormatPriceRU(t *testing.T) {
 	}
 }
 
+func TestFormatPriceUsesCurrencyTable(t *testing.T) {
+	tests := []struct {
+		value float64
+		fiat  FiatCurrency
+		lang  AppLanguage
+		want  string
+	}{
+		{12345.67, FiatEUR, LangEN, "€12,345.67"},
+		{12345.67, FiatJPY, LangEN, "¥12,346"},
+		{12345.67, FiatCHF, LangEN, "CHF 12,345.67"},
+		{12345.67, FiatKZT, LangEN, "12,345.67 ₸"},
+		{12345.67, FiatJPY, LangRU, "12 346 ¥"},
+		{12345.67, FiatCurrency("XYZ"), LangEN, "$12,345.67"},
+		{0.05123456789, QuoteBTC, LangEN, "₿0.05123457"},
+		{1, QuoteBTC, LangRU, "1,00000000 ₿"},
+		{0.0567891234, QuoteETH, LangEN, "Ξ0.056789"},
+		{5000000, QuoteSATS, LangEN, "5,000,000.00 sats"},
+		{-1234567.5, FiatUSD, LangEN, "$-1,234,567.50"},
+		{-123456, FiatRUB, LangRU, "-123 456,00 ₽"},
+	}
+	for _, tt := range tests {
+		if got := FormatPrice(tt.value, tt.fiat, tt.lang); got != tt.want {
+			t.Errorf("FormatPrice(%v, %s, %s) = %q, want %q", tt.value, tt.fiat, tt.lang, got, tt.want)
+		}
+	}
+}
+
+func TestFormatQuoteMarksConvertedPrices(t *testing.T) {
+	if got := FormatQuote(90, FiatEUR, LangEN, true); got != "≈€90.00" {
+		t.Fatalf("unexpected converted quote %q", got)
+	}
+	if got := FormatQuote(90, FiatEUR, LangEN, false); got != "€90.00" {
+		t.Fatalf("unexpected native quote %q", got)
+	}
+}
+
+func TestCurrencyTable(t *testing.T) {
+	seen := make(map[FiatCurrency]bool)
+	for _, info := range Currencies() {
+		if seen[info.Code] {
+			t.Fatalf("duplicate currency %s", info.Code)
+		}
+		seen[info.Code] = true
+		if info.Symbol == "" || info.Decimals < 0 {
+			t.Fatalf("incomplete currency entry %+v", info)
+		}
+		if got, ok := ParseFiatCurrency(string(info.Code)); !ok || got != info.Code {
+			t.Fatalf("ParseFiatCurrency(%s) = (%q, %v)", info.Code, got, ok)
+		}
+	}
+	for _, code := range []FiatCurrency{FiatUSD, FiatEUR, FiatRUB, FiatGBP, FiatJPY, FiatCHF, FiatCNY, FiatTRY, FiatKZT, FiatUAH} {
+		if !seen[code] {
+			t.Fatalf("expected %s in the currency table", code)
+		}
+	}
+	if _, ok := LookupCurrency("XYZ"); ok {
+		t.Fatal("expected unknown currency lookup to fail")
+	}
+}
+
 func TestFormatTime(t *testing.T) {
 	if got := FormatTime("12:34:56", LangEN); got != "12:34:56" {
 		t.Fatalf("expected valid time to remain unchanged, got %q", got)
@@ -24,3 +87,37 @@ func TestFormatTime(t *testing.T) {
 		t.Fatalf("expected invalid time fallback, got %q", got)
 	}
 }
+
+func TestFormatAge(t *testing.T) {
+	tests := []struct {
+		age  time.Duration
+		lang AppLanguage
+		want string
+	}{
+		{-time.Second, LangEN, "0s"},
+		{42 * time.Second, LangEN, "42s"},
+		{5*time.Minute + 10*time.Second, LangEN, "5m"},
+		{3 * time.Hour, LangEN, "3h"},
+		{50 * time.Hour, LangEN, "2d"},
+		{5 * time.Minute, LangRU, "5 мин"},
+		{42 * time.Second, LangRU, "42 с"},
+	}
+	for _, tt := range tests {
+		if got := FormatAge(tt.age, tt.lang); got != tt.want {
+			t.Errorf("FormatAge(%s, %s) = %q, want %q", tt.age, tt.lang, got, tt.want)
+		}
+	}
+}
+
+func TestFormatDateTime(t *testing.T) {
+	ts := time.Date(2026, time.March, 4, 15, 6, 0, 0, time.Local)
+	if got := FormatDateTime(ts, LangEN); got != "Mar 4, 2026 15:06" {
+		t.Fatalf("unexpected EN date time %q", got)
+	}
+	if got := FormatDateTime(ts, LangRU); got != "04.03.2026 15:06" {
+		t.Fatalf("unexpected RU date time %q", got)
+	}
+	if got := FormatDateTime(time.Time{}, LangEN); got != "--" {
+		t.Fatalf("expected placeholder for zero time, got %q", got)
+	}
+}
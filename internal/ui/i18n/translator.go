@@ -46,31 +46,249 @@ func (t *Translator) T(key string) string {
 
 var translations = map[AppLanguage]map[string]string{
 	LangEN: {
-		"app.title":               "CryptoView",
-		"status.label":            "Status:",
-		"status.ok":               "OK",
-		"status.loading":          "Loading...",
-		"status.error.no_data":    "No market data available",
-		"status.error.network":    "Network error",
-		"status.warning.cached":   "Offline, using cached data",
-		"status.warning.rate":     "Rate limited (429), using cached data",
-		"status.warning.fallback": "Provider fallback active",
-		"toolbar.refresh.tooltip": "Refresh",
-		"toolbar.lang.en":         "EN",
-		"toolbar.lang.ru":         "RU",
+		"app.title":                    "CryptoView",
+		"status.label":                 "Status:",
+		"status.ok":                    "OK",
+		"status.loading":               "Loading...",
+		"status.error.no_data":         "No market data available",
+		"status.error.network":         "Network error",
+		"status.warning.cached":        "Offline, using cached data",
+		"status.warning.cached_age":    "Offline, cached data from %s ago",
+		"status.warning.rate":          "Rate limited (429), using cached data",
+		"status.warning.fallback":      "Provider fallback active",
+		"status.warning.outliers":      "Discarded outlier quotes: %s",
+		"status.warning.fx_stale":      "%s exchange rate is %s old",
+		"status.warning.fx_missing":    "No exchange rate for %s",
+		"status.warning.key_rejected":  "%s rejected the API key. Check it in Settings; until then prices come from the other providers.",
+		"toolbar.refresh.tooltip":      "Refresh",
+		"toolbar.lang.en":              "EN",
+		"chart.range.1h":               "1h",
+		"chart.range.24h":              "24h",
+		"chart.range.7d":               "7d",
+		"chart.range.30d":              "30d",
+		"chart.range.1y":               "1y",
+		"chart.mode.line":              "Line",
+		"chart.mode.candles":           "Candles",
+		"chart.loading":                "Loading chart...",
+		"chart.error":                  "Chart data unavailable",
+		"chart.empty":                  "No chart data for this range",
+		"menu.portfolio":               "Portfolio",
+		"portfolio.title":              "Portfolio",
+		"portfolio.total":              "Total value",
+		"portfolio.pnl":                "Unrealized P&L",
+		"portfolio.col.coin":           "Coin",
+		"portfolio.col.qty":            "Quantity",
+		"portfolio.col.value":          "Value",
+		"portfolio.col.alloc":          "Allocation",
+		"portfolio.col.pnl":            "P&L",
+		"portfolio.coin":               "Select coin",
+		"portfolio.quantity":           "Quantity",
+		"portfolio.cost":               "Total cost",
+		"portfolio.cost_hint":          "Cost basis in %s",
+		"portfolio.save":               "Save",
+		"portfolio.empty":              "No holdings yet",
+		"portfolio.error.input":        "Select a coin and enter a positive quantity and cost",
+		"portfolio.error.rate":         "Exchange rate unavailable, try again shortly",
+		"portfolio.error.save":         "Could not save portfolio",
+		"menu.alerts":                  "Alerts",
+		"menu.sort":                    "Sort by",
+		"sort.default":                 "Watchlist order",
+		"sort.name":                    "Name",
+		"sort.price":                   "Price",
+		"sort.change":                  "24h change",
+		"alerts.title":                 "Price alerts",
+		"alerts.rules":                 "Rules",
+		"alerts.history":               "History",
+		"alerts.empty":                 "No alert rules yet",
+		"alerts.history.empty":         "No alerts have fired yet",
+		"alerts.kind":                  "Rule type",
+		"alerts.kind.above":            "Price above",
+		"alerts.kind.below":            "Price below",
+		"alerts.kind.percent_move":     "Moves by %",
+		"alerts.kind.cross_open":       "Crosses 24h open",
+		"alerts.value.price":           "Price in %s",
+		"alerts.value.percent":         "Change, e.g. -5",
+		"alerts.window.15m":            "15m",
+		"alerts.window.1h":             "1h",
+		"alerts.window.4h":             "4h",
+		"alerts.window.24h":            "24h",
+		"alerts.add":                   "Add",
+		"alerts.rule.above":            "%s above %s",
+		"alerts.rule.below":            "%s below %s",
+		"alerts.rule.percent_move":     "%s moves %+.2f%% within %s",
+		"alerts.rule.cross_open":       "%s crosses its 24h open",
+		"alerts.rule.waiting":          "%s (waiting for the %s rate)",
+		"alerts.fired.above":           "%s rose above %s (now %s)",
+		"alerts.fired.below":           "%s fell below %s (now %s)",
+		"alerts.fired.percent_move":    "%s moved %+.2f%% within %s (now %s)",
+		"alerts.fired.cross_up":        "%s crossed above its 24h open (now %s, %+.2f%%)",
+		"alerts.fired.cross_down":      "%s crossed below its 24h open (now %s, %+.2f%%)",
+		"alerts.notification.title":    "CryptoView alert: %s",
+		"alerts.error.input":           "Select a coin, a rule type and enter a valid value",
+		"alerts.error.save":            "Could not save alert rules",
+		"diagnostics.title":            "Provider diagnostics",
+		"diagnostics.close":            "Close",
+		"menu.settings":                "Settings",
+		"settings.title":               "Feed settings",
+		"settings.save":                "Save",
+		"settings.cancel":              "Cancel",
+		"settings.market":              "Market providers",
+		"settings.fx":                  "Exchange rate providers",
+		"settings.stream":              "Live stream",
+		"settings.stream.note":         "While connected, the stream replaces polling for USD prices. Other fiats keep polling providers that quote them natively, and polling takes over whenever the stream drops.",
+		"settings.timeout":             "Timeout, s",
+		"settings.poll.market":         "Market refresh, s",
+		"settings.poll.fx":             "Exchange rate refresh, s",
+		"settings.keys":                "API keys",
+		"settings.key.placeholder":     "Optional, for paid plans",
+		"settings.error.number":        "%s: enter a number of seconds from %g to %g",
+		"settings.error.no_provider":   "Keep at least one provider enabled in each list",
+		"settings.error.key_save":      "Could not save the %s API key in the system keychain: %v",
+		"settings.error.key_load":      "Could not read API keys from the system keychain: %v",
+		"diagnostics.empty":            "No providers configured",
+		"diagnostics.fx":               "Exchange rates",
+		"diagnostics.last_success":     "Last success",
+		"diagnostics.last_error":       "Last error",
+		"diagnostics.cooldown":         "Cooldown",
+		"diagnostics.failures":         "Consecutive failures",
+		"diagnostics.latency":          "Average latency",
+		"diagnostics.latency_ms":       "%d ms",
+		"diagnostics.budget":           "Request budget",
+		"diagnostics.budget.remaining": "%d of %d per minute left",
+		"diagnostics.budget.unlimited": "Unlimited",
+		"diagnostics.circuit":          "Circuit",
+		"circuit.closed":               "Closed",
+		"circuit.open":                 "Open",
+		"circuit.half_open":            "Half-open (probing)",
+		"diagnostics.never":            "Never",
+		"diagnostics.ago":              "%s ago",
+		"diagnostics.kind.rate_limit":  "Rate limited",
+		"diagnostics.kind.network":     "Network error",
+		"diagnostics.kind.other":       "Error",
+		"diagnostics.kind.auth":        "API key rejected",
+		"toolbar.lang.ru":              "RU",
 	},
 	LangRU: {
-		"app.title":               "CryptoView",
-		"status.label":            "Статус:",
-		"status.ok":               "OK",
-		"status.loading":          "Загрузка...",
-		"status.error.no_data":    "Нет данных рынка",
-		"status.error.network":    "Ошибка сети",
-		"status.warning.cached":   "Оффлайн, используются кешированные данные",
-		"status.warning.rate":     "Лимит API (429), используются кешированные данные",
-		"status.warning.fallback": "Активен резервный провайдер",
-		"toolbar.refresh.tooltip": "Обновить",
-		"toolbar.lang.en":         "EN",
-		"toolbar.lang.ru":         "RU",
+		"app.title":                    "CryptoView",
+		"status.label":                 "Статус:",
+		"status.ok":                    "OK",
+		"status.loading":               "Загрузка...",
+		"status.error.no_data":         "Нет данных рынка",
+		"status.error.network":         "Ошибка сети",
+		"status.warning.cached":        "Оффлайн, используются кешированные данные",
+		"status.warning.cached_age":    "Оффлайн, данные %s назад",
+		"status.warning.rate":          "Лимит API (429), используются кешированные данные",
+		"status.warning.fallback":      "Активен резервный провайдер",
+		"status.warning.outliers":      "Отброшены аномальные котировки: %s",
+		"status.warning.fx_stale":      "Курс %s устарел: %s",
+		"status.warning.fx_missing":    "Нет курса для %s",
+		"status.warning.key_rejected":  "%s отклонил API-ключ. Проверьте его в настройках; пока цены берутся у других провайдеров.",
+		"toolbar.refresh.tooltip":      "Обновить",
+		"toolbar.lang.en":              "EN",
+		"toolbar.lang.ru":              "RU",
+		"chart.range.1h":               "1ч",
+		"chart.range.24h":              "24ч",
+		"chart.range.7d":               "7д",
+		"chart.range.30d":              "30д",
+		"chart.range.1y":               "1г",
+		"chart.mode.line":              "Линия",
+		"chart.mode.candles":           "Свечи",
+		"chart.loading":                "Загрузка графика...",
+		"chart.error":                  "Данные графика недоступны",
+		"chart.empty":                  "Нет данных за этот период",
+		"menu.portfolio":               "Портфель",
+		"portfolio.title":              "Портфель",
+		"portfolio.total":              "Общая стоимость",
+		"portfolio.pnl":                "Нереализованный P&L",
+		"portfolio.col.coin":           "Монета",
+		"portfolio.col.qty":            "Количество",
+		"portfolio.col.value":          "Стоимость",
+		"portfolio.col.alloc":          "Доля",
+		"portfolio.col.pnl":            "P&L",
+		"portfolio.coin":               "Выберите монету",
+		"portfolio.quantity":           "Количество",
+		"portfolio.cost":               "Сумма покупки",
+		"portfolio.cost_hint":          "Себестоимость в %s",
+		"portfolio.save":               "Сохранить",
+		"portfolio.empty":              "Активов пока нет",
+		"portfolio.error.input":        "Выберите монету и введите положительные количество и сумму",
+		"portfolio.error.rate":         "Курс недоступен, попробуйте позже",
+		"portfolio.error.save":         "Не удалось сохранить портфель",
+		"menu.alerts":                  "Оповещения",
+		"menu.sort":                    "Сортировка",
+		"sort.default":                 "Порядок списка",
+		"sort.name":                    "Название",
+		"sort.price":                   "Цена",
+		"sort.change":                  "Изменение за 24ч",
+		"alerts.title":                 "Ценовые оповещения",
+		"alerts.rules":                 "Правила",
+		"alerts.history":               "История",
+		"alerts.empty":                 "Правил оповещений пока нет",
+		"alerts.history.empty":         "Оповещений ещё не было",
+		"alerts.kind":                  "Тип правила",
+		"alerts.kind.above":            "Цена выше",
+		"alerts.kind.below":            "Цена ниже",
+		"alerts.kind.percent_move":     "Изменение на %",
+		"alerts.kind.cross_open":       "Пересечение открытия 24ч",
+		"alerts.value.price":           "Цена в %s",
+		"alerts.value.percent":         "Изменение, напр. -5",
+		"alerts.window.15m":            "15м",
+		"alerts.window.1h":             "1ч",
+		"alerts.window.4h":             "4ч",
+		"alerts.window.24h":            "24ч",
+		"alerts.add":                   "Добавить",
+		"alerts.rule.above":            "%s выше %s",
+		"alerts.rule.below":            "%s ниже %s",
+		"alerts.rule.percent_move":     "%s изменится на %+.2f%% за %s",
+		"alerts.rule.cross_open":       "%s пересекает открытие 24ч",
+		"alerts.rule.waiting":          "%s (ожидает курс %s)",
+		"alerts.fired.above":           "%s поднялся выше %s (сейчас %s)",
+		"alerts.fired.below":           "%s опустился ниже %s (сейчас %s)",
+		"alerts.fired.percent_move":    "%s изменился на %+.2f%% за %s (сейчас %s)",
+		"alerts.fired.cross_up":        "%s пересёк открытие 24ч вверх (сейчас %s, %+.2f%%)",
+		"alerts.fired.cross_down":      "%s пересёк открытие 24ч вниз (сейчас %s, %+.2f%%)",
+		"alerts.notification.title":    "Оповещение CryptoView: %s",
+		"alerts.error.input":           "Выберите монету, тип правила и введите корректное значение",
+		"alerts.error.save":            "Не удалось сохранить правила оповещений",
+		"diagnostics.title":            "Диагностика провайдеров",
+		"diagnostics.close":            "Закрыть",
+		"menu.settings":                "Настройки",
+		"settings.title":               "Настройки данных",
+		"settings.save":                "Сохранить",
+		"settings.cancel":              "Отмена",
+		"settings.market":              "Провайдеры котировок",
+		"settings.fx":                  "Провайдеры курсов валют",
+		"settings.stream":              "Поток в реальном времени",
+		"settings.stream.note":         "Пока поток подключён, он заменяет опрос для цен в USD. Для других валют продолжается опрос провайдеров, которые котируют их напрямую, а при обрыве потока опрос снова берёт всё на себя.",
+		"settings.timeout":             "Таймаут, с",
+		"settings.poll.market":         "Обновление котировок, с",
+		"settings.poll.fx":             "Обновление курсов, с",
+		"settings.keys":                "API-ключи",
+		"settings.key.placeholder":     "Необязательно, для платных тарифов",
+		"settings.error.number":        "%s: введите число секунд от %g до %g",
+		"settings.error.no_provider":   "Оставьте включённым хотя бы одного провайдера в каждом списке",
+		"settings.error.key_save":      "Не удалось сохранить API-ключ %s в системном хранилище ключей: %v",
+		"settings.error.key_load":      "Не удалось прочитать API-ключи из системного хранилища ключей: %v",
+		"diagnostics.empty":            "Провайдеры не настроены",
+		"diagnostics.fx":               "Курсы валют",
+		"diagnostics.last_success":     "Последний успех",
+		"diagnostics.last_error":       "Последняя ошибка",
+		"diagnostics.cooldown":         "Пауза",
+		"diagnostics.failures":         "Ошибок подряд",
+		"diagnostics.latency":          "Средняя задержка",
+		"diagnostics.latency_ms":       "%d мс",
+		"diagnostics.budget":           "Лимит запросов",
+		"diagnostics.budget.remaining": "осталось %d из %d в минуту",
+		"diagnostics.budget.unlimited": "Без ограничений",
+		"diagnostics.circuit":          "Предохранитель",
+		"circuit.closed":               "Замкнут",
+		"circuit.open":                 "Разомкнут",
+		"circuit.half_open":            "Полуоткрыт (проверка)",
+		"diagnostics.never":            "Никогда",
+		"diagnostics.ago":              "%s назад",
+		"diagnostics.kind.rate_limit":  "Лимит запросов",
+		"diagnostics.kind.network":     "Ошибка сети",
+		"diagnostics.kind.other":       "Ошибка",
+		"diagnostics.kind.auth":        "API-ключ отклонён",
 	},
 }
@@ -26,30 +26,36 @@ const (
 	FiatUSD FiatCurrency = "USD"
 	FiatEUR FiatCurrency = "EUR"
 	FiatRUB FiatCurrency = "RUB"
+	FiatGBP FiatCurrency = "GBP"
+	FiatJPY FiatCurrency = "JPY"
+	FiatCHF FiatCurrency = "CHF"
+	FiatCNY FiatCurrency = "CNY"
+	FiatTRY FiatCurrency = "TRY"
+	FiatKZT FiatCurrency = "KZT"
+	FiatUAH FiatCurrency = "UAH"
+	FiatPLN FiatCurrency = "PLN"
+	FiatCAD FiatCurrency = "CAD"
+	FiatAUD FiatCurrency = "AUD"
+	FiatINR FiatCurrency = "INR"
+	FiatBRL FiatCurrency = "BRL"
+	FiatKRW FiatCurrency = "KRW"
+
+	QuoteBTC  FiatCurrency = "BTC"
+	QuoteETH  FiatCurrency = "ETH"
+	QuoteSATS FiatCurrency = "SATS"
 )
 
 func ParseFiatCurrency(raw string) (FiatCurrency, bool) {
-	switch FiatCurrency(strings.ToUpper(strings.TrimSpace(raw))) {
-	case FiatUSD:
-		return FiatUSD, true
-	case FiatEUR:
-		return FiatEUR, true
-	case FiatRUB:
-		return FiatRUB, true
-	default:
+	info, ok := LookupCurrency(FiatCurrency(strings.ToUpper(strings.TrimSpace(raw))))
+	if !ok {
 		return "", false
 	}
+	return info.Code, true
 }
 
 func (f FiatCurrency) APIValue() (string, bool) {
-	switch f {
-	case FiatUSD:
-		return "usd", true
-	case FiatEUR:
-		return "eur", true
-	case FiatRUB:
-		return "rub", true
-	default:
+	if _, ok := LookupCurrency(f); !ok {
 		return "", false
 	}
+	return strings.ToLower(string(f)), true
 }
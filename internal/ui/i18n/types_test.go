@@ -40,7 +40,9 @@ func TestParseFiatCurrency(t *testing.T) {
 		{"RUB", FiatRUB, true},
 		{"rub", FiatRUB, true},
 		{"", "", false},
-		{"GBP", "", false},
+		{"gbp", FiatGBP, true},
+		{"JPY", FiatJPY, true},
+		{"XYZ", "", false},
 		{"xxx", "", false},
 	}
 	for _, tt := range tests {
@@ -60,7 +62,9 @@ func TestFiatCurrency_APIValue(t *testing.T) {
 		{FiatUSD, "usd", true},
 		{FiatEUR, "eur", true},
 		{FiatRUB, "rub", true},
-		{FiatCurrency("GBP"), "", false},
+		{FiatGBP, "gbp", true},
+		{FiatKZT, "kzt", true},
+		{FiatCurrency("XYZ"), "", false},
 		{FiatCurrency(""), "", false},
 	}
 	for _, tt := range tests {
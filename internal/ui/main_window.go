@@ -1,11 +1,18 @@
 package ui
 
 import (
+	"context"
+	"fmt"
+	"log"
+	"os"
 	"strings"
 	"sync"
 	"sync/atomic"
+	"time"
 
 	"cryptoview/internal/model"
+	"cryptoview/internal/server"
+	"cryptoview/internal/service/apikeys"
 	"cryptoview/internal/service/marketfeed"
 	"cryptoview/internal/ui/assets"
 	"cryptoview/internal/ui/components"
@@ -13,6 +20,7 @@ import (
 	uitheme "cryptoview/internal/ui/theme"
 	"fyne.io/fyne/v2"
 	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
 	"fyne.io/fyne/v2/theme"
 )
 
@@ -20,16 +28,71 @@ type marketFeed interface {
 	Start()
 	Stop()
 	SetFiat(i18n.FiatCurrency)
+	History(id string, window time.Duration) []marketfeed.CoinQuoteUSD
+	MarketChart(ctx context.Context, id string, fiat i18n.FiatCurrency, days int) ([]model.PricePoint, error)
+	ProviderStates() []marketfeed.ProviderState
+	RequestBudgets() []marketfeed.RequestBudget
+	RefreshNow(ctx context.Context) error
+	SetForeground(foreground bool)
+	TrackedCoins() []marketfeed.CoinRef
+	SetTrackedCoins(coins []marketfeed.CoinRef)
+	FXRates() map[i18n.FiatCurrency]float64
 }
 
+const (
+	sparklineWindow      = time.Hour
+	manualRefreshTimeout = 30 * time.Second
+)
+
 type feedFactory func(callbacks marketfeed.Callbacks) marketFeed
 
 func BuildMainWindow(a fyne.App, data []model.Coin) fyne.Window {
+	return BuildMainWindowWithSource(a, data, marketfeed.SourceOptions{})
+}
+
+// BuildMainWindowWithSource starts the feed from a recording or records it,
+// see marketfeed.SourceOptions. If the source cannot be opened the window
+// falls back to live providers.
+func BuildMainWindowWithSource(a fyne.App, data []model.Coin, source marketfeed.SourceOptions) fyne.Window {
 	return buildMainWindowWithFeedFactory(a, data, func(callbacks marketfeed.Callbacks) marketFeed {
-		return marketfeed.NewDefault(callbacks)
+		addr := strings.TrimSpace(os.Getenv(server.AddrEnv))
+		if addr == "" {
+			return newSourceFeed(callbacks, source)
+		}
+		api := server.New()
+		feed := newSourceFeed(api.Callbacks(callbacks), source)
+		api.SetSource(feed)
+		if err := api.Start(addr); err != nil {
+			log.Printf("server: embedded API disabled: %v", err)
+			return feed
+		}
+		return &servedFeed{Feed: feed, api: api}
 	})
 }
 
+func newSourceFeed(callbacks marketfeed.Callbacks, source marketfeed.SourceOptions) *marketfeed.Feed {
+	feed, err := marketfeed.NewDefaultWithSource(callbacks, source)
+	if err != nil {
+		log.Printf("marketfeed: %v; using live providers", err)
+		return marketfeed.NewDefault(callbacks)
+	}
+	return feed
+}
+
+type servedFeed struct {
+	*marketfeed.Feed
+	api *server.Server
+}
+
+func (f *servedFeed) Stop() {
+	f.Feed.Stop()
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+	if err := f.api.Shutdown(ctx); err != nil {
+		log.Printf("server: shutdown failed err=%v", err)
+	}
+}
+
 func buildMainWindowWithFeedFactory(a fyne.App, data []model.Coin, makeFeed feedFactory) fyne.Window {
 	if makeFeed == nil {
 		makeFeed = func(callbacks marketfeed.Callbacks) marketFeed {
@@ -37,12 +100,13 @@ func buildMainWindowWithFeedFactory(a fyne.App, data []model.Coin, makeFeed feed
 		}
 	}
 
-	a.Settings().SetTheme(uitheme.NewForMode(uitheme.ModeSystem))
+	store := newSettingsStore(a.Preferences())
+	settings := store.Load()
+	a.Settings().SetTheme(uitheme.NewForMode(settings.Theme))
 
 	translator := i18n.NewTranslator(i18n.LangEN)
 	w := a.NewWindow(translator.T("app.title"))
-	w.Resize(fyne.NewSize(450, 480))
-	w.SetFixedSize(true)
+	w.Resize(settings.Window)
 	appIcon := assets.LoadResource("resources/Logo/CryptoView Icon.png")
 	if appIcon == nil {
 		appIcon = theme.FyneLogo()
@@ -56,13 +120,42 @@ func buildMainWindowWithFeedFactory(a fyne.App, data []model.Coin, makeFeed feed
 	currentLanguage := i18n.LangEN
 	var header *components.Toolbar
 	var statusEventID int64
-	feed := makeFeed(marketfeed.Callbacks{
+	latestCoins := data
+	var portfolioView *portfolioWindow
+	holdings := loadPortfolio()
+	var alertsView *alertsWindow
+	alertEngine := loadAlerts()
+	var feed marketFeed
+	feed = makeFeed(marketfeed.Callbacks{
 		OnMarketUpdate: func(coins []model.Coin) {
 			fyne.Do(func() {
 				coinList.ReplaceData(coins)
+				latestCoins = coins
+				if portfolioView != nil {
+					portfolioView.update(coins, currentCurrency)
+				}
+				alertEngine.SetRates(feed.FXRates())
+				fired := alertEngine.Evaluate(coins, currentCurrency, time.Now())
+				for _, alert := range fired {
+					a.SendNotification(alertNotification(translator, alert))
+				}
+				if alertsView != nil {
+					alertsView.update(coins, currentCurrency)
+				}
 			})
 		},
 		OnStatus: func(event marketfeed.StatusEvent) {
+			if event.Kind == marketfeed.StatusKindCircuit {
+				return
+			}
+			if event.Code == marketfeed.StatusCodeKeyRejected {
+				// The fallback status that follows would replace a footer
+				// message at once, so a rejected key gets a dialog.
+				fyne.Do(func() {
+					dialog.ShowError(fmt.Errorf(translator.T("status.warning.key_rejected"), providerDisplayName(event.Provider)), w)
+				})
+				return
+			}
 			localID := atomic.AddInt64(&statusEventID, 1)
 			fyne.Do(func() {
 				if atomic.LoadInt64(&statusEventID) != localID {
@@ -79,8 +172,12 @@ func buildMainWindowWithFeedFactory(a fyne.App, data []model.Coin, makeFeed feed
 						footer.SetWarning(translator.T("status.warning.rate"))
 					case marketfeed.StatusCodeFallback:
 						footer.SetOKWithMessage(okStatusMessage(translator, event.Provider))
+					case marketfeed.StatusCodeOutliers:
+						footer.SetWarning(outliersStatusMessage(translator, event))
+					case marketfeed.StatusCodeFXStale, marketfeed.StatusCodeFXMissing:
+						footer.SetWarning(fxStatusMessage(translator, event))
 					default:
-						footer.SetWarning(translator.T("status.warning.cached"))
+						footer.SetWarning(cachedStatusMessage(translator, event))
 					}
 				case marketfeed.StatusKindError:
 					footer.SetError(errorStatusMessage(translator, event))
@@ -98,10 +195,16 @@ func buildMainWindowWithFeedFactory(a fyne.App, data []model.Coin, makeFeed feed
 			currentCurrency = currency
 			coinList.SetCurrency(currency)
 			feed.SetFiat(currency)
+			store.SaveFiat(currency)
+		},
+		func() {
+			if mode, ok := uitheme.ParseMode(header.ThemeMode()); ok {
+				store.SaveTheme(mode)
+			}
 		},
-		nil,
 		func(language i18n.AppLanguage) {
 			currentLanguage = language
+			store.SaveLanguage(language)
 			translator.SetLanguage(language)
 			coinList.SetLanguage(language)
 			if header != nil {
@@ -109,20 +212,105 @@ func buildMainWindowWithFeedFactory(a fyne.App, data []model.Coin, makeFeed feed
 			}
 			footer.SetLanguage(language)
 			w.SetTitle(translator.T("app.title"))
+			if portfolioView != nil {
+				portfolioView.setLanguage()
+			}
+			if alertsView != nil {
+				alertsView.setLanguage()
+			}
 		},
 	)
 
+	coinList.SetHistoryProvider(func(id string) []float64 {
+		return historyPrices(feed.History(id, sparklineWindow))
+	})
+	configurable, _ := feed.(feedConfigurator)
+	var apiKeys *apikeys.Store
+	if configurable != nil {
+		var err error
+		if apiKeys, err = loadAPIKeys(configurable); err != nil {
+			dialog.ShowError(fmt.Errorf(translator.T("settings.error.key_load"), err), w)
+		}
+		applyFeedConfig(configurable, settings.Feed, apiKeys)
+	}
+	header.SetMenu(func() []*fyne.MenuItem {
+		items := []*fyne.MenuItem{
+			fyne.NewMenuItem(translator.T("menu.portfolio"), func() {
+				if portfolioView != nil {
+					portfolioView.window.RequestFocus()
+					return
+				}
+				portfolioView = showPortfolioWindow(a, holdings, translator, latestCoins, currentCurrency, func() {
+					portfolioView = nil
+				})
+			}),
+			fyne.NewMenuItem(translator.T("menu.alerts"), func() {
+				if alertsView != nil {
+					alertsView.window.RequestFocus()
+					return
+				}
+				alertsView = showAlertsWindow(a, alertEngine, translator, latestCoins, currentCurrency, func() {
+					alertsView = nil
+				})
+			}),
+			sortMenuItem(translator, coinList.SortOrder(), func(order components.SortOrder) {
+				coinList.SetSortOrder(order)
+				store.SaveSort(order)
+			}),
+		}
+		if configurable != nil {
+			items = append(items, fyne.NewMenuItem(translator.T("menu.settings"), func() {
+				showFeedSettingsDialog(w, translator, configurable, apiKeys, store.SaveFeedConfig)
+			}))
+		}
+		return items
+	})
+	header.SetOnRefresh(func() {
+		header.SetRefreshing(true)
+		go func() {
+			ctx, cancel := context.WithTimeout(context.Background(), manualRefreshTimeout)
+			defer cancel()
+			if err := feed.RefreshNow(ctx); err != nil {
+				log.Printf("marketfeed: manual refresh failed err=%v", err)
+			}
+			fyne.Do(func() {
+				header.SetRefreshing(false)
+			})
+		}()
+	})
+	footer.SetOnTapped(func() {
+		showDiagnosticsDialog(w, translator, feed)
+	})
+	coinList.SetOnChartRequested(func(coin model.Coin) {
+		showChartWindow(a, feed, translator, coin, currentCurrency)
+	})
+
 	content := container.NewBorder(header.CanvasObject(), footer.CanvasObject(), nil, nil, coinList.Widget())
 	w.SetContent(content)
 	coinList.SetCurrency(currentCurrency)
 	coinList.SetLanguage(currentLanguage)
+	coinList.SetSortOrder(settings.Sort)
+	header.SetThemeMode(settings.Theme)
+	header.CurrencySelect().SetSelected(string(settings.Fiat))
+	header.LanguageSelect().SetSelected(string(settings.Language))
+	if len(settings.Tracked) > 0 {
+		feed.SetTrackedCoins(coinRefs(settings.Tracked))
+	}
 	footer.SetLoading()
+	a.Lifecycle().SetOnEnteredForeground(func() {
+		feed.SetForeground(true)
+	})
+	a.Lifecycle().SetOnExitedForeground(func() {
+		feed.SetForeground(false)
+	})
 	feed.Start()
 
 	var stopOnce sync.Once
 
 	w.SetOnClosed(func() {
 		stopOnce.Do(func() {
+			store.SaveWindowSize(w.Canvas().Size())
+			store.SaveTracked(coinIDs(feed.TrackedCoins()))
 			feed.Stop()
 		})
 	})
@@ -134,6 +322,45 @@ func buildMainWindowWithFeedFactory(a fyne.App, data []model.Coin, makeFeed feed
 	return w
 }
 
+func sortMenuItem(translator *i18n.Translator, current components.SortOrder, onSelected func(components.SortOrder)) *fyne.MenuItem {
+	orders := components.SortOrders()
+	items := make([]*fyne.MenuItem, 0, len(orders))
+	for _, order := range orders {
+		item := fyne.NewMenuItem(translator.T("sort."+string(order)), func() {
+			onSelected(order)
+		})
+		item.Checked = order == current
+		items = append(items, item)
+	}
+	menu := fyne.NewMenuItem(translator.T("menu.sort"), nil)
+	menu.ChildMenu = fyne.NewMenu("", items...)
+	return menu
+}
+
+func coinRefs(ids []string) []marketfeed.CoinRef {
+	refs := make([]marketfeed.CoinRef, 0, len(ids))
+	for _, id := range ids {
+		refs = append(refs, marketfeed.CoinRef{ID: id})
+	}
+	return refs
+}
+
+func coinIDs(refs []marketfeed.CoinRef) []string {
+	ids := make([]string, 0, len(refs))
+	for _, ref := range refs {
+		ids = append(ids, ref.ID)
+	}
+	return ids
+}
+
+func historyPrices(samples []marketfeed.CoinQuoteUSD) []float64 {
+	prices := make([]float64, 0, len(samples))
+	for _, sample := range samples {
+		prices = append(prices, sample.PriceUSD)
+	}
+	return prices
+}
+
 func okStatusMessage(translator *i18n.Translator, provider string) string {
 	base := "OK"
 	if translator != nil {
@@ -147,6 +374,16 @@ func okStatusMessage(translator *i18n.Translator, provider string) string {
 }
 
 func providerDisplayName(provider string) string {
+	if strings.Contains(provider, "+") {
+		parts := strings.Split(provider, "+")
+		names := make([]string, 0, len(parts))
+		for _, part := range parts {
+			if name := providerDisplayName(part); name != "" {
+				names = append(names, name)
+			}
+		}
+		return strings.Join(names, " + ")
+	}
 	switch strings.ToLower(strings.TrimSpace(provider)) {
 	case "coingecko":
 		return "CoinGecko"
@@ -158,10 +395,14 @@ func providerDisplayName(provider string) string {
 		return "CryptoCompare"
 	case "binance":
 		return "Binance"
+	case "binance-stream":
+		return "Binance Live"
 	case "coinlore":
 		return "CoinLore"
 	case "open-er-api":
 		return "Open ER API"
+	case "ecb":
+		return "ECB"
 	default:
 		if provider == "" {
 			return ""
@@ -170,6 +411,30 @@ func providerDisplayName(provider string) string {
 	}
 }
 
+func cachedStatusMessage(translator *i18n.Translator, event marketfeed.StatusEvent) string {
+	if event.DataAge <= 0 {
+		return translator.T("status.warning.cached")
+	}
+	age := i18n.FormatAge(event.DataAge, translator.Language())
+	return fmt.Sprintf(translator.T("status.warning.cached_age"), age)
+}
+
+func outliersStatusMessage(translator *i18n.Translator, event marketfeed.StatusEvent) string {
+	names := make([]string, 0, len(event.Discarded))
+	for _, provider := range event.Discarded {
+		names = append(names, providerDisplayName(provider))
+	}
+	return fmt.Sprintf(translator.T("status.warning.outliers"), strings.Join(names, ", "))
+}
+
+func fxStatusMessage(translator *i18n.Translator, event marketfeed.StatusEvent) string {
+	if event.Code == marketfeed.StatusCodeFXMissing {
+		return fmt.Sprintf(translator.T("status.warning.fx_missing"), event.Fiat)
+	}
+	age := i18n.FormatAge(event.DataAge, translator.Language())
+	return fmt.Sprintf(translator.T("status.warning.fx_stale"), event.Fiat, age)
+}
+
 func errorStatusMessage(translator *i18n.Translator, event marketfeed.StatusEvent) string {
 	if translator == nil {
 		return "Network error"
@@ -1,7 +1,13 @@
 package ui
 
 import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"sync/atomic"
 	"testing"
+	"time"
 
 	"cryptoview/internal/model"
 	"cryptoview/internal/service/marketfeed"
@@ -9,6 +15,7 @@ import (
 	"fyne.io/fyne/v2"
 	"fyne.io/fyne/v2/canvas"
 	"fyne.io/fyne/v2/test"
+	"fyne.io/fyne/v2/theme"
 	"fyne.io/fyne/v2/widget"
 )
 
@@ -17,6 +24,16 @@ type fakeFeed struct {
 	started   bool
 	stopCalls int
 	lastFiat  i18n.FiatCurrency
+
+	chartMu       sync.Mutex
+	chartRequests []string
+	chartPoints   []model.PricePoint
+
+	providerStates []marketfeed.ProviderState
+	budgets        []marketfeed.RequestBudget
+	refreshes      atomic.Int32
+	background     bool
+	tracked        []marketfeed.CoinRef
 }
 
 func newFakeFeed(callbacks marketfeed.Callbacks) *fakeFeed {
@@ -35,6 +52,49 @@ func (f *fakeFeed) SetFiat(currency i18n.FiatCurrency) {
 	f.lastFiat = currency
 }
 
+func (f *fakeFeed) History(string, time.Duration) []marketfeed.CoinQuoteUSD {
+	return nil
+}
+
+func (f *fakeFeed) MarketChart(_ context.Context, id string, fiat i18n.FiatCurrency, days int) ([]model.PricePoint, error) {
+	f.chartMu.Lock()
+	defer f.chartMu.Unlock()
+	f.chartRequests = append(f.chartRequests, fmt.Sprintf("%s/%s/%d", id, fiat, days))
+	if f.chartPoints != nil {
+		return f.chartPoints, nil
+	}
+	return nil, errors.New("no chart")
+}
+
+func (f *fakeFeed) ProviderStates() []marketfeed.ProviderState {
+	return f.providerStates
+}
+
+func (f *fakeFeed) RequestBudgets() []marketfeed.RequestBudget {
+	return f.budgets
+}
+
+func (f *fakeFeed) RefreshNow(context.Context) error {
+	f.refreshes.Add(1)
+	return nil
+}
+
+func (f *fakeFeed) SetForeground(foreground bool) {
+	f.background = !foreground
+}
+
+func (f *fakeFeed) TrackedCoins() []marketfeed.CoinRef {
+	return f.tracked
+}
+
+func (f *fakeFeed) SetTrackedCoins(coins []marketfeed.CoinRef) {
+	f.tracked = coins
+}
+
+func (f *fakeFeed) FXRates() map[i18n.FiatCurrency]float64 {
+	return nil
+}
+
 func (f *fakeFeed) EmitStatus(event marketfeed.StatusEvent) {
 	if f.callbacks.OnStatus != nil {
 		f.callbacks.OnStatus(event)
@@ -137,6 +197,76 @@ func TestBuildMainWindow_IntegrationLifecycle_NoNetwork(t *testing.T) {
 	}
 }
 
+func TestBuildMainWindow_RefreshButtonTriggersFeedRefresh(t *testing.T) {
+	a := test.NewApp()
+	defer a.Quit()
+
+	var feed *fakeFeed
+	w := buildMainWindowWithFeedFactory(a, nil, func(callbacks marketfeed.Callbacks) marketFeed {
+		feed = newFakeFeed(callbacks)
+		return feed
+	})
+	defer w.Close()
+
+	button := findButtonWithIcon(w.Content(), theme.ViewRefreshIcon().Name())
+	if button == nil || !button.Visible() {
+		t.Fatal("expected visible refresh button in the toolbar")
+	}
+	test.Tap(button)
+
+	deadline := time.Now().Add(time.Second)
+	for feed.refreshes.Load() == 0 && time.Now().Before(deadline) {
+		time.Sleep(5 * time.Millisecond)
+	}
+	if got := feed.refreshes.Load(); got != 1 {
+		t.Fatalf("expected one manual refresh, got %d", got)
+	}
+}
+
+func TestBuildMainWindow_LifecycleTogglesFeedForeground(t *testing.T) {
+	a := test.NewApp()
+	defer a.Quit()
+
+	var feed *fakeFeed
+	w := buildMainWindowWithFeedFactory(a, nil, func(callbacks marketfeed.Callbacks) marketFeed {
+		feed = newFakeFeed(callbacks)
+		return feed
+	})
+	defer w.Close()
+
+	lifecycle, ok := a.Lifecycle().(interface {
+		OnEnteredForeground() func()
+		OnExitedForeground() func()
+	})
+	if !ok {
+		t.Skip("test lifecycle does not expose hooks")
+	}
+	lifecycle.OnExitedForeground()()
+	if !feed.background {
+		t.Fatal("expected feed to slow down when the app leaves the foreground")
+	}
+	lifecycle.OnEnteredForeground()()
+	if feed.background {
+		t.Fatal("expected feed to resume foreground polling")
+	}
+}
+
+func findButtonWithIcon(obj fyne.CanvasObject, icon string) *widget.Button {
+	switch current := obj.(type) {
+	case *widget.Button:
+		if current.Icon != nil && current.Icon.Name() == icon {
+			return current
+		}
+	case *fyne.Container:
+		for _, child := range current.Objects {
+			if found := findButtonWithIcon(child, icon); found != nil {
+				return found
+			}
+		}
+	}
+	return nil
+}
+
 func findFirstList(obj fyne.CanvasObject) *widget.List {
 	switch current := obj.(type) {
 	case *widget.List:
@@ -2,6 +2,7 @@ package ui
 
 import (
 	"testing"
+	"time"
 
 	"cryptoview/internal/model"
 	"cryptoview/internal/service/marketfeed"
@@ -19,8 +20,10 @@ func TestProviderDisplayName(t *testing.T) {
 		{"coinpaprika", "CoinPaprika"},
 		{"cryptocompare", "CryptoCompare"},
 		{"binance", "Binance"},
+		{"binance-stream", "Binance Live"},
 		{"coinlore", "CoinLore"},
 		{"open-er-api", "Open ER API"},
+		{"coingecko+cryptocompare", "CoinGecko + CryptoCompare"},
 		{"  COINGECKO  ", "CoinGecko"},
 		{"", ""},
 		{"unknown", "unknown"},
@@ -66,6 +69,45 @@ func TestErrorStatusMessage(t *testing.T) {
 	}
 }
 
+func TestCachedStatusMessage(t *testing.T) {
+	tr := i18n.NewTranslator(i18n.LangEN)
+	if got := cachedStatusMessage(tr, marketfeed.StatusEvent{Code: marketfeed.StatusCodeOffline}); got != "Offline, using cached data" {
+		t.Fatalf("expected generic cached message without age, got %q", got)
+	}
+	event := marketfeed.StatusEvent{Code: marketfeed.StatusCodeOffline, DataAge: 5 * time.Minute}
+	if got := cachedStatusMessage(tr, event); got != "Offline, cached data from 5m ago" {
+		t.Fatalf("expected aged cached message, got %q", got)
+	}
+	tr.SetLanguage(i18n.LangRU)
+	if got := cachedStatusMessage(tr, event); got != "Оффлайн, данные 5 мин назад" {
+		t.Fatalf("expected RU aged cached message, got %q", got)
+	}
+}
+
+func TestOutliersStatusMessage(t *testing.T) {
+	tr := i18n.NewTranslator(i18n.LangEN)
+	event := marketfeed.StatusEvent{Code: marketfeed.StatusCodeOutliers, Discarded: []string{"coinlore", "binance"}}
+	if got := outliersStatusMessage(tr, event); got != "Discarded outlier quotes: CoinLore, Binance" {
+		t.Fatalf("unexpected outliers message %q", got)
+	}
+}
+
+func TestFXStatusMessage(t *testing.T) {
+	tr := i18n.NewTranslator(i18n.LangEN)
+	missing := marketfeed.StatusEvent{Code: marketfeed.StatusCodeFXMissing, Fiat: i18n.FiatKZT}
+	if got := fxStatusMessage(tr, missing); got != "No exchange rate for KZT" {
+		t.Fatalf("unexpected missing fx message %q", got)
+	}
+	stale := marketfeed.StatusEvent{Code: marketfeed.StatusCodeFXStale, Fiat: i18n.FiatEUR, DataAge: 80 * time.Hour}
+	if got := fxStatusMessage(tr, stale); got != "EUR exchange rate is 3d old" {
+		t.Fatalf("unexpected stale fx message %q", got)
+	}
+	tr.SetLanguage(i18n.LangRU)
+	if got := fxStatusMessage(tr, stale); got != "Курс EUR устарел: 3 д" {
+		t.Fatalf("unexpected RU stale fx message %q", got)
+	}
+}
+
 func TestBuildMainWindow_Smoke(t *testing.T) {
 	a := test.NewApp()
 	defer a.Quit()
@@ -0,0 +1,296 @@
+package ui
+
+import (
+	"fmt"
+	"log"
+	"sort"
+	"strconv"
+	"strings"
+
+	"cryptoview/internal/model"
+	"cryptoview/internal/service/portfolio"
+	"cryptoview/internal/ui/i18n"
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/theme"
+	"fyne.io/fyne/v2/widget"
+)
+
+type portfolioWindow struct {
+	window     fyne.Window
+	portfolio  *portfolio.Portfolio
+	translator *i18n.Translator
+	coins      []model.Coin
+	fiat       i18n.FiatCurrency
+
+	total      *widget.Label
+	pnl        *widget.Label
+	rows       *fyne.Container
+	coinSelect *widget.Select
+	quantity   *widget.Entry
+	cost       *widget.Entry
+	costLabel  *widget.Label
+	message    *widget.Label
+	coinIDs    map[string]string
+}
+
+func loadPortfolio() *portfolio.Portfolio {
+	path, err := portfolio.DefaultPath()
+	if err != nil {
+		log.Printf("portfolio: persistence disabled: %v", err)
+	}
+	pf := portfolio.New(path)
+	if err := pf.Load(); err != nil {
+		log.Printf("portfolio: load failed path=%s err=%v", path, err)
+	}
+	return pf
+}
+
+func showPortfolioWindow(a fyne.App, pf *portfolio.Portfolio, translator *i18n.Translator, coins []model.Coin, fiat i18n.FiatCurrency, onClosed func()) *portfolioWindow {
+	pw := &portfolioWindow{
+		window:     a.NewWindow(translator.T("portfolio.title")),
+		portfolio:  pf,
+		translator: translator,
+		total:      widget.NewLabel(""),
+		pnl:        widget.NewLabel(""),
+		rows:       container.NewVBox(),
+		quantity:   widget.NewEntry(),
+		cost:       widget.NewEntry(),
+		costLabel:  widget.NewLabel(""),
+		message:    widget.NewLabel(""),
+	}
+	pw.total.TextStyle = fyne.TextStyle{Bold: true}
+	pw.pnl.TextStyle = fyne.TextStyle{Bold: true}
+	pw.coinSelect = widget.NewSelect(nil, pw.prefill)
+	pw.message.Importance = widget.DangerImportance
+	pw.message.Hide()
+
+	pw.build()
+	pw.update(coins, fiat)
+	pw.window.Resize(fyne.NewSize(560, 420))
+	pw.window.SetOnClosed(func() {
+		if onClosed != nil {
+			onClosed()
+		}
+	})
+	pw.window.Show()
+	return pw
+}
+
+func (pw *portfolioWindow) build() {
+	t := pw.translator
+	pw.window.SetTitle(t.T("portfolio.title"))
+	pw.quantity.SetPlaceHolder(t.T("portfolio.quantity"))
+	save := widget.NewButtonWithIcon(t.T("portfolio.save"), theme.DocumentSaveIcon(), pw.save)
+	save.Importance = widget.HighImportance
+
+	summary := container.NewGridWithColumns(2,
+		widget.NewLabel(t.T("portfolio.total")), pw.total,
+		widget.NewLabel(t.T("portfolio.pnl")), pw.pnl,
+	)
+	form := container.NewVBox(
+		widget.NewSeparator(),
+		container.NewGridWithColumns(3, pw.coinSelect, pw.quantity, pw.cost),
+		container.NewBorder(nil, nil, pw.costLabel, save),
+		pw.message,
+	)
+	pw.window.SetContent(container.NewBorder(summary, form, nil, nil, container.NewVScroll(pw.rows)))
+}
+
+func (pw *portfolioWindow) setLanguage() {
+	pw.build()
+	pw.render()
+}
+
+func (pw *portfolioWindow) update(coins []model.Coin, fiat i18n.FiatCurrency) {
+	pw.coins = coins
+	pw.fiat = fiat
+	pw.render()
+}
+
+func (pw *portfolioWindow) render() {
+	t := pw.translator
+	lang := t.Language()
+	valuation := pw.portfolio.Value(pw.coins, pw.fiat)
+
+	pw.total.SetText(i18n.FormatPrice(valuation.TotalValue, pw.fiat, lang))
+	pw.pnl.SetText(formatPnL(valuation.PnL, valuation.PnLPercent, pw.fiat, lang))
+	pw.pnl.Importance = pnlImportance(valuation.PnL)
+	pw.pnl.Refresh()
+	pw.cost.SetPlaceHolder(t.T("portfolio.cost"))
+	pw.costLabel.SetText(fmt.Sprintf(t.T("portfolio.cost_hint"), pw.fiat))
+
+	pw.rows.RemoveAll()
+	if len(valuation.Positions) == 0 {
+		pw.rows.Add(widget.NewLabel(t.T("portfolio.empty")))
+	} else {
+		pw.rows.Add(container.NewGridWithColumns(6,
+			headerLabel(t.T("portfolio.col.coin")),
+			headerLabel(t.T("portfolio.col.qty")),
+			headerLabel(t.T("portfolio.col.value")),
+			headerLabel(t.T("portfolio.col.alloc")),
+			headerLabel(t.T("portfolio.col.pnl")),
+			widget.NewLabel(""),
+		))
+	}
+	for _, pos := range valuation.Positions {
+		coinID := pos.CoinID
+		value, alloc, pnl := "--", "--", widget.NewLabel("--")
+		if pos.Priced {
+			value = i18n.FormatPrice(pos.Value, pw.fiat, lang)
+			alloc = fmt.Sprintf("%.1f%%", pos.Allocation)
+			pnl.SetText(formatPnL(pos.PnL, pos.PnLPercent, pw.fiat, lang))
+			pnl.Importance = pnlImportance(pos.PnL)
+		}
+		remove := widget.NewButtonWithIcon("", theme.DeleteIcon(), func() {
+			if err := pw.portfolio.Remove(coinID); err != nil {
+				log.Printf("portfolio: remove failed coin=%s err=%v", coinID, err)
+				pw.showMessage(t.T("portfolio.error.save"))
+			}
+			pw.render()
+		})
+		remove.Importance = widget.LowImportance
+		pw.rows.Add(container.NewGridWithColumns(6,
+			widget.NewLabel(pos.Ticker),
+			widget.NewLabel(formatQuantity(pos.Quantity)),
+			widget.NewLabel(value),
+			widget.NewLabel(alloc),
+			pnl,
+			remove,
+		))
+	}
+	pw.refreshCoinOptions()
+}
+
+func (pw *portfolioWindow) refreshCoinOptions() {
+	options := make([]string, 0, len(pw.coins))
+	ids := make(map[string]string, len(pw.coins))
+	for _, coin := range pw.coins {
+		label := fmt.Sprintf("%s | %s", coin.Ticker, coin.Name)
+		options = append(options, label)
+		ids[label] = coin.ID
+	}
+	for _, h := range pw.portfolio.Holdings() {
+		found := false
+		for _, id := range ids {
+			if id == h.CoinID {
+				found = true
+				break
+			}
+		}
+		if !found {
+			ids[h.CoinID] = h.CoinID
+			options = append(options, h.CoinID)
+		}
+	}
+	sort.Strings(options)
+	pw.coinIDs = ids
+	pw.coinSelect.PlaceHolder = pw.translator.T("portfolio.coin")
+	pw.coinSelect.Options = options
+	pw.coinSelect.Refresh()
+}
+
+func (pw *portfolioWindow) prefill(selected string) {
+	id := pw.coinIDs[selected]
+	for _, h := range pw.portfolio.Holdings() {
+		if h.CoinID != id {
+			continue
+		}
+		pw.quantity.SetText(formatQuantity(h.Quantity))
+		if coin, ok := pw.coinByID(id); ok {
+			if rate, ok := portfolio.FiatRate(coin, pw.fiat); ok {
+				pw.cost.SetText(strconv.FormatFloat(h.CostBasisUSD*rate, 'f', 2, 64))
+			}
+		}
+		return
+	}
+}
+
+func (pw *portfolioWindow) save() {
+	t := pw.translator
+	id, ok := pw.coinIDs[pw.coinSelect.Selected]
+	quantity, qtyOK := parseAmount(pw.quantity.Text)
+	cost, costOK := parseAmount(pw.cost.Text)
+	if !ok || !qtyOK || !costOK || quantity <= 0 || cost < 0 {
+		pw.showMessage(t.T("portfolio.error.input"))
+		return
+	}
+	rate := 1.0
+	if coin, found := pw.coinByID(id); found {
+		r, rateOK := portfolio.FiatRate(coin, pw.fiat)
+		if !rateOK {
+			pw.showMessage(t.T("portfolio.error.rate"))
+			return
+		}
+		rate = r
+	} else if pw.fiat != i18n.FiatUSD {
+		pw.showMessage(t.T("portfolio.error.rate"))
+		return
+	}
+	if err := pw.portfolio.Upsert(portfolio.Holding{CoinID: id, Quantity: quantity, CostBasisUSD: cost / rate}); err != nil {
+		log.Printf("portfolio: save failed coin=%s err=%v", id, err)
+		pw.showMessage(t.T("portfolio.error.save"))
+		return
+	}
+	pw.message.Hide()
+	pw.quantity.SetText("")
+	pw.cost.SetText("")
+	pw.render()
+}
+
+func (pw *portfolioWindow) coinByID(id string) (model.Coin, bool) {
+	for _, coin := range pw.coins {
+		if coin.ID == id {
+			return coin, true
+		}
+	}
+	return model.Coin{}, false
+}
+
+func (pw *portfolioWindow) showMessage(text string) {
+	pw.message.SetText(text)
+	pw.message.Show()
+}
+
+func headerLabel(text string) *widget.Label {
+	label := widget.NewLabel(text)
+	label.TextStyle = fyne.TextStyle{Bold: true}
+	return label
+}
+
+func formatPnL(value, percent float64, fiat i18n.FiatCurrency, lang i18n.AppLanguage) string {
+	sign := "+"
+	if value < 0 {
+		sign = "-"
+		value = -value
+	}
+	return fmt.Sprintf("%s%s (%+.2f%%)", sign, i18n.FormatPrice(value, fiat, lang), percent)
+}
+
+func pnlImportance(value float64) widget.Importance {
+	switch {
+	case value > 0:
+		return widget.SuccessImportance
+	case value < 0:
+		return widget.DangerImportance
+	default:
+		return widget.MediumImportance
+	}
+}
+
+func formatQuantity(quantity float64) string {
+	return strconv.FormatFloat(quantity, 'f', -1, 64)
+}
+
+func parseAmount(raw string) (float64, bool) {
+	raw = strings.ReplaceAll(strings.TrimSpace(raw), " ", "")
+	raw = strings.ReplaceAll(raw, ",", ".")
+	if raw == "" {
+		return 0, false
+	}
+	value, err := strconv.ParseFloat(raw, 64)
+	if err != nil {
+		return 0, false
+	}
+	return value, true
+}
@@ -0,0 +1,64 @@
+package ui
+
+import (
+	"path/filepath"
+	"testing"
+
+	"cryptoview/internal/model"
+	"cryptoview/internal/service/portfolio"
+	"cryptoview/internal/ui/i18n"
+	"fyne.io/fyne/v2/test"
+)
+
+func TestPortfolioWindowSavesHoldingInUSDAndValuesInFiat(t *testing.T) {
+	a := test.NewApp()
+	defer a.Quit()
+
+	pf := portfolio.New(filepath.Join(t.TempDir(), "portfolio.json"))
+	coins := []model.Coin{{ID: "bitcoin", Name: "Bitcoin", Ticker: "BTC", Price: 90, PriceUSD: 100}}
+	pw := showPortfolioWindow(a, pf, i18n.NewTranslator(i18n.LangEN), coins, i18n.FiatEUR, nil)
+	defer pw.window.Close()
+
+	pw.coinSelect.SetSelected("BTC | Bitcoin")
+	pw.quantity.SetText("2")
+	pw.cost.SetText("135,00")
+	pw.save()
+
+	holdings := pf.Holdings()
+	if len(holdings) != 1 || holdings[0].Quantity != 2 || holdings[0].CostBasisUSD != 150 {
+		t.Fatalf("expected cost basis stored in USD, got %+v", holdings)
+	}
+	if got := pw.total.Text; got != "€180.00" {
+		t.Fatalf("unexpected total %q", got)
+	}
+	if got := pw.pnl.Text; got != "+€45.00 (+33.33%)" {
+		t.Fatalf("unexpected pnl %q", got)
+	}
+
+	pw.update([]model.Coin{{ID: "bitcoin", Name: "Bitcoin", Ticker: "BTC", Price: 60, PriceUSD: 60}}, i18n.FiatUSD)
+	if got := pw.pnl.Text; got != "-$30.00 (-20.00%)" {
+		t.Fatalf("expected live revaluation, got %q", got)
+	}
+}
+
+func TestPortfolioWindowRejectsInvalidInput(t *testing.T) {
+	a := test.NewApp()
+	defer a.Quit()
+
+	pf := portfolio.New("")
+	coins := []model.Coin{{ID: "bitcoin", Name: "Bitcoin", Ticker: "BTC", Price: 100, PriceUSD: 100}}
+	pw := showPortfolioWindow(a, pf, i18n.NewTranslator(i18n.LangEN), coins, i18n.FiatUSD, nil)
+	defer pw.window.Close()
+
+	pw.coinSelect.SetSelected("BTC | Bitcoin")
+	pw.quantity.SetText("abc")
+	pw.cost.SetText("10")
+	pw.save()
+
+	if len(pf.Holdings()) != 0 {
+		t.Fatal("expected invalid quantity to be rejected")
+	}
+	if !pw.message.Visible() {
+		t.Fatal("expected validation message to be shown")
+	}
+}
@@ -0,0 +1,213 @@
+package ui
+
+import (
+	"log"
+	"strings"
+	"time"
+
+	"cryptoview/internal/service/marketfeed"
+	"cryptoview/internal/ui/components"
+	"cryptoview/internal/ui/i18n"
+	uitheme "cryptoview/internal/ui/theme"
+	"fyne.io/fyne/v2"
+)
+
+// settingsVersion is the schema stored under prefSettingsVersion. Bump it
+// together with a new entry in settingsMigrations when keys are renamed or
+// their encoding changes.
+const settingsVersion = 1
+
+const (
+	prefSettingsVersion = "settings.version"
+	prefFiat            = "settings.fiat"
+	prefLanguage        = "settings.language"
+	prefTheme           = "settings.theme"
+	prefTracked         = "settings.tracked"
+	prefSort            = "settings.sort"
+	prefWindowWidth     = "settings.window.width"
+	prefWindowHeight    = "settings.window.height"
+	prefMarketProviders = "settings.providers.market"
+	prefFXProviders     = "settings.providers.fx"
+	prefDisabled        = "settings.providers.disabled"
+	prefTimeoutPrefix   = "settings.timeout."
+	prefMarketPoll      = "settings.poll.market"
+	prefFXPoll          = "settings.poll.fx"
+	prefStreamDisabled  = "settings.stream.disabled"
+)
+
+var defaultWindowSize = fyne.NewSize(450, 480)
+
+// settingsMigrations[i] upgrades stored preferences from version i to i+1.
+var settingsMigrations = []func(prefs fyne.Preferences){
+	// 0 -> 1: nothing was stored before the first versioned schema.
+	func(fyne.Preferences) {},
+}
+
+type appSettings struct {
+	Fiat     i18n.FiatCurrency
+	Language i18n.AppLanguage
+	Theme    uitheme.Mode
+	Tracked  []string
+	Sort     components.SortOrder
+	Window   fyne.Size
+	Feed     feedConfig
+}
+
+// feedConfig is what the settings dialog changes on the feed. Empty provider
+// lists and zero intervals leave the feed defaults alone, and the stream is
+// on unless StreamDisabled is set. API keys are kept in apikeys.Store, never
+// here.
+type feedConfig struct {
+	Market         []marketfeed.ProviderSetting
+	FX             []marketfeed.ProviderSetting
+	MarketPoll     time.Duration
+	FXPoll         time.Duration
+	StreamDisabled bool
+}
+
+func defaultSettings() appSettings {
+	return appSettings{
+		Fiat:     i18n.FiatUSD,
+		Language: i18n.LangEN,
+		Theme:    uitheme.ModeSystem,
+		Sort:     components.SortDefault,
+		Window:   defaultWindowSize,
+	}
+}
+
+type settingsStore struct {
+	prefs fyne.Preferences
+}
+
+func newSettingsStore(prefs fyne.Preferences) *settingsStore {
+	store := &settingsStore{prefs: prefs}
+	store.migrate()
+	return store
+}
+
+func (s *settingsStore) migrate() {
+	version := s.prefs.IntWithFallback(prefSettingsVersion, 0)
+	if version > settingsVersion {
+		// Written by a newer build: read the keys this one understands.
+		log.Printf("settings: schema version=%d is newer than supported=%d", version, settingsVersion)
+		return
+	}
+	if version < 0 {
+		version = 0
+	}
+	for ; version < settingsVersion; version++ {
+		settingsMigrations[version](s.prefs)
+		log.Printf("settings: migrated schema from=%d to=%d", version, version+1)
+	}
+	s.prefs.SetInt(prefSettingsVersion, settingsVersion)
+}
+
+// Load returns the stored settings; missing or invalid values fall back to
+// the defaults one by one.
+func (s *settingsStore) Load() appSettings {
+	settings := defaultSettings()
+	if fiat, ok := i18n.ParseFiatCurrency(s.prefs.String(prefFiat)); ok {
+		settings.Fiat = fiat
+	}
+	if language, ok := i18n.ParseAppLanguage(s.prefs.String(prefLanguage)); ok {
+		settings.Language = language
+	}
+	if mode, ok := uitheme.ParseMode(s.prefs.String(prefTheme)); ok {
+		settings.Theme = mode
+	}
+	for _, id := range s.prefs.StringList(prefTracked) {
+		if id = strings.TrimSpace(id); id != "" {
+			settings.Tracked = append(settings.Tracked, id)
+		}
+	}
+	if order, ok := components.ParseSortOrder(s.prefs.String(prefSort)); ok {
+		settings.Sort = order
+	}
+	width := float32(s.prefs.Float(prefWindowWidth))
+	height := float32(s.prefs.Float(prefWindowHeight))
+	if width > 0 && height > 0 {
+		settings.Window = fyne.NewSize(width, height)
+	}
+	disabled := make(map[string]bool)
+	for _, name := range s.prefs.StringList(prefDisabled) {
+		disabled[name] = true
+	}
+	settings.Feed.Market = s.loadProviders(prefMarketProviders, disabled)
+	settings.Feed.FX = s.loadProviders(prefFXProviders, disabled)
+	settings.Feed.MarketPoll = secondsDuration(s.prefs.Float(prefMarketPoll))
+	settings.Feed.FXPoll = secondsDuration(s.prefs.Float(prefFXPoll))
+	settings.Feed.StreamDisabled = s.prefs.Bool(prefStreamDisabled)
+	return settings
+}
+
+func (s *settingsStore) loadProviders(key string, disabled map[string]bool) []marketfeed.ProviderSetting {
+	names := s.prefs.StringList(key)
+	if len(names) == 0 {
+		return nil
+	}
+	providers := make([]marketfeed.ProviderSetting, 0, len(names))
+	for _, name := range names {
+		providers = append(providers, marketfeed.ProviderSetting{
+			Name:    name,
+			Enabled: !disabled[name],
+			Timeout: secondsDuration(s.prefs.Float(prefTimeoutPrefix + name)),
+		})
+	}
+	return providers
+}
+
+func (s *settingsStore) SaveFeedConfig(config feedConfig) {
+	var disabled []string
+	save := func(key string, providers []marketfeed.ProviderSetting) {
+		names := make([]string, 0, len(providers))
+		for _, provider := range providers {
+			names = append(names, provider.Name)
+			if !provider.Enabled {
+				disabled = append(disabled, provider.Name)
+			}
+			s.prefs.SetFloat(prefTimeoutPrefix+provider.Name, provider.Timeout.Seconds())
+		}
+		s.prefs.SetStringList(key, names)
+	}
+	save(prefMarketProviders, config.Market)
+	save(prefFXProviders, config.FX)
+	s.prefs.SetStringList(prefDisabled, disabled)
+	s.prefs.SetFloat(prefMarketPoll, config.MarketPoll.Seconds())
+	s.prefs.SetFloat(prefFXPoll, config.FXPoll.Seconds())
+	s.prefs.SetBool(prefStreamDisabled, config.StreamDisabled)
+}
+
+func secondsDuration(seconds float64) time.Duration {
+	if seconds <= 0 {
+		return 0
+	}
+	return time.Duration(seconds * float64(time.Second))
+}
+
+func (s *settingsStore) SaveFiat(fiat i18n.FiatCurrency) {
+	s.prefs.SetString(prefFiat, string(fiat))
+}
+
+func (s *settingsStore) SaveLanguage(language i18n.AppLanguage) {
+	s.prefs.SetString(prefLanguage, string(language))
+}
+
+func (s *settingsStore) SaveTheme(mode uitheme.Mode) {
+	s.prefs.SetString(prefTheme, string(mode))
+}
+
+func (s *settingsStore) SaveTracked(ids []string) {
+	s.prefs.SetStringList(prefTracked, ids)
+}
+
+func (s *settingsStore) SaveSort(order components.SortOrder) {
+	s.prefs.SetString(prefSort, string(order))
+}
+
+func (s *settingsStore) SaveWindowSize(size fyne.Size) {
+	if size.Width <= 0 || size.Height <= 0 {
+		return
+	}
+	s.prefs.SetFloat(prefWindowWidth, float64(size.Width))
+	s.prefs.SetFloat(prefWindowHeight, float64(size.Height))
+}
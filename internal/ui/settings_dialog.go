@@ -0,0 +1,304 @@
+package ui
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"strconv"
+	"strings"
+	"time"
+
+	"cryptoview/internal/service/apikeys"
+	"cryptoview/internal/service/marketfeed"
+	"cryptoview/internal/ui/i18n"
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/theme"
+	"fyne.io/fyne/v2/widget"
+)
+
+const (
+	minPollSeconds    = 1
+	maxPollSeconds    = 3600
+	minTimeoutSeconds = 0.5
+	maxTimeoutSeconds = 60
+)
+
+// feedConfigurator is implemented by feeds whose providers, intervals and
+// API keys can be changed while running.
+type feedConfigurator interface {
+	MarketProviderSettings() []marketfeed.ProviderSetting
+	FXProviderSettings() []marketfeed.ProviderSetting
+	SetMarketProviders(settings []marketfeed.ProviderSetting) error
+	SetFXProviders(settings []marketfeed.ProviderSetting) error
+	PollIntervals() (market, fx time.Duration)
+	SetMarketPollInterval(interval time.Duration)
+	SetFXPollInterval(interval time.Duration)
+	SetAPIKey(provider, key string) error
+	StreamSetting() (setting marketfeed.ProviderSetting, ok bool)
+	SetStreamEnabled(enabled bool)
+}
+
+type feedSettingsDialog struct {
+	dialog     dialog.Dialog
+	parent     fyne.Window
+	translator *i18n.Translator
+	feed       feedConfigurator
+	keys       *apikeys.Store
+	onSaved    func(feedConfig)
+
+	market     []marketfeed.ProviderSetting
+	fx         []marketfeed.ProviderSetting
+	timeouts   map[string]*widget.Entry
+	keyEntries map[string]*widget.Entry
+	marketRows *fyne.Container
+	fxRows     *fyne.Container
+	marketPoll *widget.Entry
+	fxPoll     *widget.Entry
+	stream     *widget.Check
+}
+
+func showFeedSettingsDialog(parent fyne.Window, translator *i18n.Translator, feed feedConfigurator, keys *apikeys.Store, onSaved func(feedConfig)) *feedSettingsDialog {
+	t := translator
+	d := &feedSettingsDialog{
+		parent:     parent,
+		translator: translator,
+		feed:       feed,
+		keys:       keys,
+		onSaved:    onSaved,
+		market:     feed.MarketProviderSettings(),
+		fx:         feed.FXProviderSettings(),
+		timeouts:   make(map[string]*widget.Entry),
+		keyEntries: make(map[string]*widget.Entry),
+		marketRows: container.NewVBox(),
+		fxRows:     container.NewVBox(),
+		marketPoll: widget.NewEntry(),
+		fxPoll:     widget.NewEntry(),
+	}
+	marketPoll, fxPoll := feed.PollIntervals()
+	d.marketPoll.SetText(formatSeconds(marketPoll))
+	d.fxPoll.SetText(formatSeconds(fxPoll))
+
+	keyForm := widget.NewForm()
+	for _, setting := range append(append([]marketfeed.ProviderSetting{}, d.market...), d.fx...) {
+		d.timeouts[setting.Name] = newSecondsEntry(setting.Timeout)
+		if !setting.SupportsAPIKey {
+			continue
+		}
+		entry := widget.NewPasswordEntry()
+		entry.SetPlaceHolder(t.T("settings.key.placeholder"))
+		if keys != nil {
+			entry.SetText(keys.Key(setting.Name))
+		}
+		d.keyEntries[setting.Name] = entry
+		keyForm.Append(providerDisplayName(setting.Name), entry)
+	}
+	d.renderProviders()
+
+	intervals := widget.NewForm(
+		widget.NewFormItem(t.T("settings.poll.market"), d.marketPoll),
+		widget.NewFormItem(t.T("settings.poll.fx"), d.fxPoll),
+	)
+	content := container.NewVBox(
+		widget.NewCard(t.T("settings.market"), t.T("settings.timeout"), d.marketRows),
+	)
+	if stream, ok := feed.StreamSetting(); ok {
+		d.stream = widget.NewCheck(providerDisplayName(stream.Name), nil)
+		d.stream.SetChecked(stream.Enabled)
+		note := widget.NewLabel(t.T("settings.stream.note"))
+		note.Wrapping = fyne.TextWrapWord
+		content.Add(widget.NewCard(t.T("settings.stream"), "", container.NewVBox(d.stream, note)))
+	}
+	content.Add(widget.NewCard(t.T("settings.fx"), t.T("settings.timeout"), d.fxRows))
+	content.Add(intervals)
+	if len(d.keyEntries) > 0 {
+		content.Add(widget.NewCard(t.T("settings.keys"), "", keyForm))
+	}
+
+	d.dialog = dialog.NewCustomConfirm(t.T("settings.title"), t.T("settings.save"), t.T("settings.cancel"), container.NewVScroll(content), func(save bool) {
+		if !save {
+			return
+		}
+		if err := d.apply(); err != nil {
+			dialog.ShowError(err, parent)
+		}
+	}, parent)
+	d.dialog.Resize(fyne.NewSize(440, 460))
+	d.dialog.Show()
+	return d
+}
+
+func (d *feedSettingsDialog) renderProviders() {
+	d.renderList(d.marketRows, &d.market)
+	d.renderList(d.fxRows, &d.fx)
+}
+
+func (d *feedSettingsDialog) renderList(rows *fyne.Container, list *[]marketfeed.ProviderSetting) {
+	rows.RemoveAll()
+	for i, setting := range *list {
+		i := i
+		enabled := widget.NewCheck(providerDisplayName(setting.Name), func(on bool) {
+			(*list)[i].Enabled = on
+		})
+		enabled.SetChecked(setting.Enabled)
+		up := widget.NewButtonWithIcon("", theme.MoveUpIcon(), func() {
+			d.move(list, i, i-1)
+		})
+		down := widget.NewButtonWithIcon("", theme.MoveDownIcon(), func() {
+			d.move(list, i, i+1)
+		})
+		if i == 0 {
+			up.Disable()
+		}
+		if i == len(*list)-1 {
+			down.Disable()
+		}
+		timeout := container.NewGridWrap(fyne.NewSize(64, 36), d.timeouts[setting.Name])
+		rows.Add(container.NewBorder(nil, nil, nil, container.NewHBox(timeout, up, down), enabled))
+	}
+	rows.Refresh()
+}
+
+func (d *feedSettingsDialog) move(list *[]marketfeed.ProviderSetting, from, to int) {
+	if to < 0 || to >= len(*list) {
+		return
+	}
+	(*list)[from], (*list)[to] = (*list)[to], (*list)[from]
+	d.renderProviders()
+}
+
+// apply validates every field first so a typo never leaves the feed half
+// configured, then pushes the settings to the running feed and saves them.
+func (d *feedSettingsDialog) apply() error {
+	t := d.translator
+	config := feedConfig{
+		Market: append([]marketfeed.ProviderSetting(nil), d.market...),
+		FX:     append([]marketfeed.ProviderSetting(nil), d.fx...),
+	}
+	var err error
+	for _, list := range [][]marketfeed.ProviderSetting{config.Market, config.FX} {
+		enabled := 0
+		for i := range list {
+			if list[i].Enabled {
+				enabled++
+			}
+			label := fmt.Sprintf("%s (%s)", providerDisplayName(list[i].Name), t.T("settings.timeout"))
+			if list[i].Timeout, err = parseSeconds(t, label, d.timeouts[list[i].Name].Text, minTimeoutSeconds, maxTimeoutSeconds); err != nil {
+				return err
+			}
+		}
+		if enabled == 0 {
+			return errors.New(t.T("settings.error.no_provider"))
+		}
+	}
+	if config.MarketPoll, err = parseSeconds(t, t.T("settings.poll.market"), d.marketPoll.Text, minPollSeconds, maxPollSeconds); err != nil {
+		return err
+	}
+	if config.FXPoll, err = parseSeconds(t, t.T("settings.poll.fx"), d.fxPoll.Text, minPollSeconds, maxPollSeconds); err != nil {
+		return err
+	}
+
+	if err := d.feed.SetMarketProviders(config.Market); err != nil {
+		return err
+	}
+	if err := d.feed.SetFXProviders(config.FX); err != nil {
+		return err
+	}
+	d.feed.SetMarketPollInterval(config.MarketPoll)
+	d.feed.SetFXPollInterval(config.FXPoll)
+	if d.stream != nil {
+		config.StreamDisabled = !d.stream.Checked
+		d.feed.SetStreamEnabled(d.stream.Checked)
+	}
+	var keyErrs []error
+	for provider, entry := range d.keyEntries {
+		key := strings.TrimSpace(entry.Text)
+		if err := d.feed.SetAPIKey(provider, key); err != nil {
+			log.Printf("settings: api key not applied provider=%s err=%v", provider, err)
+		}
+		if d.keys == nil || d.keys.Key(provider) == key {
+			continue
+		}
+		if err := d.keys.Set(provider, key); err != nil {
+			log.Printf("settings: api key save failed provider=%s err=%v", provider, err)
+			keyErrs = append(keyErrs, fmt.Errorf(t.T("settings.error.key_save"), providerDisplayName(provider), err))
+		}
+	}
+	if d.onSaved != nil {
+		d.onSaved(config)
+	}
+	return errors.Join(keyErrs...)
+}
+
+// applyFeedConfig restores saved settings on startup. Values that no longer
+// fit the feed, such as a list with every provider disabled, are skipped.
+func applyFeedConfig(feed feedConfigurator, config feedConfig, keys *apikeys.Store) {
+	if len(config.Market) > 0 {
+		if err := feed.SetMarketProviders(config.Market); err != nil {
+			log.Printf("settings: market providers not restored err=%v", err)
+		}
+	}
+	if len(config.FX) > 0 {
+		if err := feed.SetFXProviders(config.FX); err != nil {
+			log.Printf("settings: fx providers not restored err=%v", err)
+		}
+	}
+	if config.MarketPoll > 0 {
+		feed.SetMarketPollInterval(config.MarketPoll)
+	}
+	if config.FXPoll > 0 {
+		feed.SetFXPollInterval(config.FXPoll)
+	}
+	if config.StreamDisabled {
+		feed.SetStreamEnabled(false)
+	}
+	if keys == nil {
+		return
+	}
+	for _, provider := range keys.Providers() {
+		if err := feed.SetAPIKey(provider, keys.Key(provider)); err != nil {
+			log.Printf("settings: api key not applied provider=%s err=%v", provider, err)
+		}
+	}
+}
+
+// loadAPIKeys reads the keys of every provider that takes one from the OS
+// keychain. The store is usable even when loading fails; the error is for
+// the user, since a locked or missing keychain silently drops paid plans.
+func loadAPIKeys(feed feedConfigurator) (*apikeys.Store, error) {
+	path, err := apikeys.LegacyPath()
+	if err != nil {
+		log.Printf("apikeys: legacy file lookup skipped: %v", err)
+	}
+	var providers []string
+	for _, setting := range append(feed.MarketProviderSettings(), feed.FXProviderSettings()...) {
+		if setting.SupportsAPIKey {
+			providers = append(providers, setting.Name)
+		}
+	}
+	store := apikeys.New(path)
+	if err := store.Load(providers); err != nil {
+		log.Printf("apikeys: load failed err=%v", err)
+		return store, err
+	}
+	return store, nil
+}
+
+func newSecondsEntry(d time.Duration) *widget.Entry {
+	entry := widget.NewEntry()
+	entry.SetText(formatSeconds(d))
+	return entry
+}
+
+func formatSeconds(d time.Duration) string {
+	return strconv.FormatFloat(d.Seconds(), 'f', -1, 64)
+}
+
+func parseSeconds(t *i18n.Translator, label, raw string, min, max float64) (time.Duration, error) {
+	seconds, err := strconv.ParseFloat(strings.TrimSpace(strings.ReplaceAll(raw, ",", ".")), 64)
+	if err != nil || seconds < min || seconds > max {
+		return 0, fmt.Errorf(t.T("settings.error.number"), label, min, max)
+	}
+	return time.Duration(seconds * float64(time.Second)), nil
+}
@@ -0,0 +1,171 @@
+package ui
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"cryptoview/internal/service/apikeys"
+	"cryptoview/internal/service/marketfeed"
+	"cryptoview/internal/ui/i18n"
+	"fyne.io/fyne/v2/test"
+	"github.com/zalando/go-keyring"
+)
+
+type fakeConfigurator struct {
+	market, fx         []marketfeed.ProviderSetting
+	marketPoll, fxPoll time.Duration
+	keys               map[string]string
+	stream             *marketfeed.ProviderSetting
+}
+
+func (f *fakeConfigurator) MarketProviderSettings() []marketfeed.ProviderSetting {
+	return append([]marketfeed.ProviderSetting(nil), f.market...)
+}
+
+func (f *fakeConfigurator) FXProviderSettings() []marketfeed.ProviderSetting {
+	return append([]marketfeed.ProviderSetting(nil), f.fx...)
+}
+
+func (f *fakeConfigurator) SetMarketProviders(settings []marketfeed.ProviderSetting) error {
+	f.market = settings
+	return nil
+}
+
+func (f *fakeConfigurator) SetFXProviders(settings []marketfeed.ProviderSetting) error {
+	f.fx = settings
+	return nil
+}
+
+func (f *fakeConfigurator) PollIntervals() (time.Duration, time.Duration) {
+	return f.marketPoll, f.fxPoll
+}
+
+func (f *fakeConfigurator) SetMarketPollInterval(interval time.Duration) { f.marketPoll = interval }
+func (f *fakeConfigurator) SetFXPollInterval(interval time.Duration)     { f.fxPoll = interval }
+
+func (f *fakeConfigurator) SetAPIKey(provider, key string) error {
+	f.keys[provider] = key
+	return nil
+}
+
+func (f *fakeConfigurator) StreamSetting() (marketfeed.ProviderSetting, bool) {
+	if f.stream == nil {
+		return marketfeed.ProviderSetting{}, false
+	}
+	return *f.stream, true
+}
+
+func (f *fakeConfigurator) SetStreamEnabled(enabled bool) { f.stream.Enabled = enabled }
+
+func TestFeedSettingsDialogAppliesAndSaves(t *testing.T) {
+	a := test.NewApp()
+	defer a.Quit()
+	w := a.NewWindow("main")
+	defer w.Close()
+
+	feed := &fakeConfigurator{
+		market: []marketfeed.ProviderSetting{
+			{Name: "coingecko", Enabled: true, Timeout: time.Second, SupportsAPIKey: true},
+			{Name: "coinlore", Enabled: true, Timeout: 3 * time.Second},
+		},
+		fx:         []marketfeed.ProviderSetting{{Name: "open-er-api", Enabled: true, Timeout: time.Second}},
+		marketPoll: 60 * time.Second,
+		fxPoll:     time.Hour,
+		keys:       make(map[string]string),
+		stream:     &marketfeed.ProviderSetting{Name: "binance", Enabled: true},
+	}
+	keyring.MockInit()
+	keys := apikeys.New("")
+	store := newSettingsStore(a.Preferences())
+	d := showFeedSettingsDialog(w, i18n.NewTranslator(i18n.LangEN), feed, keys, store.SaveFeedConfig)
+	defer d.dialog.Hide()
+
+	d.move(&d.market, 1, 0)
+	d.market[1].Enabled = false
+	d.timeouts["coinlore"].SetText("30")
+	d.marketPoll.SetText("30")
+	d.keyEntries["coingecko"].SetText(" CG-key ")
+	if d.stream == nil || !d.stream.Checked || d.stream.Text != "Binance" {
+		t.Fatalf("expected an enabled Binance stream toggle, got %+v", d.stream)
+	}
+	d.stream.SetChecked(false)
+	if err := d.apply(); err != nil {
+		t.Fatalf("apply: %v", err)
+	}
+
+	wantMarket := []marketfeed.ProviderSetting{
+		{Name: "coinlore", Enabled: true, Timeout: 30 * time.Second},
+		{Name: "coingecko", Enabled: false, Timeout: time.Second, SupportsAPIKey: true},
+	}
+	if !reflect.DeepEqual(feed.market, wantMarket) {
+		t.Fatalf("unexpected market providers %+v", feed.market)
+	}
+	if feed.marketPoll != 30*time.Second || feed.fxPoll != time.Hour {
+		t.Fatalf("unexpected poll intervals %s/%s", feed.marketPoll, feed.fxPoll)
+	}
+	if feed.keys["coingecko"] != "CG-key" || keys.Key("coingecko") != "CG-key" {
+		t.Fatalf("expected key applied and stored, feed=%q store=%q", feed.keys["coingecko"], keys.Key("coingecko"))
+	}
+	if _, ok := feed.keys["coinlore"]; ok {
+		t.Fatal("expected no key for a provider without a paid tier")
+	}
+
+	saved := store.Load().Feed
+	if len(saved.Market) != 2 || saved.Market[0].Name != "coinlore" || saved.Market[1].Enabled || saved.Market[0].Timeout != 30*time.Second {
+		t.Fatalf("unexpected saved providers %+v", saved.Market)
+	}
+	if saved.MarketPoll != 30*time.Second || saved.FXPoll != time.Hour {
+		t.Fatalf("unexpected saved intervals %s/%s", saved.MarketPoll, saved.FXPoll)
+	}
+	if feed.stream.Enabled || !saved.StreamDisabled {
+		t.Fatalf("expected the stream turned off and saved, feed=%v saved=%v", feed.stream.Enabled, saved.StreamDisabled)
+	}
+	applyFeedConfig(feed, saved, nil)
+	if feed.stream.Enabled {
+		t.Fatal("expected the restored config to keep the stream off")
+	}
+
+	d.fxPoll.SetText("soon")
+	if err := d.apply(); err == nil || !strings.Contains(err.Error(), "Exchange rate refresh") {
+		t.Fatalf("expected a validation error naming the field, got %v", err)
+	}
+	d.fxPoll.SetText("3600")
+	d.market[0].Enabled = false
+	if err := d.apply(); err == nil {
+		t.Fatal("expected an error with every market provider disabled")
+	}
+	if !feed.market[0].Enabled {
+		t.Fatal("expected rejected settings to leave the feed unchanged")
+	}
+}
+
+func TestFeedSettingsDialogReportsKeychainFailures(t *testing.T) {
+	a := test.NewApp()
+	defer a.Quit()
+	w := a.NewWindow("main")
+	defer w.Close()
+
+	keyring.MockInitWithError(errors.New("keychain locked"))
+	feed := &fakeConfigurator{
+		market: []marketfeed.ProviderSetting{{Name: "coingecko", Enabled: true, Timeout: time.Second, SupportsAPIKey: true}},
+		fx:     []marketfeed.ProviderSetting{{Name: "open-er-api", Enabled: true, Timeout: time.Second}},
+		keys:   make(map[string]string),
+	}
+	saved := false
+	d := showFeedSettingsDialog(w, i18n.NewTranslator(i18n.LangEN), feed, apikeys.New(""), func(feedConfig) { saved = true })
+	defer d.dialog.Hide()
+
+	d.marketPoll.SetText("60")
+	d.fxPoll.SetText("3600")
+	d.keyEntries["coingecko"].SetText("CG-key")
+	err := d.apply()
+	if err == nil || !strings.Contains(err.Error(), "CoinGecko") || !strings.Contains(err.Error(), "keychain locked") {
+		t.Fatalf("expected the keychain failure to reach the user, got %v", err)
+	}
+	if !saved || feed.keys["coingecko"] != "CG-key" {
+		t.Fatalf("expected the other settings and the live key applied, saved=%v key=%q", saved, feed.keys["coingecko"])
+	}
+}
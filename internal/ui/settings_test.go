@@ -0,0 +1,116 @@
+package ui
+
+import (
+	"reflect"
+	"testing"
+
+	"cryptoview/internal/service/marketfeed"
+	"cryptoview/internal/ui/components"
+	"cryptoview/internal/ui/i18n"
+	uitheme "cryptoview/internal/ui/theme"
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/test"
+)
+
+func TestSettingsStoreRoundTripAndDefaults(t *testing.T) {
+	a := test.NewApp()
+	defer a.Quit()
+
+	store := newSettingsStore(a.Preferences())
+	if got := a.Preferences().Int(prefSettingsVersion); got != settingsVersion {
+		t.Fatalf("expected schema version %d after migration, got %d", settingsVersion, got)
+	}
+	if got := store.Load(); !reflect.DeepEqual(got, defaultSettings()) {
+		t.Fatalf("expected defaults on first launch, got %+v", got)
+	}
+
+	store.SaveFiat(i18n.FiatGBP)
+	store.SaveLanguage(i18n.LangRU)
+	store.SaveTheme(uitheme.ModeDark)
+	store.SaveTracked([]string{"bitcoin", "solana"})
+	store.SaveSort(components.SortChange)
+	store.SaveWindowSize(fyne.NewSize(620, 700))
+
+	want := appSettings{
+		Fiat:     i18n.FiatGBP,
+		Language: i18n.LangRU,
+		Theme:    uitheme.ModeDark,
+		Tracked:  []string{"bitcoin", "solana"},
+		Sort:     components.SortChange,
+		Window:   fyne.NewSize(620, 700),
+	}
+	if got := newSettingsStore(a.Preferences()).Load(); !reflect.DeepEqual(got, want) {
+		t.Fatalf("expected %+v, got %+v", want, got)
+	}
+
+	a.Preferences().SetString(prefFiat, "XYZ")
+	a.Preferences().SetString(prefTheme, "sepia")
+	if got := store.Load(); got.Fiat != i18n.FiatUSD || got.Theme != uitheme.ModeSystem || got.Language != i18n.LangRU {
+		t.Fatalf("expected invalid values to fall back individually, got %+v", got)
+	}
+}
+
+func TestSettingsStoreKeepsNewerSchema(t *testing.T) {
+	a := test.NewApp()
+	defer a.Quit()
+
+	if len(settingsMigrations) != settingsVersion {
+		t.Fatalf("expected one migration per schema version, got %d for version %d", len(settingsMigrations), settingsVersion)
+	}
+	a.Preferences().SetInt(prefSettingsVersion, settingsVersion+1)
+	a.Preferences().SetString(prefFiat, "EUR")
+
+	settings := newSettingsStore(a.Preferences()).Load()
+	if got := a.Preferences().Int(prefSettingsVersion); got != settingsVersion+1 {
+		t.Fatalf("expected a newer schema version to be left alone, got %d", got)
+	}
+	if settings.Fiat != i18n.FiatEUR {
+		t.Fatalf("expected known keys to be read from a newer schema, got %+v", settings)
+	}
+}
+
+func TestBuildMainWindowRestoresAndSavesSettings(t *testing.T) {
+	a := test.NewApp()
+	defer a.Quit()
+
+	store := newSettingsStore(a.Preferences())
+	store.SaveFiat(i18n.FiatEUR)
+	store.SaveLanguage(i18n.LangRU)
+	store.SaveTheme(uitheme.ModeDark)
+	store.SaveTracked([]string{"ethereum"})
+	store.SaveSort(components.SortName)
+
+	var feed *fakeFeed
+	w := buildMainWindowWithFeedFactory(a, nil, func(callbacks marketfeed.Callbacks) marketFeed {
+		feed = newFakeFeed(callbacks)
+		return feed
+	})
+	fyne.DoAndWait(func() {})
+
+	if feed.lastFiat != i18n.FiatEUR {
+		t.Fatalf("expected restored fiat EUR, got %q", feed.lastFiat)
+	}
+	if len(feed.tracked) != 1 || feed.tracked[0].ID != "ethereum" {
+		t.Fatalf("expected restored watchlist, got %+v", feed.tracked)
+	}
+	if w.Title() != i18n.NewTranslator(i18n.LangRU).T("app.title") {
+		t.Fatalf("expected RU window title, got %q", w.Title())
+	}
+
+	langSelect := findLanguageSelect(w.Content())
+	langSelect.SetSelected("EN")
+	feed.tracked = []marketfeed.CoinRef{{ID: "bitcoin"}, {ID: "solana"}}
+	w.Resize(fyne.NewSize(500, 600))
+	w.Close()
+
+	saved := store.Load()
+	if saved.Language != i18n.LangEN || saved.Theme != uitheme.ModeDark || saved.Sort != components.SortName {
+		t.Fatalf("unexpected saved settings %+v", saved)
+	}
+	if !reflect.DeepEqual(saved.Tracked, []string{"bitcoin", "solana"}) {
+		t.Fatalf("expected watchlist saved on close, got %+v", saved.Tracked)
+	}
+	if saved.Window.Width != 500 || saved.Window.Height != 600 {
+		t.Fatalf("expected window size saved on close, got %+v", saved.Window)
+	}
+}
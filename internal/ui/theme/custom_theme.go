@@ -2,6 +2,7 @@ package uitheme
 
 import (
 	"image/color"
+	"strings"
 
 	"fyne.io/fyne/v2"
 	"fyne.io/fyne/v2/theme"
@@ -15,6 +16,15 @@ const (
 	ModeLight  Mode = "light"
 )
 
+func ParseMode(raw string) (Mode, bool) {
+	switch mode := Mode(strings.ToLower(strings.TrimSpace(raw))); mode {
+	case ModeSystem, ModeDark, ModeLight:
+		return mode, true
+	default:
+		return "", false
+	}
+}
+
 type CustomTheme struct {
 	base   fyne.Theme
 	mode   Mode